@@ -58,7 +58,8 @@ func NewBasicAuthHandler(users *resource.Resource) func(next http.Handler) http.
 					}
 					return
 				}
-				if schema.VerifyPassword(user.Payload["password"], []byte(p)) {
+				hash, _ := user.Payload["password"].([]byte)
+				if (schema.Password{}).Compare(string(hash), p) {
 					// Store the auth user into the context for later use
 					r = r.WithContext(NewContextWithUser(ctx, user))
 					next.ServeHTTP(w, r)
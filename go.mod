@@ -1,5 +1,7 @@
 module github.com/rs/rest-layer
 
+go 1.17
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/evanphx/json-patch v4.1.0+incompatible
@@ -9,4 +11,5 @@ require (
 	github.com/rs/xid v1.2.1
 	github.com/stretchr/testify v1.2.2
 	golang.org/x/crypto v0.0.0-20181127143415-eb0de9b17e85
+	golang.org/x/text v0.13.0
 )
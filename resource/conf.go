@@ -21,6 +21,11 @@ type Conf struct {
 	//
 	// TotalDenied prevents the user from requesting the total.
 	ForceTotal ForceTotalMode
+	// AllowUnindexedFilters accepts filters and sorts on the fields which are
+	// not flagged as Filterable or Sortable. It is intended for development:
+	// on large collections, such queries may require full scans of the
+	// storage.
+	AllowUnindexedFilters bool
 }
 
 // ForceTotalMode defines Conf.ForceTotal modes.
@@ -1,6 +1,9 @@
 package resource
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrNotFound is returned when the requested resource can't be found.
@@ -19,3 +22,19 @@ var (
 	// resource.
 	ErrNoStorage = errors.New("No Storage Defined")
 )
+
+// DeleteError is returned by Delete and Clear when the delete hooks of the
+// resource schema reject the deletion of an item (see
+// schema.Schema.ValidateDelete).
+type DeleteError struct {
+	// ID is the id of the rejected item.
+	ID interface{}
+	// Errs holds the errors of the hooks by field, the errors on the whole
+	// document being stored under the "" key.
+	Errs map[string][]interface{}
+}
+
+// Error implements the error interface.
+func (e *DeleteError) Error() string {
+	return fmt.Sprintf("Item %v cannot be deleted", e.ID)
+}
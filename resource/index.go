@@ -114,27 +114,81 @@ func (rc refChecker) ReferenceChecker(path string) (schema.FieldValidator, schem
 	if !exists {
 		return nil, nil
 	}
-	validator := rsc.Schema().Fields["id"].Validator
+	return refValidator{rsc, rsc.Schema().Fields["id"].Validator}, rsc.Validator()
+}
 
-	return schema.FieldValidatorFunc(func(value interface{}) (interface{}, error) {
-		var id interface{}
-		var err error
+// refValidator validates the IDs of the items of a resource and checks that the
+// items exist.
+type refValidator struct {
+	rsc       *Resource
+	validator schema.FieldValidator
+}
 
-		if validator != nil {
-			id, err = validator.Validate(value)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			id = value
+// Validate implements the schema.FieldValidator interface.
+func (v refValidator) Validate(value interface{}) (interface{}, error) {
+	return v.ValidateCtx(context.Background(), value)
+}
+
+// ValidateCtx implements the schema.FieldValidatorCtx interface.
+func (v refValidator) ValidateCtx(ctx context.Context, value interface{}) (interface{}, error) {
+	id, err := v.validateID(value)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = v.rsc.Get(ctx, id); err != nil {
+		if err == ErrNotFound {
+			err = v.notFound(id)
 		}
+		return nil, err
+	}
+	return id, nil
+}
+
+// AcceptsJSONNumber implements the schema.FieldNumberValidator interface.
+func (v refValidator) AcceptsJSONNumber() bool {
+	n, ok := v.validator.(schema.FieldNumberValidator)
+	return ok && n.AcceptsJSONNumber()
+}
 
-		_, err = rsc.Get(context.TODO(), id)
+// ValidateBatch implements the schema.FieldBatchValidator interface. The
+// existence of the items is checked with a single MultiGet.
+func (v refValidator) ValidateBatch(ctx context.Context, values []interface{}) ([]interface{}, []error) {
+	ids := make([]interface{}, len(values))
+	errs := make([]error, len(values))
+	var batch []interface{}
+	for i, value := range values {
+		if ids[i], errs[i] = v.validateID(value); errs[i] == nil {
+			batch = append(batch, ids[i])
+		}
+	}
+	if len(batch) == 0 {
+		return ids, errs
+	}
+	items, err := v.rsc.MultiGet(ctx, batch)
+	j := 0
+	for i := range values {
+		if errs[i] != nil {
+			continue
+		}
 		if err != nil {
-			return nil, err
+			errs[i] = err
+		} else if j >= len(items) || items[j] == nil {
+			errs[i] = v.notFound(ids[i])
 		}
-		return id, nil
-	}), rsc.Validator()
+		j++
+	}
+	return ids, errs
+}
+
+func (v refValidator) validateID(value interface{}) (interface{}, error) {
+	if v.validator == nil {
+		return value, nil
+	}
+	return v.validator.Validate(value)
+}
+
+func (v refValidator) notFound(id interface{}) error {
+	return fmt.Errorf("%s item '%v' not found", v.rsc.Path(), id)
 }
 
 // assertNotBound asserts a given resource name is not already bound.
@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"context"
 	"io/ioutil"
 	"log"
 	"testing"
@@ -77,6 +78,66 @@ func TestIndexCompileReferenceChecker(t *testing.T) {
 	assert.NoError(t, i.Compile())
 }
 
+func TestIndexReferenceCheckerExistence(t *testing.T) {
+	var multiGets int
+	s := newTestMStorer()
+	s.multiGet = func(ctx context.Context, ids []interface{}) ([]*Item, error) {
+		multiGets++
+		var items []*Item
+		for _, id := range ids {
+			if id != "missing" {
+				items = append(items, &Item{ID: id})
+			}
+		}
+		return items, nil
+	}
+	i := NewIndex()
+	i.Bind("users", schema.Schema{Fields: schema.Fields{"id": {}}}, s, DefaultConf)
+	v, _ := refChecker{i}.ReferenceChecker("users")
+
+	t.Run("Single", func(t *testing.T) {
+		id, err := schema.ValidateField(context.Background(), v, "u1")
+		assert.NoError(t, err)
+		assert.Equal(t, "u1", id)
+		_, err = schema.ValidateField(context.Background(), v, "missing")
+		assert.EqualError(t, err, "users item 'missing' not found")
+	})
+	t.Run("Batch", func(t *testing.T) {
+		multiGets = 0
+		ids, errs := v.(schema.FieldBatchValidator).ValidateBatch(context.Background(), []interface{}{"u1", "missing", "u2"})
+		assert.Equal(t, 1, multiGets)
+		assert.Equal(t, []interface{}{"u1", "missing", "u2"}, ids)
+		assert.NoError(t, errs[0])
+		assert.EqualError(t, errs[1], "users item 'missing' not found")
+		assert.NoError(t, errs[2])
+	})
+	t.Run("Array", func(t *testing.T) {
+		multiGets = 0
+		ref := &schema.Reference{Path: "users"}
+		assert.NoError(t, ref.Compile(refChecker{i}))
+		a := schema.Array{Values: schema.Field{Validator: ref}}
+		_, err := a.Validate([]interface{}{"u1", "missing", "u2"})
+		assert.EqualError(t, err, "invalid value at #2: users item 'missing' not found")
+		assert.Equal(t, 1, multiGets)
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := schema.ValidateField(ctx, &schema.Reference{Path: "users"}, "u1")
+		assert.Error(t, err)
+	})
+	t.Run("JSONNumber", func(t *testing.T) {
+		i := NewIndex()
+		i.Bind("users", schema.Schema{Fields: schema.Fields{"id": {}}}, s, DefaultConf)
+		i.Bind("counters", schema.Schema{Fields: schema.Fields{"id": {Validator: &schema.Integer{Strict: true}}}}, s, DefaultConf)
+		for path, accepts := range map[string]bool{"users": false, "counters": true} {
+			ref := &schema.Reference{Path: path}
+			assert.NoError(t, ref.Compile(refChecker{i}))
+			assert.Equal(t, accepts, ref.AcceptsJSONNumber(), path)
+		}
+	})
+}
+
 func TestIndexCompileReferenceCheckerError(t *testing.T) {
 	i, ok := NewIndex().(*index)
 	if !assert.True(t, ok) {
@@ -67,6 +67,25 @@ func (v validatorFallback) GetField(name string) *schema.Field {
 	return v.fallback.GetField(name)
 }
 
+// ValidateCtx implements schema.ValidatorCtx, falling back on Validate when
+// the wrapped validator doesn't implement it.
+func (v validatorFallback) ValidateCtx(ctx context.Context, changes map[string]interface{}, base map[string]interface{}) (map[string]interface{}, map[string][]interface{}) {
+	if vc, ok := v.Validator.(schema.ValidatorCtx); ok {
+		return vc.ValidateCtx(ctx, changes, base)
+	}
+	return v.Validator.Validate(changes, base)
+}
+
+// ValidateWithWarningsCtx implements schema.ValidatorWarnings. No warning is
+// reported when the wrapped validator doesn't implement it.
+func (v validatorFallback) ValidateWithWarningsCtx(ctx context.Context, changes map[string]interface{}, base map[string]interface{}) (map[string]interface{}, map[string][]interface{}, map[string][]interface{}) {
+	if vw, ok := v.Validator.(schema.ValidatorWarnings); ok {
+		return vw.ValidateWithWarningsCtx(ctx, changes, base)
+	}
+	doc, errs := v.ValidateCtx(ctx, changes, base)
+	return doc, errs, nil
+}
+
 // newResource creates a new resource with provided spec, handler and config.
 func newResource(name string, s schema.Schema, h Storer, c Conf) *Resource {
 	return &Resource{
@@ -410,7 +429,11 @@ func (r *Resource) Delete(ctx context.Context, item *Item) (err error) {
 		}(time.Now())
 	}
 	if err = r.hooks.onDelete(ctx, item); err == nil {
-		err = r.storage.Delete(ctx, item)
+		if errs := r.schema.ValidateDelete(ctx, item.Payload); len(errs) > 0 {
+			err = &DeleteError{ID: item.ID, Errs: errs}
+		} else {
+			err = r.storage.Delete(ctx, item)
+		}
 	}
 	r.hooks.onDeleted(ctx, item, &err)
 	return
@@ -428,8 +451,84 @@ func (r *Resource) Clear(ctx context.Context, q *query.Query) (deleted int, err
 		}(time.Now())
 	}
 	if err = r.hooks.onClear(ctx, q); err == nil {
-		deleted, err = r.storage.Clear(ctx, q)
+		if r.schema.HasDeleteHooks() {
+			deleted, err = r.clearWithHooks(ctx, q)
+		} else {
+			deleted, err = r.storage.Clear(ctx, q)
+		}
 	}
 	r.hooks.onCleared(ctx, q, &deleted, &err)
 	return
 }
+
+// clearPageSize is the number of items loaded at once by clearWithHooks.
+const clearPageSize = 100
+
+// clearWithHooks deletes the items matching q one by one, loading them by pages
+// of clearPageSize items. The delete hooks of the schema are checked on all
+// the items before the first one is deleted, so nothing is deleted if an item
+// is rejected. Clearing is not atomic though: the items are checked again
+// before being deleted, and if an item created or changed in the meantime is
+// rejected, the items already deleted are not restored.
+func (r *Resource) clearWithHooks(ctx context.Context, q *query.Query) (deleted int, err error) {
+	offset, limit := 0, -1
+	if q.Window != nil {
+		offset, limit = q.Window.Offset, q.Window.Limit
+	}
+	for o, n := offset, limit; ; {
+		items, err := r.findClearPage(ctx, q, o, n)
+		if err != nil {
+			return 0, err
+		}
+		if len(items) == 0 {
+			break
+		}
+		for _, item := range items {
+			if errs := r.schema.ValidateDelete(ctx, item.Payload); len(errs) > 0 {
+				return 0, &DeleteError{ID: item.ID, Errs: errs}
+			}
+		}
+		o += len(items)
+		if n > 0 {
+			n -= len(items)
+		}
+	}
+	// Deleted items are removed from the result set, so each page starts at
+	// the window offset.
+	for n := limit; ; {
+		var items []*Item
+		if items, err = r.findClearPage(ctx, q, offset, n); err != nil || len(items) == 0 {
+			return deleted, err
+		}
+		for _, item := range items {
+			if errs := r.schema.ValidateDelete(ctx, item.Payload); len(errs) > 0 {
+				return deleted, &DeleteError{ID: item.ID, Errs: errs}
+			}
+			if err = r.storage.Delete(ctx, item); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+		if n > 0 {
+			n -= len(items)
+		}
+	}
+}
+
+// findClearPage returns the page of at most clearPageSize items matching q
+// starting at offset, limited to limit items if limit is positive.
+func (r *Resource) findClearPage(ctx context.Context, q *query.Query, offset, limit int) ([]*Item, error) {
+	if limit == 0 {
+		return nil, nil
+	}
+	pq := *q
+	pq.Window = &query.Window{Offset: offset, Limit: clearPageSize}
+	if limit > 0 && limit < clearPageSize {
+		pq.Window.Limit = limit
+	}
+	list, err := r.storage.Find(ctx, &pq)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
@@ -929,3 +929,105 @@ func TestResourceClearPostHookError(t *testing.T) {
 	assert.True(t, handler)
 	assert.True(t, postHook)
 }
+
+// newClearTestStorer returns a storer holding n items, with IDs from 0 to n-1,
+// whose Find honors the query window.
+func newClearTestStorer(n int) (*testMStorer, *[]*Item) {
+	items := make([]*Item, n)
+	for i := range items {
+		items[i] = &Item{ID: i, Payload: map[string]interface{}{"id": i}}
+	}
+	s := newTestMStorer()
+	s.find = func(ctx context.Context, q *query.Query) (*ItemList, error) {
+		res := items
+		if q.Window != nil {
+			if q.Window.Offset >= len(res) {
+				res = nil
+			} else {
+				res = res[q.Window.Offset:]
+			}
+			if q.Window.Limit >= 0 && q.Window.Limit < len(res) {
+				res = res[:q.Window.Limit]
+			}
+		}
+		return &ItemList{Total: -1, Items: append([]*Item(nil), res...)}, nil
+	}
+	s.delete = func(ctx context.Context, item *Item) error {
+		for i, it := range items {
+			if it.ID == item.ID {
+				items = append(items[:i], items[i+1:]...)
+				return nil
+			}
+		}
+		return ErrNotFound
+	}
+	return s, &items
+}
+
+func TestResourceClearWithHooks(t *testing.T) {
+	clearSchema := schema.Schema{
+		Fields: schema.Fields{"id": {}, "locked": {}},
+		OnDelete: func(ctx context.Context, doc map[string]interface{}) error {
+			if doc["locked"] == true {
+				return errors.New("locked")
+			}
+			return nil
+		},
+	}
+	ctx := context.Background()
+
+	t.Run("Pages", func(t *testing.T) {
+		s, items := newClearTestStorer(250)
+		find := s.find
+		s.find = func(ctx context.Context, q *query.Query) (*ItemList, error) {
+			if q.Window == nil || q.Window.Limit > clearPageSize {
+				t.Errorf("Find() window = %v, want at most %d items", q.Window, clearPageSize)
+			}
+			return find(ctx, q)
+		}
+		r := NewIndex().Bind("foo", clearSchema, s, DefaultConf)
+		deleted, err := r.Clear(ctx, &query.Query{})
+		assert.NoError(t, err)
+		assert.Equal(t, 250, deleted)
+		assert.Len(t, *items, 0)
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		s, items := newClearTestStorer(250)
+		r := NewIndex().Bind("foo", clearSchema, s, DefaultConf)
+		deleted, err := r.Clear(ctx, &query.Query{Window: &query.Window{Offset: 10, Limit: 150}})
+		assert.NoError(t, err)
+		assert.Equal(t, 150, deleted)
+		if assert.Len(t, *items, 100) {
+			assert.Equal(t, 9, (*items)[9].ID)
+			assert.Equal(t, 160, (*items)[10].ID)
+		}
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		// Nothing is deleted when an item of the last page is rejected.
+		s, items := newClearTestStorer(250)
+		(*items)[240].Payload["locked"] = true
+		r := NewIndex().Bind("foo", clearSchema, s, DefaultConf)
+		deleted, err := r.Clear(ctx, &query.Query{})
+		assert.EqualError(t, err, "Item 240 cannot be deleted")
+		assert.Equal(t, 0, deleted)
+		assert.Len(t, *items, 250)
+	})
+
+	t.Run("NotAtomic", func(t *testing.T) {
+		// An item locked once the first item is deleted stops the clear, and
+		// the deleted items are not restored.
+		s, items := newClearTestStorer(250)
+		del := s.delete
+		s.delete = func(ctx context.Context, item *Item) error {
+			(*items)[len(*items)-1].Payload["locked"] = true
+			return del(ctx, item)
+		}
+		r := NewIndex().Bind("foo", clearSchema, s, DefaultConf)
+		deleted, err := r.Clear(ctx, &query.Query{})
+		assert.EqualError(t, err, "Item 249 cannot be deleted")
+		assert.Equal(t, 249, deleted)
+		assert.Len(t, *items, 1)
+	})
+}
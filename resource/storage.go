@@ -22,7 +22,10 @@ type Storer interface {
 	//
 	// A storer must ignore the Projection part of the query and always return
 	// the document in its entirety. Documents matching a given predicate might
-	// be reused (i.e.: cached) with a different projection.
+	// be reused (i.e.: cached) with a different projection. The fields listed
+	// in the Omit part of the query may however be left out of the returned
+	// documents, i.e. to avoid fetching large fields the response doesn't
+	// include. Predicate and Sort must still be applied on the whole document.
 	//
 	// If the fetching of the data is not immediate, the method must listen for
 	// cancellation on the passed ctx. If the operation is stopped due to
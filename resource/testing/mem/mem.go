@@ -177,6 +177,14 @@ func (m *MemoryHandler) Find(ctx context.Context, q *query.Query) (list *resourc
 
 	err = handleWithLatency(m.Latency, ctx, func() error {
 		list, err = m.find(ctx, q)
+		if err == nil {
+			// Items are decoded on each fetch, their payload can be changed.
+			for _, item := range list.Items {
+				for _, field := range q.Omit {
+					delete(item.Payload, field)
+				}
+			}
+		}
 		return err
 	})
 	return list, err
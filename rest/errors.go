@@ -51,6 +51,9 @@ func NewError(err error) *Error {
 	if Err, ok := err.(*Error); ok {
 		return Err
 	}
+	if de, ok := err.(*resource.DeleteError); ok {
+		return &Error{422, de.Error(), de.Errs}
+	}
 	switch err {
 	case context.Canceled:
 		return ErrClientClosedRequest
@@ -2,7 +2,9 @@ package rest_test
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/rs/rest-layer/resource"
@@ -160,3 +162,114 @@ func TestDeleteList(t *testing.T) {
 		t.Run(n, tc.Test)
 	}
 }
+
+func TestDeleteHooks(t *testing.T) {
+	sharedInit := func() *requestTestVars {
+		s := mem.NewHandler()
+		s.Insert(context.Background(), []*resource.Item{
+			{ID: "1", Payload: map[string]interface{}{"id": "1", "foo": "odd"}},
+			{ID: "2", Payload: map[string]interface{}{"id": "2", "foo": "even", "meta": map[string]interface{}{"locked": true}}},
+			{ID: "3", Payload: map[string]interface{}{"id": "3", "foo": "odd"}},
+			{ID: "4", Payload: map[string]interface{}{"id": "4", "foo": "archived"}},
+		})
+
+		idx := resource.NewIndex()
+		idx.Bind("foo", schema.Schema{
+			Fields: schema.Fields{
+				"id": {Sortable: true, Filterable: true},
+				"foo": {
+					Filterable: true,
+					OnDelete: func(ctx context.Context, value interface{}) error {
+						if value == "archived" {
+							return errors.New("archived")
+						}
+						return nil
+					},
+				},
+				"meta": {Schema: &schema.Schema{
+					Fields: schema.Fields{"locked": {}},
+					OnDelete: func(ctx context.Context, doc map[string]interface{}) error {
+						if doc["locked"] == true {
+							return errors.New("locked")
+						}
+						return nil
+					},
+				}},
+			},
+		}, s, resource.Conf{AllowedModes: resource.ReadWrite})
+
+		return &requestTestVars{
+			Index:   idx,
+			Storers: map[string]resource.Storer{"foo": s},
+		}
+	}
+	checkFooIDs := func(ids ...interface{}) requestCheckerFunc {
+		return func(t *testing.T, vars *requestTestVars) {
+			items, err := vars.Storers["foo"].Find(context.Background(), &query.Query{Sort: query.Sort{{Name: "id"}}})
+			if err != nil {
+				t.Errorf("s.Find failed: %s", err)
+				return
+			}
+			var actual []interface{}
+			for _, item := range items.Items {
+				actual = append(actual, item.ID)
+			}
+			if !reflect.DeepEqual(actual, ids) {
+				t.Errorf("Expected resource 'foo' to contain %v, got %v", ids, actual)
+			}
+		}
+	}
+
+	tests := map[string]requestTest{
+		`list:accepted`: {
+			Init: sharedInit,
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("DELETE", `/foo?filter={foo:"odd"}`, nil)
+			},
+			ResponseCode:   http.StatusNoContent,
+			ResponseBody:   ``,
+			ResponseHeader: http.Header{"X-Total": []string{"2"}},
+			ExtraTest:      checkFooIDs("2", "4"),
+		},
+		`list:rejected`: {
+			Init: sharedInit,
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("DELETE", `/foo?filter={foo:{$in:["odd","archived"]}}`, nil)
+			},
+			ResponseCode: http.StatusUnprocessableEntity,
+			ResponseBody: `{
+				"code": 422,
+				"message": "Item 4 cannot be deleted",
+				"issues": {"foo": ["archived"]}
+			}`,
+			ExtraTest: checkFooIDs("1", "2", "3", "4"),
+		},
+		`item:rejected`: {
+			Init: sharedInit,
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("DELETE", `/foo/2`, nil)
+			},
+			ResponseCode: http.StatusUnprocessableEntity,
+			ResponseBody: `{
+				"code": 422,
+				"message": "Item 2 cannot be deleted",
+				"issues": {"meta": [{"": ["locked"]}]}
+			}`,
+			ExtraTest: checkFooIDs("1", "2", "3", "4"),
+		},
+		`item:accepted`: {
+			Init: sharedInit,
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("DELETE", `/foo/3`, nil)
+			},
+			ResponseCode: http.StatusNoContent,
+			ResponseBody: ``,
+			ExtraTest:    checkFooIDs("1", "2", "4"),
+		},
+	}
+
+	for n, tc := range tests {
+		tc := tc // capture range variable
+		t.Run(n, tc.Test)
+	}
+}
@@ -28,6 +28,8 @@ func listGet(ctx context.Context, r *http.Request, route *RouteMatch) (status in
 	if e != nil {
 		return e.Code, nil, e
 	}
+	// Let the storage skip the fields excluded from the projection.
+	q.Omit = q.Projection.OmittedFields(rsc.Schema())
 	var list *resource.ItemList
 	var err error
 	if forceTotal {
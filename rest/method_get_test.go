@@ -3,12 +3,14 @@ package rest_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
 	"github.com/rs/rest-layer/resource"
 	"github.com/rs/rest-layer/resource/testing/mem"
 	"github.com/rs/rest-layer/schema"
+	"github.com/rs/rest-layer/schema/query"
 )
 
 func TestGetListInvalidQuery(t *testing.T) {
@@ -344,6 +346,146 @@ func TestGetListFilter(t *testing.T) {
 	}
 }
 
+func TestGetListUnindexedFilter(t *testing.T) {
+	init := func(allow bool) func() *requestTestVars {
+		return func() *requestTestVars {
+			s := mem.NewHandler()
+			s.Insert(context.TODO(), []*resource.Item{
+				{ID: "1", Payload: map[string]interface{}{"id": 1, "foo": "a", "bar": "b", "sub": map[string]interface{}{"baz": "c"}}},
+				{ID: "2", Payload: map[string]interface{}{"id": 2, "foo": "a", "bar": "x", "sub": map[string]interface{}{"baz": "y"}}},
+			})
+			idx := resource.NewIndex()
+			idx.Bind("foo", schema.Schema{
+				Fields: schema.Fields{
+					"id":  {Filterable: true, Sortable: true},
+					"foo": {Filterable: true},
+					"bar": {},
+					"sub": {Schema: &schema.Schema{Fields: schema.Fields{"baz": {}}}},
+				},
+			}, s, resource.Conf{AllowedModes: resource.ReadWrite, AllowUnindexedFilters: allow})
+			return &requestTestVars{
+				Index:   idx,
+				Storers: map[string]resource.Storer{"foo": s},
+			}
+		}
+	}
+	tests := map[string]requestTest{
+		"Rejected": {
+			Init: init(false),
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("GET", `/foo?filter={foo:"a",$or:[{bar:"b"},{"sub.baz":"y"}]}&sort=bar,-id`, nil)
+			},
+			ResponseCode: 422,
+			ResponseBody: `{
+				"code": 422,
+				"message": "URL parameters contain error(s)",
+				"issues": {
+					"filter": ["bar, sub.baz: fields are not filterable"],
+					"sort": ["bar: field is not sortable"]
+				}
+			}`,
+		},
+		"Allowed": {
+			Init: init(true),
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("GET", `/foo?filter={foo:"a",$or:[{bar:"b"},{"sub.baz":"y"}]}&sort=-bar`, nil)
+			},
+			ResponseCode: 200,
+			ResponseBody: `[
+				{"id":2,"foo":"a","bar":"x","sub":{"baz":"y"}},
+				{"id":1,"foo":"a","bar":"b","sub":{"baz":"c"}}
+			]`,
+		},
+	}
+	for n, tc := range tests {
+		tc := tc // capture range variable
+		t.Run(n, tc.Test)
+	}
+}
+
+func TestGetListNoDefaultProjection(t *testing.T) {
+	sharedInit := func() *requestTestVars {
+		s := mem.NewHandler()
+		s.Insert(context.TODO(), []*resource.Item{
+			{ID: "1", Payload: map[string]interface{}{"id": "1", "title": "a", "body": "large a", "meta": map[string]interface{}{"size": 7, "raw": "x"}}},
+			{ID: "2", Payload: map[string]interface{}{"id": "2", "title": "b", "body": "large b", "meta": map[string]interface{}{"size": 7, "raw": "y"}}},
+		})
+		idx := resource.NewIndex()
+		rsc := idx.Bind("foo", schema.Schema{
+			Fields: schema.Fields{
+				"id":    {Sortable: true},
+				"title": {},
+				"body":  {NoDefaultProjection: true},
+				"meta": {Schema: &schema.Schema{Fields: schema.Fields{
+					"size": {},
+					"raw":  {NoDefaultProjection: true},
+				}}},
+			},
+		}, s, resource.DefaultConf)
+		// Ensure the storage doesn't return the omitted fields.
+		rsc.Use(resource.FoundEventHandlerFunc(func(ctx context.Context, q *query.Query, list **resource.ItemList, err *error) {
+			for _, item := range (*list).Items {
+				for _, field := range q.Omit {
+					if _, found := item.Payload[field]; found {
+						*err = fmt.Errorf("omitted field %s returned by the storage", field)
+					}
+				}
+			}
+		}))
+		return &requestTestVars{
+			Index:   idx,
+			Storers: map[string]resource.Storer{"foo": s},
+		}
+	}
+	tests := map[string]requestTest{
+		"Default": {
+			Init: sharedInit,
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("GET", "/foo?sort=id", nil)
+			},
+			ResponseCode: 200,
+			ResponseBody: `[
+				{"id":"1","title":"a","meta":{"size":7}},
+				{"id":"2","title":"b","meta":{"size":7}}
+			]`,
+		},
+		"Star": {
+			Init: sharedInit,
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("GET", "/foo?sort=id&fields=*", nil)
+			},
+			ResponseCode: 200,
+			ResponseBody: `[
+				{"id":"1","title":"a","meta":{"size":7}},
+				{"id":"2","title":"b","meta":{"size":7}}
+			]`,
+		},
+		"Explicit": {
+			Init: sharedInit,
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("GET", "/foo?sort=id&fields=id,body,meta{raw}", nil)
+			},
+			ResponseCode: 200,
+			ResponseBody: `[
+				{"id":"1","body":"large a","meta":{"raw":"x"}},
+				{"id":"2","body":"large b","meta":{"raw":"y"}}
+			]`,
+		},
+		"Item": {
+			Init: sharedInit,
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("GET", "/foo/2", nil)
+			},
+			ResponseCode: 200,
+			ResponseBody: `{"id":"2","title":"b","meta":{"size":7}}`,
+		},
+	}
+	for n, tc := range tests {
+		tc := tc // capture range variable
+		t.Run(n, tc.Test)
+	}
+}
+
 func TestGetListArray(t *testing.T) {
 	sharedInit := func() *requestTestVars {
 		s := mem.NewHandler()
@@ -16,6 +16,8 @@ func itemGet(ctx context.Context, r *http.Request, route *RouteMatch) (status in
 	}
 	rsrc := route.Resource()
 	q.Window = &query.Window{Limit: 1}
+	// Let the storage skip the fields excluded from the projection.
+	q.Omit = q.Projection.OmittedFields(rsrc.Schema())
 	list, err := rsrc.Find(ctx, q)
 	if err != nil {
 		e = NewError(err)
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io/ioutil"
@@ -74,8 +75,9 @@ func itemPatch(ctx context.Context, r *http.Request, route *RouteMatch) (status
 		if err != nil {
 			return 422, nil, &Error{422, err.Error(), nil}
 		}
-		err = json.Unmarshal(payloadJSON, &payload)
-		if err != nil {
+		decoder := json.NewDecoder(bytes.NewReader(payloadJSON))
+		decoder.UseNumber()
+		if err = decoder.Decode(&payload); err != nil {
 			return 422, nil, &Error{422, err.Error(), nil}
 		}
 	}
@@ -87,7 +89,7 @@ func itemPatch(ctx context.Context, r *http.Request, route *RouteMatch) (status
 	for k, v := range route.ResourcePath.Values() {
 		base[k] = v
 	}
-	doc, errs := rsrc.Validator().Validate(changes, base)
+	doc, errs, warnings := validatePayload(ctx, rsrc.Validator(), changes, base)
 	if len(errs) > 0 {
 		return 422, nil, &Error{422, "Document contains error(s)", errs}
 	}
@@ -115,5 +117,5 @@ func itemPatch(ctx context.Context, r *http.Request, route *RouteMatch) (status
 		e = NewError(err)
 		return e.Code, nil, e
 	}
-	return 200, nil, item
+	return 200, setWarningHeaders(nil, warnings), item
 }
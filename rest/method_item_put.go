@@ -68,7 +68,7 @@ func itemPut(ctx context.Context, r *http.Request, route *RouteMatch) (status in
 			delete(changes, k)
 		}
 	}
-	doc, errs := rsrc.Validator().Validate(changes, base)
+	doc, errs, warnings := validatePayload(ctx, rsrc.Validator(), changes, base)
 	if len(errs) > 0 {
 		return 422, nil, &Error{422, "Document contains error(s)", errs}
 	}
@@ -103,5 +103,5 @@ func itemPut(ctx context.Context, r *http.Request, route *RouteMatch) (status in
 		e = NewError(err)
 		return e.Code, nil, e
 	}
-	return status, nil, item
+	return status, setWarningHeaders(nil, warnings), item
 }
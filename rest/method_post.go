@@ -26,7 +26,7 @@ func listPost(ctx context.Context, r *http.Request, route *RouteMatch) (status i
 	for k, v := range route.ResourcePath.Values() {
 		base[k] = v
 	}
-	doc, errs := rsrc.Validator().Validate(changes, base)
+	doc, errs, warnings := validatePayload(ctx, rsrc.Validator(), changes, base)
 	if len(errs) > 0 {
 		return 422, nil, &Error{422, "Document contains error(s)", errs}
 	}
@@ -47,7 +47,7 @@ func listPost(ctx context.Context, r *http.Request, route *RouteMatch) (status i
 		return e.Code, nil, e
 	}
 	// See https://www.subbu.org/blog/2008/10/location-vs-content-location
-	headers = http.Header{}
+	headers = setWarningHeaders(http.Header{}, warnings)
 	itemID := item.ID
 	if f := rsrc.Validator().GetField("id"); f != nil {
 		if s, ok := f.Validator.(schema.FieldSerializer); ok {
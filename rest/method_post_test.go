@@ -48,6 +48,28 @@ func TestHandlerPostList(t *testing.T) {
 				}
 			},
 		},
+		"Deprecated": {
+			Init: func() *requestTestVars {
+				i := resource.NewIndex()
+				s := mem.NewHandler()
+				i.Bind("foo", schema.Schema{Fields: schema.Fields{
+					"id":  {OnInit: func(ctx context.Context, v interface{}) interface{} { return "1" }},
+					"foo": {Deprecated: true},
+					"bar": {Deprecated: true, DeprecationMessage: `use "baz" instead`},
+					"baz": {},
+				}}, s, resource.DefaultConf)
+				return &requestTestVars{Index: i, Storers: map[string]resource.Storer{"foo": s}}
+			},
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("POST", "/foo", bytes.NewBufferString(`{"foo": "a", "bar": "b"}`))
+			},
+			ResponseCode: 201,
+			ResponseBody: `{"foo":"a","bar":"b","id":"1"}`,
+			ResponseHeader: http.Header{
+				"Content-Location": []string{"/foo/1"},
+				"Warning":          []string{`299 - "bar: use \"baz\" instead"`, `299 - "foo: deprecated"`},
+			},
+		},
 		"BadPayload": {
 			Init: func() *requestTestVars {
 				index := resource.NewIndex()
@@ -163,6 +185,69 @@ func TestHandlerPostList(t *testing.T) {
 				"issues": {"foo": ["invalid field"]}
 			}`,
 		},
+		"Numbers": {
+			Init: func() *requestTestVars {
+				index := resource.NewIndex()
+				s := mem.NewHandler()
+				index.Bind("test", schema.Schema{Fields: schema.Fields{
+					"id":    {},
+					"int":   {Validator: &schema.Integer{Strict: true}},
+					"float": {Validator: &schema.Float{}},
+					"list":  {Validator: &schema.Array{}},
+					"any":   {},
+				}}, s, resource.DefaultConf)
+				return &requestTestVars{Index: index, Storers: map[string]resource.Storer{"test": s}}
+			},
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("POST", "/test", bytes.NewBufferString(`{"id": "1", "int": 3, "float": 1.5, "list": [1, {"a": 2}], "any": 4}`))
+			},
+			ResponseCode: http.StatusCreated,
+			ResponseBody: `{"id": "1", "int": 3, "float": 1.5, "list": [1, {"a": 2}], "any": 4}`,
+			ExtraTest: func(t *testing.T, vars *requestTestVars) {
+				l, err := vars.Storers["test"].Find(context.TODO(), &query.Query{
+					Predicate: query.Predicate{&query.Equal{Field: "id", Value: "1"}},
+					Window:    &query.Window{Limit: 1},
+				})
+				assert.NoError(t, err)
+				if assert.Len(t, l.Items, 1) {
+					assert.Equal(t, map[string]interface{}{
+						"id":    "1",
+						"int":   3,
+						"float": 1.5,
+						"list":  []interface{}{1.0, map[string]interface{}{"a": 2.0}},
+						"any":   4.0,
+					}, l.Items[0].Payload)
+				}
+			},
+		},
+		"StrictInteger": {
+			Init: func() *requestTestVars {
+				index := resource.NewIndex()
+				s := mem.NewHandler()
+				index.Bind("test", schema.Schema{Fields: schema.Fields{
+					"id": {},
+					"a":  {Validator: &schema.Integer{Strict: true}},
+					"b":  {Validator: &schema.AnyOf{&schema.Integer{Strict: true}}},
+					"c": {Validator: &schema.Array{Values: schema.Field{
+						Validator: &schema.AnyOf{&schema.Integer{Strict: true}},
+					}}},
+				}}, s, resource.DefaultConf)
+				return &requestTestVars{Index: index}
+			},
+			NewRequest: func() (*http.Request, error) {
+				return http.NewRequest("POST", "/test", bytes.NewBufferString(`{"id": "1", "a": 3.0, "b": 3.0, "c": [3.0]}`))
+			},
+			ResponseCode: http.StatusUnprocessableEntity,
+			ResponseBody: `{
+				"code": 422,
+				"message": "Document contains error(s)",
+				"issues": {
+					"a": ["not an integer"],
+					"b": ["not an integer"],
+					"c": ["invalid value at #1: not an integer", {"0": ["not an integer"]}]
+				}
+			}`,
+		},
 		"MissingID": {
 			Init: func() *requestTestVars {
 				index := resource.NewIndex()
@@ -219,7 +304,7 @@ func TestHandlerPostList(t *testing.T) {
 			ResponseBody: `{
 				"code": 422,
 				"message": "Document contains error(s)",
-				"issues": {"foo": ["Not Found"]}
+				"issues": {"foo": ["foo item 'nonexisting' not found"]}
 			}`,
 		},
 		"WithReferenceNoStorage": {
@@ -325,7 +410,7 @@ func TestHandlerPostList(t *testing.T) {
 			ResponseBody: `{
 				"code": 422,
 				"message": "Document contains error(s)",
-				"issues": {"foos":["invalid value at #2: Not Found"]}
+				"issues": {"foos":["invalid value at #2: foo item 'ref2' not found",{"1":["foo item 'ref2' not found"]}]}
 			}`,
 		},
 		"WithArraySchemaReference": {
@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/rs/rest-layer/resource"
+	"github.com/rs/rest-layer/schema"
 	"github.com/rs/rest-layer/schema/query"
 )
 
@@ -242,6 +243,33 @@ func (qp *queryParser) addIssue(field string, err interface{}) {
 	qp.issues[field] = append(qp.issues[field], err)
 }
 
+// queryValidator returns the validator used to validate filters and sorts,
+// accepting any field when the resource allows unindexed filters.
+func (qp *queryParser) queryValidator() schema.Validator {
+	v := qp.rsc.Validator()
+	if qp.rsc.Conf().AllowUnindexedFilters {
+		return unindexedValidator{v}
+	}
+	return v
+}
+
+// unindexedValidator wraps a validator to report all its fields as filterable
+// and sortable.
+type unindexedValidator struct {
+	schema.Validator
+}
+
+func (v unindexedValidator) GetField(name string) *schema.Field {
+	f := v.Validator.GetField(name)
+	if f == nil {
+		return nil
+	}
+	c := *f
+	c.Filterable = true
+	c.Sortable = true
+	return &c
+}
+
 func (qp *queryParser) parseProjection(params url.Values) {
 	if fields := params.Get("fields"); fields != "" {
 		if p, err := query.ParseProjection(fields); err != nil {
@@ -260,7 +288,7 @@ func (qp *queryParser) parsePredicate(params url.Values) {
 		for _, filter := range filters {
 			if p, err := query.ParsePredicate(filter); err != nil {
 				qp.addIssue("filter", err.Error())
-			} else if err := p.Prepare(qp.rsc.Validator()); err != nil {
+			} else if err := p.Prepare(qp.queryValidator()); err != nil {
 				qp.addIssue("filter", err.Error())
 			} else {
 				qp.q.Predicate = append(qp.q.Predicate, p...)
@@ -273,7 +301,7 @@ func (qp *queryParser) parseSort(params url.Values) {
 	if sort := params.Get("sort"); sort != "" {
 		if s, err := query.ParseSort(sort); err != nil {
 			qp.addIssue("sort", err.Error())
-		} else if err := s.Validate(qp.rsc.Validator()); err != nil {
+		} else if err := s.Validate(qp.queryValidator()); err != nil {
 			qp.addIssue("sort", err.Error())
 		} else {
 			qp.q.Sort = s
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/rs/rest-layer/resource"
+	"github.com/rs/rest-layer/schema"
 )
 
 // getMethodHandler returns the method handler for a given HTTP method in item
@@ -147,6 +149,9 @@ func decodePayload(r *http.Request, payload *map[string]interface{}) *Error {
 		return nil
 	}
 	decoder := json.NewDecoder(r.Body)
+	// Keep the numbers literal so validators like schema.Integer can check
+	// their form; the schema converts the other ones to float64.
+	decoder.UseNumber()
 	defer r.Body.Close()
 	if err := decoder.Decode(payload); err != nil {
 		return &Error{400, fmt.Sprintf("Malformed body: %v", err), nil}
@@ -179,6 +184,46 @@ func checkIntegrityRequest(r *http.Request, original *resource.Item) *Error {
 	return nil
 }
 
+// validatePayload validates changes applied on base using v, passing ctx to
+// the field validators when v implements schema.ValidatorCtx. The warnings are
+// returned when v implements schema.ValidatorWarnings.
+func validatePayload(ctx context.Context, v schema.Validator, changes, base map[string]interface{}) (map[string]interface{}, map[string][]interface{}, map[string][]interface{}) {
+	if vw, ok := v.(schema.ValidatorWarnings); ok {
+		return vw.ValidateWithWarningsCtx(ctx, changes, base)
+	}
+	if vc, ok := v.(schema.ValidatorCtx); ok {
+		doc, errs := vc.ValidateCtx(ctx, changes, base)
+		return doc, errs, nil
+	}
+	doc, errs := v.Validate(changes, base)
+	return doc, errs, nil
+}
+
+// setWarningHeaders adds a Warning header (RFC 7234) with the 299
+// (miscellaneous persistent warning) code to headers for each validation
+// warning, i.e.: `299 - "login: deprecated"`. The headers are created if nil.
+func setWarningHeaders(headers http.Header, warnings map[string][]interface{}) http.Header {
+	if len(warnings) == 0 {
+		return headers
+	}
+	if headers == nil {
+		headers = http.Header{}
+	}
+	flat := schema.FlattenErrors(warnings)
+	paths := make([]string, 0, len(flat))
+	for path := range flat {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	quoter := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	for _, path := range paths {
+		for _, w := range flat[path] {
+			headers.Add("Warning", fmt.Sprintf(`299 - "%s: %s"`, quoter.Replace(path), quoter.Replace(fmt.Sprint(w))))
+		}
+	}
+	return headers
+}
+
 func logErrorf(ctx context.Context, format string, a ...interface{}) {
 	if resource.Logger != nil {
 		resource.Logger(ctx, resource.LogLevelError, fmt.Sprintf(format, a...), nil)
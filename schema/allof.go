@@ -48,6 +48,16 @@ func (v AllOf) Validate(value interface{}) (interface{}, error) {
 	return value, nil
 }
 
+// AcceptsJSONNumber implements the FieldNumberValidator interface.
+func (v AllOf) AcceptsJSONNumber() bool {
+	for _, validator := range v {
+		if acceptsJSONNumber(validator) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetField implements the FieldGetter interface. Note that it will return the
 // first matching field only.
 func (v AllOf) GetField(name string) *Field {
@@ -33,16 +33,23 @@ func (v AnyOf) ValidateQuery(value interface{}) (interface{}, error) {
 		if err == nil {
 			return val, nil
 		}
-		errs = errs.Append(err)
+		errs = append(errs, err)
 	}
 
 	if len(errs) > 0 {
-		return nil, errs
+		return nil, closestErrors(errs)
 	}
 	return nil, nil
 }
 
-// Validate ensures that at least one sub-validator validates.
+// Validate ensures that at least one sub-validator validates. The result of
+// the first sub-validator to validate is returned, the next ones are not run.
+//
+// When no sub-validator validates, the errors of the closest matching ones are
+// returned: sub-validators reporting errors on nested fields or items (i.e.:
+// Object or Array), whose structure thus matched the value, are closer than
+// the others, and then the fewer errors the closer. When several
+// sub-validators are equally close, their errors are concatenated.
 func (v AnyOf) Validate(value interface{}) (interface{}, error) {
 	var errs ErrorSlice
 
@@ -51,15 +58,109 @@ func (v AnyOf) Validate(value interface{}) (interface{}, error) {
 		if err == nil {
 			return value, nil
 		}
-		errs = errs.Append(err)
+		errs = append(errs, err)
 	}
 
 	if len(errs) > 0 {
-		return nil, errs
+		return nil, closestErrors(errs)
 	}
 	return nil, nil
 }
 
+// AcceptsJSONNumber implements the FieldNumberValidator interface.
+func (v AnyOf) AcceptsJSONNumber() bool {
+	for _, validator := range v {
+		if acceptsJSONNumber(validator) {
+			return true
+		}
+	}
+	return false
+}
+
+// closestErrors returns the errors of the closest matching alternatives in
+// errs, as defined by AnyOf.Validate. A single error is returned as is so its
+// nested errors are still reported by Schema.Validate.
+func closestErrors(errs ErrorSlice) error {
+	var closest ErrorSlice
+	var bestNested bool
+	var bestCount int
+	for _, err := range errs {
+		nested := errorDetails(err) != nil
+		count := countErrors(err)
+		switch {
+		case closest == nil, nested && !bestNested, nested == bestNested && count < bestCount:
+			closest = ErrorSlice{err}
+			bestNested, bestCount = nested, count
+		case nested == bestNested && count == bestCount:
+			closest = closest.Append(err)
+		}
+	}
+	if len(closest) == 1 {
+		return closest[0]
+	}
+	return closest
+}
+
+// countErrors returns the number of failures reported by err, counting the
+// errors of nested fields and items.
+func countErrors(err error) int {
+	switch e := err.(type) {
+	case ErrorSlice:
+		n := 0
+		for _, err := range e {
+			n += countErrors(err)
+		}
+		return n
+	case arrayItemErrors:
+		n := 0
+		for _, err := range e.errs {
+			n += countErrors(err.err)
+		}
+		return n
+	case dictKeyErrors:
+		n := 0
+		for _, err := range e {
+			n += countErrors(err.err)
+		}
+		return n
+	case ErrorMap:
+		return countErrorValues(e)
+	}
+	return 1
+}
+
+// countErrorValues returns the number of failures reported in errs, as
+// returned by Schema.Validate. The summary error of an array or dict field is
+// not counted when the errors of its items are.
+func countErrorValues(errs map[string][]interface{}) int {
+	n := 0
+	for _, values := range errs {
+		nested, summaries := 0, 0
+		for _, v := range values {
+			switch e := v.(type) {
+			case map[string][]interface{}:
+				nested += countErrorValues(e)
+			case ErrorMap:
+				nested += countErrorValues(e)
+			case ValidationError:
+				if len(e.Details) > 0 {
+					nested += countErrorValues(e.Details)
+				} else {
+					summaries++
+				}
+			default:
+				summaries++
+			}
+		}
+		if nested > 0 {
+			n += nested
+		} else {
+			n += summaries
+		}
+	}
+	return n
+}
+
 // Serialize attempts to serialize the value using the first available
 // FieldSerializer which does not return an error. If no appropriate serializer
 // is found, the input value is returned.
@@ -116,6 +116,47 @@ func TestAnyOfValidate(t *testing.T) {
 			Input:  "foo1",
 			Expect: "foo1",
 		},
+		{
+			Name: `{Bool,Object{foo:String}}.Validate({"foo":1})`,
+			Validator: schema.AnyOf{
+				&schema.Bool{},
+				&schema.Object{Schema: &schema.Schema{Fields: schema.Fields{
+					"foo": {Validator: &schema.String{}},
+				}}},
+			},
+			Input: map[string]interface{}{"foo": 1},
+			Error: "foo is [not a string]",
+		},
+		{
+			Name: `{Object{name,url},Object{name,age}}.Validate({"name":"x","age":"old"})`,
+			Validator: schema.AnyOf{
+				&schema.Object{Schema: &schema.Schema{Fields: schema.Fields{
+					"name": {Validator: &schema.String{}},
+					"url":  {Required: true, Validator: &schema.URL{}},
+				}}},
+				&schema.Object{Schema: &schema.Schema{Fields: schema.Fields{
+					"name": {Validator: &schema.String{}},
+					"age":  {Validator: &schema.Integer{}},
+				}}},
+			},
+			Input: map[string]interface{}{"name": "x", "age": "old"},
+			Error: "age is [not an integer]",
+		},
+		{
+			Name: `{Object{name,age},Object{name,url}}.Validate({"name":1})`,
+			Validator: schema.AnyOf{
+				&schema.Object{Schema: &schema.Schema{Fields: schema.Fields{
+					"name": {Validator: &schema.String{}},
+					"age":  {Validator: &schema.Integer{}},
+				}}},
+				&schema.Object{Schema: &schema.Schema{Fields: schema.Fields{
+					"name": {Validator: &schema.Bool{}},
+					"url":  {Validator: &schema.URL{}},
+				}}},
+			},
+			Input: map[string]interface{}{"name": 1},
+			Error: "name is [not a string], name is [not a Boolean]",
+		},
 	}
 	for i := range cases {
 		cases[i].Run(t)
@@ -1,8 +1,10 @@
 package schema
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"strconv"
 )
 
@@ -14,33 +16,110 @@ type Array struct {
 	MinLen int
 	// MaxLen defines the maximum array length (default no limit).
 	MaxLen int
+	// Unique rejects arrays containing duplicate items. Items are compared
+	// after their normalization by the Values validator.
+	Unique bool
+	// DedupeSilently removes duplicate items, keeping the first occurrence,
+	// instead of rejecting the array when Unique is set.
+	DedupeSilently bool
 }
 
 // Compile implements the ReferenceCompiler interface.
 func (v *Array) Compile(rc ReferenceChecker) (err error) {
+	if v.MaxLen > 0 && v.MinLen > v.MaxLen {
+		return fmt.Errorf("MinLen (%d) is greater than MaxLen (%d)", v.MinLen, v.MaxLen)
+	}
 	return v.Values.Compile(rc)
 }
 
-func (v Array) validateValues(values []interface{}, query bool) ([]interface{}, error) {
+// Describe implements the Describer interface.
+func (v Array) Describe() map[string]interface{} {
+	m := map[string]interface{}{"type": "array"}
+	if values := v.Values.Describe(); len(values) > 0 {
+		m["values"] = values
+	}
+	if v.MinLen > 0 {
+		m["minLen"] = v.MinLen
+	}
+	if v.MaxLen > 0 {
+		m["maxLen"] = v.MaxLen
+	}
+	if v.Unique {
+		m["unique"] = true
+		if v.DedupeSilently {
+			m["dedupeSilently"] = true
+		}
+	}
+	return m
+}
+
+func (v Array) validateValues(ctx context.Context, values []interface{}, query bool) ([]interface{}, error) {
 	if v.Values.Validator == nil {
 		return values, nil
 	}
+	if bv, ok := v.Values.Validator.(FieldBatchValidator); ok && !query {
+		return v.validateBatch(ctx, bv, values)
+	}
 
 	var vFunc func(val interface{}) (interface{}, error)
 	if qv, ok := v.Values.Validator.(FieldQueryValidator); ok && query {
 		vFunc = qv.ValidateQuery
 	} else {
-		vFunc = v.Values.Validator.Validate
+		vFunc = func(val interface{}) (interface{}, error) {
+			return ValidateField(ctx, v.Values.Validator, val)
+		}
 	}
 
+	res := make([]interface{}, len(values))
+	copy(res, values)
+	var errs []arrayItemError
 	for i, val := range values {
-		val, err := vFunc(val)
+		val, err := v.Values.validateItem(vFunc, val)
 		if err != nil {
-			return nil, fmt.Errorf("invalid value at #%d: %s", i+1, err)
+			errs = append(errs, arrayItemError{i, err})
+			continue
 		}
-		values[i] = val
+		res[i] = val
+	}
+	if len(errs) > 0 {
+		return nil, arrayItemErrors{errs, res}
 	}
-	return values, nil
+	return res, nil
+}
+
+// validateBatch validates values with a single call to bv, handling null values
+// as described by Field.Nullable.
+func (v Array) validateBatch(ctx context.Context, bv FieldBatchValidator, values []interface{}) ([]interface{}, error) {
+	var batch []interface{}
+	var indexes []int
+	for i, val := range values {
+		if val == nil && v.Values.Nullable {
+			continue
+		}
+		batch = append(batch, val)
+		indexes = append(indexes, i)
+	}
+	if len(batch) == 0 {
+		return values, nil
+	}
+	batchRes, batchErrs := bv.ValidateBatch(ctx, batch)
+	res := make([]interface{}, len(values))
+	copy(res, values)
+	var errs []arrayItemError
+	for j, i := range indexes {
+		if err := batchErrs[j]; err != nil {
+			if values[i] == nil {
+				err = errNull
+			}
+			errs = append(errs, arrayItemError{i, err})
+			continue
+		}
+		res[i] = batchRes[j]
+	}
+	if len(errs) > 0 {
+		return nil, arrayItemErrors{errs, res}
+	}
+	return res, nil
 }
 
 // ValidateQuery implements FieldQueryValidator.
@@ -50,7 +129,7 @@ func (v Array) ValidateQuery(value interface{}) (interface{}, error) {
 		values = append(values, value)
 	}
 
-	arr, err := v.validateValues(values, true)
+	arr, err := v.validateValues(context.Background(), values, true)
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +142,15 @@ func (v Array) ValidateQuery(value interface{}) (interface{}, error) {
 
 // Validate implements FieldValidator.
 func (v Array) Validate(value interface{}) (interface{}, error) {
+	return v.ValidateCtx(context.Background(), value)
+}
+
+// ValidateCtx implements FieldValidatorCtx. The errors of invalid items are
+// reported by Schema.Validate keyed by their index. Items are normalized in a
+// new array, leaving value untouched, which is held by the returned error when
+// some items are invalid, so Schema.Validate keeps the valid items normalized
+// even when others fail.
+func (v Array) ValidateCtx(ctx context.Context, value interface{}) (interface{}, error) {
 	values, ok := value.([]interface{})
 	if !ok {
 		return nil, errors.New("not an array")
@@ -74,21 +162,115 @@ func (v Array) Validate(value interface{}) (interface{}, error) {
 	if v.MaxLen > 0 && l > v.MaxLen {
 		return nil, fmt.Errorf("has more items than %d", v.MaxLen)
 	}
-	arr, err := v.validateValues(values, false)
+	arr, err := v.validateValues(ctx, values, false)
 	if err != nil {
 		return nil, err
 	}
+	if v.Unique {
+		if v.DedupeSilently {
+			arr = dedupe(arr)
+			if len(arr) < v.MinLen {
+				return nil, fmt.Errorf("has fewer unique items than %d", v.MinLen)
+			}
+		} else if i := firstDuplicate(arr); i != -1 {
+			return nil, fmt.Errorf("has duplicate item at #%d", i+1)
+		}
+	}
 	return arr, nil
 }
 
+// AcceptsJSONNumber implements the FieldNumberValidator interface.
+func (v Array) AcceptsJSONNumber() bool {
+	return v.Values.acceptsJSONNumber(DefaultMaxDepth)
+}
+
+// itemSet is a set of array items. Strings, booleans and numbers are compared
+// with ==, others with reflect.DeepEqual.
+type itemSet struct {
+	hashed map[interface{}]struct{}
+	others []interface{}
+}
+
+// add adds val to the set and returns false if it was already present.
+func (s *itemSet) add(val interface{}) bool {
+	if isBasicKind(val) {
+		// Fast path for hashable values. Comparable types like structs or
+		// arrays are excluded as they may hold unhashable values in
+		// interface fields.
+		if _, found := s.hashed[val]; found {
+			return false
+		}
+		if s.hashed == nil {
+			s.hashed = map[interface{}]struct{}{}
+		}
+		s.hashed[val] = struct{}{}
+		return true
+	}
+	for _, other := range s.others {
+		if reflect.DeepEqual(val, other) {
+			return false
+		}
+	}
+	s.others = append(s.others, val)
+	return true
+}
+
+// isBasicKind returns true if val is a string, a boolean or a number.
+func isBasicKind(val interface{}) bool {
+	if val == nil {
+		return false
+	}
+	switch reflect.TypeOf(val).Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
+
+// firstDuplicate returns the index of the first item of values equal to a
+// previous item, or -1 if all items are unique.
+func firstDuplicate(values []interface{}) int {
+	var seen itemSet
+	for i, val := range values {
+		if !seen.add(val) {
+			return i
+		}
+	}
+	return -1
+}
+
+// dedupe returns values without the items equal to a previous item.
+func dedupe(values []interface{}) []interface{} {
+	var seen itemSet
+	res := make([]interface{}, 0, len(values))
+	for _, val := range values {
+		if seen.add(val) {
+			res = append(res, val)
+		}
+	}
+	return res
+}
+
 // GetField implements the FieldGetter interface. It will return
 // a Field if name corespond to a legal array index according to
-// parameters set on v.
+// parameters set on v. Remaining path after the index, or a path
+// not starting with an index, is resolved on the array's values
+// when they hold a sub-schema or a FieldGetter validator.
 func (v Array) GetField(name string) *Field {
-	if i, err := strconv.Atoi(name); err != nil {
-		return nil
-	} else if i < 0 || (v.MaxLen > 0 && i >= v.MaxLen) {
+	index, remaining, wasSplit := splitFieldPath(name)
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		// Descend transparently into the values.
+		return v.Values.getSubField(name)
+	}
+	if i < 0 || (v.MaxLen > 0 && i >= v.MaxLen) {
 		return nil
 	}
-	return &v.Values
+	if !wasSplit {
+		return &v.Values
+	}
+	return v.Values.getSubField(remaining)
 }
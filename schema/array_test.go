@@ -3,6 +3,7 @@
 package schema_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/rs/rest-layer/schema"
@@ -19,13 +20,24 @@ func TestArrayValidatorCompile(t *testing.T) {
 			Name:             "Values.Validator=&String{Regexp:invalid}",
 			Compiler:         &schema.Array{Values: schema.Field{Validator: &schema.String{Regexp: "[invalid re"}}},
 			ReferenceChecker: fakeReferenceChecker{},
-			Error:            ": invalid regexp: error parsing regexp: missing closing ]: `[invalid re`",
+			Error:            "invalid regexp: error parsing regexp: missing closing ]: `[invalid re`",
 		},
 		{
 			Name:             "Values.Validator=String{}",
 			Compiler:         &schema.Array{Values: schema.Field{Validator: schema.String{}}},
 			ReferenceChecker: fakeReferenceChecker{},
-			Error:            ": not a schema.Validator pointer",
+			Error:            "not a schema.Validator pointer",
+		},
+		{
+			Name:             "MinLen=2,MaxLen=1",
+			Compiler:         &schema.Array{MinLen: 2, MaxLen: 1},
+			ReferenceChecker: fakeReferenceChecker{},
+			Error:            "MinLen (2) is greater than MaxLen (1)",
+		},
+		{
+			Name:             "MinLen=2,MaxLen=0",
+			Compiler:         &schema.Array{MinLen: 2},
+			ReferenceChecker: fakeReferenceChecker{},
 		},
 	}
 	for i := range testCases {
@@ -59,12 +71,108 @@ func TestArrayValidator(t *testing.T) {
 			Input:     []interface{}{true, "value"},
 			Error:     "invalid value at #2: not a Boolean",
 		},
+		{
+			Name:      `Values.Validator=&schema.Bool{},Validate([]interface{}{"a",true,"b"})`,
+			Validator: &schema.Array{Values: schema.Field{Validator: &schema.Bool{}}},
+			Input:     []interface{}{"a", true, "b"},
+			Error:     "invalid value at #1: not a Boolean, invalid value at #3: not a Boolean",
+		},
 		{
 			Name:      `Values.Validator=&String{},Validate("value")`,
 			Validator: &schema.Array{Values: schema.Field{Validator: &schema.String{}}},
 			Input:     "value",
 			Error:     "not an array",
 		},
+		{
+			Name:      `Unique=true,Validate([]interface{}{"a","b"})`,
+			Validator: &schema.Array{Values: schema.Field{Validator: &schema.String{}}, Unique: true},
+			Input:     []interface{}{"a", "b"},
+			Expect:    []interface{}{"a", "b"},
+		},
+		{
+			Name:      `Unique=true,Validate([]interface{}{"a","b","a"})`,
+			Validator: &schema.Array{Values: schema.Field{Validator: &schema.String{}}, Unique: true},
+			Input:     []interface{}{"a", "b", "a"},
+			Error:     "has duplicate item at #3",
+		},
+		{
+			Name:      `Unique=false,Validate([]interface{}{"a","a"})`,
+			Validator: &schema.Array{Values: schema.Field{Validator: &schema.String{}}},
+			Input:     []interface{}{"a", "a"},
+			Expect:    []interface{}{"a", "a"},
+		},
+		{
+			Name:      `Unique=true,Validate([]interface{}{{"a":1},{"a":2},{"a":1}})`,
+			Validator: &schema.Array{Unique: true},
+			Input: []interface{}{
+				map[string]interface{}{"a": 1},
+				map[string]interface{}{"a": 2},
+				map[string]interface{}{"a": 1},
+			},
+			Error: "has duplicate item at #3",
+		},
+		{
+			Name:      `Unique=true,Validate([]interface{}{{"a":1},{"a":2}})`,
+			Validator: &schema.Array{Unique: true},
+			Input: []interface{}{
+				map[string]interface{}{"a": 1},
+				map[string]interface{}{"a": 2},
+			},
+			Expect: []interface{}{
+				map[string]interface{}{"a": 1},
+				map[string]interface{}{"a": 2},
+			},
+		},
+		{
+			Name:      `Unique=true,DedupeSilently=true,Validate([]interface{}{"b","a","b","a","c"})`,
+			Validator: &schema.Array{Values: schema.Field{Validator: &schema.String{}}, Unique: true, DedupeSilently: true},
+			Input:     []interface{}{"b", "a", "b", "a", "c"},
+			Expect:    []interface{}{"b", "a", "c"},
+		},
+		{
+			Name: `Unique=true,DedupeSilently=true,Validate([]interface{}{"a","A"}) normalized`,
+			Validator: &schema.Array{
+				Values:         schema.Field{Validator: &schema.String{Allowed: []string{"a"}, AllowedCaseInsensitive: true}},
+				Unique:         true,
+				DedupeSilently: true,
+			},
+			Input:  []interface{}{"a", "A"},
+			Expect: []interface{}{"a"},
+		},
+		{
+			Name:      `Unique=true,DedupeSilently=true,Validate([]interface{}{{"a":1},{"a":2},{"a":1}})`,
+			Validator: &schema.Array{Unique: true, DedupeSilently: true},
+			Input: []interface{}{
+				map[string]interface{}{"a": 1},
+				map[string]interface{}{"a": 2},
+				map[string]interface{}{"a": 1},
+			},
+			Expect: []interface{}{
+				map[string]interface{}{"a": 1},
+				map[string]interface{}{"a": 2},
+			},
+		},
+		{
+			Name:      `Unique=true,DedupeSilently=true,MinLen=2,Validate([]interface{}{"a","a"})`,
+			Validator: &schema.Array{Unique: true, DedupeSilently: true, MinLen: 2},
+			Input:     []interface{}{"a", "a"},
+			Error:     "has fewer unique items than 2",
+		},
+		{
+			Name:      `Unique=false,DedupeSilently=true,Validate([]interface{}{"a","a"})`,
+			Validator: &schema.Array{DedupeSilently: true},
+			Input:     []interface{}{"a", "a"},
+			Expect:    []interface{}{"a", "a"},
+		},
+		{
+			Name: `Unique=true,Validate([]interface{}{"a","A"}) normalized`,
+			Validator: &schema.Array{
+				Values: schema.Field{Validator: &schema.String{Allowed: []string{"a"}, AllowedCaseInsensitive: true}},
+				Unique: true,
+			},
+			Input: []interface{}{"a", "A"},
+			Error: "has duplicate item at #2",
+		},
 		{
 			Name:      `MinLen=2,Validate([]interface{}{true,false})`,
 			Validator: &schema.Array{Values: schema.Field{Validator: &schema.Bool{}}, MinLen: 2},
@@ -95,6 +203,30 @@ func TestArrayValidator(t *testing.T) {
 	}
 }
 
+func TestArrayValidatorUniqueStructs(t *testing.T) {
+	// Structs are comparable but may hold unhashable values.
+	type item struct {
+		Meta interface{} `json:"meta"`
+	}
+	v := &schema.Array{Unique: true, Values: schema.Field{Validator: &schema.Struct{Prototype: item{}}}}
+	if err := v.Compile(nil); err != nil {
+		t.Fatalf("Compile(): unexpected error: %v", err)
+	}
+	value, err := v.Validate([]interface{}{map[string]interface{}{"meta": map[string]interface{}{"a": 1}}})
+	if err != nil {
+		t.Errorf("Validate(): unexpected error: %v", err)
+	} else if items, _ := value.([]interface{}); len(items) != 1 {
+		t.Errorf("Validate(): expected 1 item, got: %v", value)
+	}
+	_, err = v.Validate([]interface{}{
+		map[string]interface{}{"meta": map[string]interface{}{"a": 1}},
+		map[string]interface{}{"meta": map[string]interface{}{"a": 1}},
+	})
+	if err == nil || err.Error() != "has duplicate item at #2" {
+		t.Errorf("Validate(): expected error: has duplicate item at #2, got: %v", err)
+	}
+}
+
 func TestArrayQueryValidator(t *testing.T) {
 	testCases := []fieldQueryValidatorTestCase{
 		{
@@ -156,3 +288,90 @@ func TestArrayQueryValidator(t *testing.T) {
 		testCases[i].Run(t)
 	}
 }
+
+func TestArrayGetField(t *testing.T) {
+	city := schema.Field{Validator: &schema.String{}}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"addresses": {
+				Validator: &schema.Array{
+					Values: schema.Field{
+						Validator: &schema.Object{
+							Schema: &schema.Schema{
+								Fields: schema.Fields{"city": city},
+							},
+						},
+					},
+					MaxLen: 10,
+				},
+			},
+			"tags": {
+				Validator: &schema.Array{Values: schema.Field{Validator: &schema.String{}}},
+			},
+		},
+	}
+	cases := []struct {
+		Name   string
+		Path   string
+		Expect *schema.Field
+	}{
+		{"Index", "addresses.0.city", &city},
+		{"Transparent", "addresses.city", &city},
+		{"IndexOutOfRange", "addresses.10.city", nil},
+		{"NegativeIndex", "addresses.-1.city", nil},
+		{"UnknownSubField", "addresses.0.zip", nil},
+		{"UnknownTransparentSubField", "addresses.zip", nil},
+		{"ScalarIndex", "tags.1", &schema.Field{Validator: &schema.String{}}},
+		{"ScalarNonIndex", "tags.foo", nil},
+		{"ScalarIndexSubField", "tags.1.foo", nil},
+	}
+	for i := range cases {
+		tc := cases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			f := s.GetField(tc.Path)
+			if tc.Expect == nil {
+				if f != nil {
+					t.Errorf("GetField(%q): expected nil, got: %#v", tc.Path, f)
+				}
+				return
+			}
+			if f == nil || !reflect.DeepEqual(*f, *tc.Expect) {
+				t.Errorf("GetField(%q): expected: %#v, got: %#v", tc.Path, tc.Expect, f)
+			}
+		})
+	}
+}
+
+func TestArrayDescribe(t *testing.T) {
+	cases := []struct {
+		name   string
+		array  schema.Array
+		expect map[string]interface{}
+	}{
+		{"{}", schema.Array{}, map[string]interface{}{"type": "array"}},
+		{
+			"{Values:String,MinLen:1,MaxLen:3,Unique}",
+			schema.Array{
+				Values: schema.Field{Validator: &schema.String{MaxLen: 5}},
+				MinLen: 1,
+				MaxLen: 3,
+				Unique: true,
+			},
+			map[string]interface{}{
+				"type":   "array",
+				"values": map[string]interface{}{"type": "string", "maxLen": 5},
+				"minLen": 1,
+				"maxLen": 3,
+				"unique": true,
+			},
+		},
+	}
+	for i := range cases {
+		tc := cases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.array.Describe(); !reflect.DeepEqual(got, tc.expect) {
+				t.Errorf("Describe() = %#v, want %#v", got, tc.expect)
+			}
+		})
+	}
+}
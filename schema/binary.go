@@ -0,0 +1,94 @@
+package schema
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Binary validates base64 encoded binary values, padded or not. The value is
+// decoded and stored as a []byte, and encoded back to base64 on serialization.
+type Binary struct {
+	// MaxLen defines the maximum size of the decoded data in bytes (default no
+	// limit).
+	MaxLen int
+	// AllowedTypes restricts the accepted MIME types as detected from the
+	// content of the data (i.e.: image/png). The detection algorithm is the
+	// one of http.DetectContentType.
+	AllowedTypes []string
+	// URLEncoding selects the URL and filename safe base64 alphabet (RFC 4648
+	// section 5) instead of the standard one.
+	URLEncoding bool
+	// StoreEncoded stores the data in its padded base64 encoded form instead
+	// of a []byte.
+	StoreEncoded bool
+}
+
+// Compile implements the Compiler interface.
+func (v *Binary) Compile(rc ReferenceChecker) error {
+	if v.MaxLen < 0 {
+		return fmt.Errorf("invalid MaxLen (%d): must be positive", v.MaxLen)
+	}
+	return nil
+}
+
+// Validate implements FieldValidator.
+func (v Binary) Validate(value interface{}) (interface{}, error) {
+	var b []byte
+	switch t := value.(type) {
+	case string:
+		enc := v.encoding()
+		if !strings.HasSuffix(t, "=") {
+			enc = enc.WithPadding(base64.NoPadding)
+		}
+		var err error
+		if b, err = enc.DecodeString(t); err != nil {
+			return nil, errors.New("invalid base64 encoding")
+		}
+	case []byte:
+		b = t
+	default:
+		return nil, errors.New("not a string")
+	}
+	if v.MaxLen > 0 && len(b) > v.MaxLen {
+		return nil, fmt.Errorf("is larger than %d bytes", v.MaxLen)
+	}
+	if len(v.AllowedTypes) > 0 {
+		ct, _, _ := mime.ParseMediaType(http.DetectContentType(b))
+		found := false
+		for _, allowed := range v.AllowedTypes {
+			if ct == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("content type %s not allowed", ct)
+		}
+	}
+	if v.StoreEncoded {
+		return v.encoding().EncodeToString(b), nil
+	}
+	return b, nil
+}
+
+// Serialize implements FieldSerializer.
+func (v Binary) Serialize(value interface{}) (interface{}, error) {
+	switch t := value.(type) {
+	case []byte:
+		return v.encoding().EncodeToString(t), nil
+	case string:
+		return t, nil
+	}
+	return nil, errors.New("invalid type")
+}
+
+func (v Binary) encoding() *base64.Encoding {
+	if v.URLEncoding {
+		return base64.URLEncoding
+	}
+	return base64.StdEncoding
+}
@@ -0,0 +1,157 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+// pngHeader is the signature of a PNG file.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func TestBinaryValidate(t *testing.T) {
+	cases := []fieldValidatorTestCase{
+		{
+			Name:      `Validate("aGVsbG8=")`,
+			Validator: &schema.Binary{},
+			Input:     "aGVsbG8=",
+			Expect:    []byte("hello"),
+		},
+		{
+			Name:      `Validate([]byte)`,
+			Validator: &schema.Binary{},
+			Input:     []byte("hello"),
+			Expect:    []byte("hello"),
+		},
+		{
+			Name:      `Validate("invalid!")`,
+			Validator: &schema.Binary{},
+			Input:     "invalid!",
+			Error:     "invalid base64 encoding",
+		},
+		{
+			Name:      `Validate(1)`,
+			Validator: &schema.Binary{},
+			Input:     1,
+			Error:     "not a string",
+		},
+		{
+			Name:      `{MaxLen:5}.Validate("aGVsbG8=")`,
+			Validator: &schema.Binary{MaxLen: 5},
+			Input:     "aGVsbG8=",
+			Expect:    []byte("hello"),
+		},
+		{
+			Name:      `{MaxLen:4}.Validate("aGVsbG8=")`,
+			Validator: &schema.Binary{MaxLen: 4},
+			Input:     "aGVsbG8=",
+			Error:     "is larger than 4 bytes",
+		},
+		{
+			Name:      `{AllowedTypes:["image/png"]}.Validate(png)`,
+			Validator: &schema.Binary{AllowedTypes: []string{"image/png"}},
+			Input:     "iVBORw0KGgo=",
+			Expect:    pngHeader,
+		},
+		{
+			Name:      `Validate("aGVsbG8")`,
+			Validator: &schema.Binary{},
+			Input:     "aGVsbG8",
+			Expect:    []byte("hello"),
+		},
+		{
+			Name:      `Validate("aGVsbG8==")`,
+			Validator: &schema.Binary{},
+			Input:     "aGVsbG8==",
+			Error:     "invalid base64 encoding",
+		},
+		{
+			Name:      `Validate("-_8=")`,
+			Validator: &schema.Binary{},
+			Input:     "-_8=",
+			Error:     "invalid base64 encoding",
+		},
+		{
+			Name:      `{URLEncoding:true}.Validate("-_8=")`,
+			Validator: &schema.Binary{URLEncoding: true},
+			Input:     "-_8=",
+			Expect:    []byte{0xfb, 0xff},
+		},
+		{
+			Name:      `{URLEncoding:true}.Validate("-_8")`,
+			Validator: &schema.Binary{URLEncoding: true},
+			Input:     "-_8",
+			Expect:    []byte{0xfb, 0xff},
+		},
+		{
+			Name:      `{URLEncoding:true}.Validate("+/8=")`,
+			Validator: &schema.Binary{URLEncoding: true},
+			Input:     "+/8=",
+			Error:     "invalid base64 encoding",
+		},
+		{
+			Name:      `{StoreEncoded:true}.Validate("aGVsbG8")`,
+			Validator: &schema.Binary{StoreEncoded: true},
+			Input:     "aGVsbG8",
+			Expect:    "aGVsbG8=",
+		},
+		{
+			Name:      `{StoreEncoded:true,MaxLen:4}.Validate("aGVsbG8")`,
+			Validator: &schema.Binary{StoreEncoded: true, MaxLen: 4},
+			Input:     "aGVsbG8",
+			Error:     "is larger than 4 bytes",
+		},
+		{
+			Name:      `{AllowedTypes:["image/png"]}.Validate(text)`,
+			Validator: &schema.Binary{AllowedTypes: []string{"image/png"}},
+			Input:     "aGVsbG8=",
+			Error:     "content type text/plain not allowed",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestBinaryCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{Name: "{MaxLen:0}", Compiler: &schema.Binary{}},
+		{Name: "{MaxLen:10}", Compiler: &schema.Binary{MaxLen: 10}},
+		{Name: "{MaxLen:-1}", Compiler: &schema.Binary{MaxLen: -1}, Error: "invalid MaxLen (-1): must be positive"},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestBinarySerialize(t *testing.T) {
+	cases := []fieldSerializerTestCase{
+		{
+			Name:       `Serialize([]byte)`,
+			Serializer: &schema.Binary{},
+			Input:      []byte("hello"),
+			Expect:     "aGVsbG8=",
+		},
+		{
+			Name:       `Serialize(string)`,
+			Serializer: &schema.Binary{},
+			Input:      "aGVsbG8=",
+			Expect:     "aGVsbG8=",
+		},
+		{
+			Name:       `{URLEncoding:true}.Serialize([]byte)`,
+			Serializer: &schema.Binary{URLEncoding: true},
+			Input:      []byte{0xfb, 0xff},
+			Expect:     "-_8=",
+		},
+		{
+			Name:       `Serialize(int)`,
+			Serializer: &schema.Binary{},
+			Input:      1,
+			Error:      "invalid type",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
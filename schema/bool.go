@@ -2,12 +2,28 @@ package schema
 
 import "errors"
 
-// Bool validates Boolean based values.
+// Bool validates Boolean based values. Numbers (i.e.: 0 or 1) are always
+// rejected, with or without Coerce.
 type Bool struct {
+	// Coerce accepts the "true" and "false" strings and converts them to bool.
+	Coerce bool
+}
+
+// Describe implements the Describer interface.
+func (v Bool) Describe() map[string]interface{} {
+	return map[string]interface{}{"type": "bool"}
 }
 
 // Validate validates and normalize Boolean based value.
 func (v Bool) Validate(value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok && v.Coerce {
+		switch s {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
 	if _, ok := value.(bool); !ok {
 		return nil, errors.New("not a Boolean")
 	}
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -23,3 +24,29 @@ func TestBoolValidator(t *testing.T) {
 	assert.EqualError(t, err, "not a Boolean")
 	assert.Nil(t, s)
 }
+
+func TestBoolValidatorCoerce(t *testing.T) {
+	s, err := Bool{Coerce: true}.Validate("true")
+	assert.NoError(t, err)
+	assert.Equal(t, true, s)
+	s, err = Bool{Coerce: true}.Validate("false")
+	assert.NoError(t, err)
+	assert.Equal(t, false, s)
+	s, err = Bool{Coerce: true}.Validate(true)
+	assert.NoError(t, err)
+	assert.Equal(t, true, s)
+	for _, input := range []string{"TRUE", "1", "yes", ""} {
+		s, err = Bool{Coerce: true}.Validate(input)
+		assert.EqualError(t, err, "not a Boolean", input)
+		assert.Nil(t, s, input)
+	}
+	for _, input := range []interface{}{0, 1.0, json.Number("1")} {
+		s, err = Bool{Coerce: true}.Validate(input)
+		assert.EqualError(t, err, "not a Boolean", "%v", input)
+		assert.Nil(t, s, "%v", input)
+	}
+}
+
+func TestBoolDescribe(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{"type": "bool"}, Bool{}.Describe())
+}
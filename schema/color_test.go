@@ -0,0 +1,60 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestColorValidate(t *testing.T) {
+	cases := []fieldValidatorTestCase{
+		{Name: `Validate("#ABC")`, Validator: &schema.Color{}, Input: "#ABC", Expect: "#aabbcc"},
+		{Name: `Validate("#A1B2C3")`, Validator: &schema.Color{}, Input: "#A1B2C3", Expect: "#a1b2c3"},
+		{Name: `Validate(1)`, Validator: &schema.Color{}, Input: 1, Error: "not a string"},
+		{Name: `Validate("")`, Validator: &schema.Color{}, Input: "", Error: "invalid color, use #RGB or #RRGGBB notation"},
+		{Name: `Validate("#")`, Validator: &schema.Color{}, Input: "#", Error: "invalid color, use #RGB or #RRGGBB notation"},
+		{Name: `Validate("a1b2c3")`, Validator: &schema.Color{}, Input: "a1b2c3", Error: "invalid color, use #RGB or #RRGGBB notation"},
+		{Name: `Validate("#a1b2cg")`, Validator: &schema.Color{}, Input: "#a1b2cg", Error: "invalid color, use #RGB or #RRGGBB notation"},
+		{Name: `Validate("#a1b2c3d4")`, Validator: &schema.Color{}, Input: "#a1b2c3d4", Error: "invalid color, use #RGB or #RRGGBB notation"},
+		{Name: `Validate("red")`, Validator: &schema.Color{}, Input: "red", Error: "invalid color, use #RGB or #RRGGBB notation"},
+		{
+			Name:      `{AllowAlpha:true}.Validate("#A1B2C3D4")`,
+			Validator: &schema.Color{AllowAlpha: true},
+			Input:     "#A1B2C3D4",
+			Expect:    "#a1b2c3d4",
+		},
+		{
+			Name:      `{AllowAlpha:true}.Validate("#abc")`,
+			Validator: &schema.Color{AllowAlpha: true},
+			Input:     "#abc",
+			Expect:    "#aabbcc",
+		},
+		{
+			Name:      `{AllowAlpha:true}.Validate("#a1b2c")`,
+			Validator: &schema.Color{AllowAlpha: true},
+			Input:     "#a1b2c",
+			Error:     "invalid color, use #RGB, #RRGGBB or #RRGGBBAA notation",
+		},
+		{
+			Name:      `{AllowNamed:true}.Validate("RebeccaPurple")`,
+			Validator: &schema.Color{AllowNamed: true},
+			Input:     "RebeccaPurple",
+			Expect:    "#663399",
+		},
+		{
+			Name:      `{AllowNamed:true}.Validate("#fff")`,
+			Validator: &schema.Color{AllowNamed: true},
+			Input:     "#fff",
+			Expect:    "#ffffff",
+		},
+		{
+			Name:      `{AllowNamed:true}.Validate("reddish")`,
+			Validator: &schema.Color{AllowNamed: true},
+			Input:     "reddish",
+			Error:     "invalid color, use #RGB or #RRGGBB notation or a CSS color name",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Condition defines constraints applied to a document when it matches a
+// predicate. For instance, a company must have a VAT number but no birth date:
+//
+//     schema.Condition{
+//         If:        query.MustParsePredicate(`{type: "company"}`),
+//         Required:  []string{"vat_number"},
+//         Forbidden: []string{"birthdate"},
+//     }
+type Condition struct {
+	// If is the predicate matched against the validated document of the
+	// schema holding the condition. The fields it references must be
+	// filterable.
+	If Predicate
+	// Required lists the fields which must be set when the document matches.
+	Required []string
+	// Forbidden lists the fields which must not be set when the document
+	// matches.
+	Forbidden []string
+	// Validators defines additional validators applied to the value of the
+	// fields, when set, when the document matches.
+	Validators map[string]FieldValidator
+}
+
+// compileConditions validates the fields referenced by the conditions of s
+// and compiles their predicates and validators.
+func compileConditions(s Schema, rc ReferenceChecker) error {
+	for i, c := range s.Conditions {
+		if c.If == nil {
+			return fmt.Errorf("conditions[%d]: no predicate defined", i)
+		}
+		if fl, ok := c.If.(fieldLister); ok {
+			if unknown := unknownFields(fl.Fields(), s); len(unknown) > 0 {
+				return fmt.Errorf("conditions[%d]: predicate references unknown fields: %s", i, strings.Join(unknown, ", "))
+			}
+		}
+		if err := c.If.Prepare(s); err != nil {
+			return fmt.Errorf("conditions[%d]: %v", i, err)
+		}
+		for _, names := range [][]string{c.Required, c.Forbidden} {
+			for _, name := range names {
+				if _, found := s.Fields[name]; !found {
+					return fmt.Errorf("conditions[%d]: field %s not found", i, name)
+				}
+			}
+		}
+		for name, v := range c.Validators {
+			if _, found := s.Fields[name]; !found {
+				return fmt.Errorf("conditions[%d]: field %s not found", i, name)
+			}
+			if cmp, ok := v.(Compiler); ok {
+				if err := cmp.Compile(rc); err != nil {
+					return fmt.Errorf("conditions[%d]: %s: %v", i, name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateConditions applies the conditions of s matching doc and returns the
+// errors by field. The values normalized by the validators of the conditions
+// are stored in doc. Required fields absent from doc are ignored when partial is true.
+func (s Schema) validateConditions(ctx context.Context, doc map[string]interface{}, partial bool) (errs map[string][]interface{}) {
+	errs = map[string][]interface{}{}
+	for _, c := range s.Conditions {
+		if !c.If.Match(doc) {
+			continue
+		}
+		for _, field := range c.Required {
+			if value, found := doc[field]; (!found && !partial) || (found && value == nil) {
+				addFieldError(errs, field, ValidationError{CodeRequired, "required", field, nil})
+			}
+		}
+		for _, field := range c.Forbidden {
+			if value, found := doc[field]; found && value != nil {
+				addFieldError(errs, field, ValidationError{CodeDependency, fmt.Sprintf("not allowed when %v", c.If), field, nil})
+			}
+		}
+		for field, v := range c.Validators {
+			value, found := doc[field]
+			if !found || value == nil {
+				continue
+			}
+			value, err := ValidateField(ctx, v, value)
+			if err != nil {
+				addFieldError(errs, field, ValidationError{CodeValidator, err.Error(), field, errorDetails(err)})
+				continue
+			}
+			doc[field] = value
+		}
+	}
+	return errs
+}
@@ -1,14 +1,36 @@
 package schema
 
+import "fmt"
+
 // Connection is a dummy validator to define a weak connection to another
 // schema. The query.Projection will treat this validator as an external
-// resource, and generate a sub-request to fetch the sub-payload.
+// resource, and generate a sub-request to fetch the sub-payload, selecting the
+// items of the resource at Path whose Field holds the ID of the current item.
+//
+// Connection fields are never stored: any value provided for them on create
+// or update is rejected as read-only.
 type Connection struct {
 	Path      string
 	Field     string
 	Validator Validator
 }
 
+// Compile implements the ReferenceCompiler interface. When v.Validator is not
+// set, it is resolved from v.Path using rc.
+func (v *Connection) Compile(rc ReferenceChecker) error {
+	if v.Validator != nil {
+		return nil
+	}
+	if rc == nil {
+		return fmt.Errorf("rc can not be nil")
+	}
+	if _, sv := rc.ReferenceChecker(v.Path); sv != nil {
+		v.Validator = sv
+		return nil
+	}
+	return fmt.Errorf("can't find resource '%s'", v.Path)
+}
+
 // Validate implements the FieldValidator interface.
 func (v *Connection) Validate(value interface{}) (interface{}, error) {
 	// No validation perform at this time.
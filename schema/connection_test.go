@@ -0,0 +1,60 @@
+package schema_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestConnectionCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{
+			Name:     "{Path:valid}",
+			Compiler: &schema.Connection{Path: "foo", Field: "bar"},
+			ReferenceChecker: fakeReferenceChecker{
+				"foo": {SchemaValidator: &schema.Schema{}},
+			},
+		},
+		{
+			Name:             "{Path:invalid}",
+			Compiler:         &schema.Connection{Path: "foo", Field: "bar"},
+			ReferenceChecker: fakeReferenceChecker{},
+			Error:            "can't find resource 'foo'",
+		},
+		{
+			Name:     "{Path:invalid,Validator:set}",
+			Compiler: &schema.Connection{Path: ".foo", Field: "bar", Validator: &schema.Schema{}},
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestConnectionCompileResolvesValidator(t *testing.T) {
+	sv := &schema.Schema{Fields: schema.Fields{"bar": {}}}
+	c := &schema.Connection{Path: "foo", Field: "bar"}
+	if err := c.Compile(fakeReferenceChecker{"foo": {SchemaValidator: sv}}); err != nil {
+		t.Fatalf("Compile(): unexpected error: %v", err)
+	}
+	if c.Validator != sv {
+		t.Errorf("Compile(): Validator = %v, want %v", c.Validator, sv)
+	}
+}
+
+func TestSchemaValidateConnection(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"id": {},
+			"posts": {
+				Validator: &schema.Connection{Path: "posts", Field: "user", Validator: &schema.Schema{}},
+			},
+		},
+	}
+	_, errs := s.Validate(map[string]interface{}{"posts": []interface{}{"a"}}, map[string]interface{}{"id": "1"})
+	want := map[string][]interface{}{"posts": {schema.ValidationError{Code: schema.CodeReadOnly, Message: "read-only", Field: "posts"}}}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("Validate(): errs = %v, want %v", errs, want)
+	}
+}
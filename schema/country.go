@@ -0,0 +1,135 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// CountryCodeFormat defines one of the ISO 3166-1 country code formats.
+type CountryCodeFormat int
+
+const (
+	// CountryAlpha2 is the two-letter format (i.e.: "FR").
+	CountryAlpha2 CountryCodeFormat = iota
+	// CountryAlpha3 is the three-letter format (i.e.: "FRA").
+	CountryAlpha3
+	// CountryNumeric is the three-digit format (i.e.: "250").
+	CountryNumeric
+)
+
+func (f CountryCodeFormat) String() string {
+	switch f {
+	case CountryAlpha2:
+		return "alpha-2"
+	case CountryAlpha3:
+		return "alpha-3"
+	case CountryNumeric:
+		return "numeric"
+	}
+	return fmt.Sprintf("CountryCodeFormat(%d)", int(f))
+}
+
+// Country holds the ISO 3166-1 codes of a country.
+type Country struct {
+	Alpha2  string
+	Alpha3  string
+	Numeric string
+	Name    string
+}
+
+// Code returns the code of the country in the format f.
+func (c Country) Code(f CountryCodeFormat) string {
+	switch f {
+	case CountryAlpha3:
+		return c.Alpha3
+	case CountryNumeric:
+		return c.Numeric
+	}
+	return c.Alpha2
+}
+
+// countryIndex maps each format to its codes. The value is true if the code is
+// historical only.
+var countryIndex = map[CountryCodeFormat]map[string]bool{}
+
+func init() {
+	for _, f := range []CountryCodeFormat{CountryAlpha2, CountryAlpha3, CountryNumeric} {
+		index := make(map[string]bool, len(Countries)+len(HistoricalCountries))
+		for _, c := range HistoricalCountries {
+			index[c.Code(f)] = true
+		}
+		// Current codes take precedence over reassigned historical ones.
+		for _, c := range Countries {
+			index[c.Code(f)] = false
+		}
+		countryIndex[f] = index
+	}
+}
+
+// CountryCode validates ISO 3166-1 country codes.
+type CountryCode struct {
+	// Format defines the accepted code format (default CountryAlpha2).
+	Format CountryCodeFormat
+	// AllowHistorical accepts withdrawn codes listed in HistoricalCountries.
+	AllowHistorical bool
+}
+
+// Compile implements the Compiler interface.
+func (v *CountryCode) Compile(rc ReferenceChecker) error {
+	if _, found := countryIndex[v.Format]; !found {
+		return fmt.Errorf("invalid country code format: %s", v.Format)
+	}
+	return nil
+}
+
+// Validate validates and normalizes country codes. Alpha codes are converted
+// to uppercase. Numeric codes may be given as numbers and are converted to
+// their three-digit string form.
+func (v CountryCode) Validate(value interface{}) (interface{}, error) {
+	var code string
+	switch t := value.(type) {
+	case string:
+		code = strings.ToUpper(t)
+	case float64:
+		if v.Format != CountryNumeric || t != math.Trunc(t) || t < 0 || t > 999 {
+			return nil, v.error()
+		}
+		code = fmt.Sprintf("%03d", int(t))
+	case int:
+		if v.Format != CountryNumeric || t < 0 || t > 999 {
+			return nil, v.error()
+		}
+		code = fmt.Sprintf("%03d", t)
+	default:
+		return nil, errors.New("invalid type")
+	}
+	historical, found := countryIndex[v.Format][code]
+	if !found || (historical && !v.AllowHistorical) {
+		return nil, v.error()
+	}
+	return code, nil
+}
+
+// Codes returns the list of accepted codes.
+func (v CountryCode) Codes() []string {
+	codes := make([]string, 0, len(Countries))
+	for _, c := range Countries {
+		codes = append(codes, c.Code(v.Format))
+	}
+	if v.AllowHistorical {
+		seen := map[string]bool{}
+		for _, c := range HistoricalCountries {
+			if code := c.Code(v.Format); countryIndex[v.Format][code] && !seen[code] {
+				codes = append(codes, code)
+				seen[code] = true
+			}
+		}
+	}
+	return codes
+}
+
+func (v CountryCode) error() error {
+	return fmt.Errorf("invalid ISO 3166-1 %s country code", v.Format)
+}
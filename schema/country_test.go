@@ -0,0 +1,129 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestCountryCodeCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{
+			Name:     "{Format:CountryAlpha3}",
+			Compiler: &schema.CountryCode{Format: schema.CountryAlpha3},
+		},
+		{
+			Name:     "{Format:42}",
+			Compiler: &schema.CountryCode{Format: 42},
+			Error:    "invalid country code format: CountryCodeFormat(42)",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestCountryCodeValidate(t *testing.T) {
+	cases := []fieldValidatorTestCase{
+		{
+			Name:      `{}.Validate("fr")`,
+			Validator: &schema.CountryCode{},
+			Input:     "fr",
+			Expect:    "FR",
+		},
+		{
+			Name:      `{}.Validate("FRA")`,
+			Validator: &schema.CountryCode{},
+			Input:     "FRA",
+			Error:     "invalid ISO 3166-1 alpha-2 country code",
+		},
+		{
+			Name:      `{}.Validate("XX")`,
+			Validator: &schema.CountryCode{},
+			Input:     "XX",
+			Error:     "invalid ISO 3166-1 alpha-2 country code",
+		},
+		{
+			Name:      `{}.Validate(1)`,
+			Validator: &schema.CountryCode{},
+			Input:     true,
+			Error:     "invalid type",
+		},
+		{
+			Name:      `{Format:CountryAlpha3}.Validate("deu")`,
+			Validator: &schema.CountryCode{Format: schema.CountryAlpha3},
+			Input:     "deu",
+			Expect:    "DEU",
+		},
+		{
+			Name:      `{Format:CountryNumeric}.Validate("250")`,
+			Validator: &schema.CountryCode{Format: schema.CountryNumeric},
+			Input:     "250",
+			Expect:    "250",
+		},
+		{
+			Name:      `{Format:CountryNumeric}.Validate(4)`,
+			Validator: &schema.CountryCode{Format: schema.CountryNumeric},
+			Input:     4.0,
+			Expect:    "004",
+		},
+		{
+			Name:      `{Format:CountryNumeric}.Validate("999")`,
+			Validator: &schema.CountryCode{Format: schema.CountryNumeric},
+			Input:     "999",
+			Error:     "invalid ISO 3166-1 numeric country code",
+		},
+		{
+			Name:      `{}.Validate("SU")`,
+			Validator: &schema.CountryCode{},
+			Input:     "SU",
+			Error:     "invalid ISO 3166-1 alpha-2 country code",
+		},
+		{
+			Name:      `{AllowHistorical:true}.Validate("SU")`,
+			Validator: &schema.CountryCode{AllowHistorical: true},
+			Input:     "su",
+			Expect:    "SU",
+		},
+		{
+			Name:      `{Format:CountryNumeric}.Validate("104")`,
+			Validator: &schema.CountryCode{Format: schema.CountryNumeric},
+			Input:     "104",
+			Expect:    "104",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestCountryCodeCodes(t *testing.T) {
+	codes := schema.CountryCode{}.Codes()
+	assert.Len(t, codes, len(schema.Countries))
+	assert.Contains(t, codes, "FR")
+	assert.NotContains(t, codes, "SU")
+	codes = schema.CountryCode{Format: schema.CountryAlpha2, AllowHistorical: true}.Codes()
+	assert.Contains(t, codes, "SU")
+	n := 0
+	for _, code := range codes {
+		if code == "CS" {
+			n++
+		}
+	}
+	assert.Equal(t, 1, n)
+}
+
+func TestCountriesTable(t *testing.T) {
+	seen := map[string]bool{}
+	for _, c := range schema.Countries {
+		for _, code := range []string{c.Alpha2, c.Alpha3, c.Numeric} {
+			assert.False(t, seen[code], "duplicate code %s", code)
+			seen[code] = true
+		}
+		assert.Len(t, c.Alpha2, 2)
+		assert.Len(t, c.Alpha3, 3)
+		assert.Len(t, c.Numeric, 3)
+	}
+}
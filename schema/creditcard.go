@@ -0,0 +1,158 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Card brands returned by CardBrand.
+const (
+	CardAmex       = "amex"
+	CardDiners     = "diners"
+	CardDiscover   = "discover"
+	CardJCB        = "jcb"
+	CardMaestro    = "maestro"
+	CardMastercard = "mastercard"
+	CardUnionPay   = "unionpay"
+	CardVisa       = "visa"
+)
+
+// cardBrands lists the IIN ranges and valid lengths of the supported brands.
+// Brands are tested in order, so the most specific ranges come first.
+var cardBrands = []struct {
+	brand   string
+	ranges  []string
+	lengths []int
+}{
+	{CardAmex, []string{"34", "37"}, []int{15}},
+	{CardDiners, []string{"300-305", "36", "38-39"}, []int{14, 15, 16, 17, 18, 19}},
+	{CardJCB, []string{"3528-3589"}, []int{16, 17, 18, 19}},
+	{CardVisa, []string{"4"}, []int{13, 16, 19}},
+	{CardMaestro, []string{"5018", "5020", "5038", "5893", "6304", "6759", "6761-6763"}, []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{CardMastercard, []string{"51-55", "2221-2720"}, []int{16}},
+	{CardDiscover, []string{"6011", "644-649", "65"}, []int{16, 17, 18, 19}},
+	{CardUnionPay, []string{"62"}, []int{16, 17, 18, 19}},
+}
+
+// CardBrand returns the brand of the card number (i.e.: CardVisa), detected
+// from its issuer identification number, or an empty string if the brand is
+// unknown. Spaces and dashes in number are ignored.
+//
+// It can be used in hooks to store the brand of a card in a sibling field.
+func CardBrand(number string) string {
+	number = stripCardNumber(number)
+	for _, b := range cardBrands {
+		for _, r := range b.ranges {
+			if matchIINRange(number, r) {
+				return b.brand
+			}
+		}
+	}
+	return ""
+}
+
+// CreditCard validates payment card numbers using the Luhn checksum and the
+// valid lengths of the detected brand. Spaces and dashes are removed from the
+// stored value.
+//
+// Use Mask to never store the full number, and the Hidden field flag to make
+// the field write-only.
+type CreditCard struct {
+	// Brands restricts the accepted card brands (i.e.: CardVisa).
+	Brands []string
+	// Mask stores the card number masked, only keeping its last four digits
+	// (i.e.: "**** **** **** 1234").
+	Mask bool
+}
+
+// Validate implements FieldValidator.
+func (v CreditCard) Validate(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("not a string")
+	}
+	number := stripCardNumber(s)
+	if number == "" || !isDigits(number) {
+		return nil, errors.New("invalid card number")
+	}
+	brand := CardBrand(number)
+	if brand == "" {
+		return nil, errors.New("unknown card brand")
+	}
+	if len(v.Brands) > 0 && !isIn(v.Brands, brand) {
+		return nil, fmt.Errorf("card brand %s not allowed", brand)
+	}
+	if !validCardLength(brand, len(number)) {
+		return nil, fmt.Errorf("invalid length for %s card", brand)
+	}
+	if !luhn(number) {
+		return nil, errors.New("invalid card number checksum")
+	}
+	if v.Mask {
+		return cardMask + number[len(number)-4:], nil
+	}
+	return number, nil
+}
+
+// cardMask is the prefix of masked card numbers.
+const cardMask = "**** **** **** "
+
+func stripCardNumber(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(s)
+}
+
+// matchIINRange returns true if number starts with a prefix in r, where r is
+// either a single prefix or a range of prefixes of the same length (i.e.:
+// "51-55").
+func matchIINRange(number, r string) bool {
+	lo, hi := r, r
+	if i := strings.IndexByte(r, '-'); i != -1 {
+		lo, hi = r[:i], r[i+1:]
+	}
+	if len(number) < len(lo) {
+		return false
+	}
+	prefix := number[:len(lo)]
+	return prefix >= lo && prefix <= hi
+}
+
+func validCardLength(brand string, l int) bool {
+	for _, b := range cardBrands {
+		if b.brand == brand {
+			for _, bl := range b.lengths {
+				if bl == l {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// luhn returns true if the digits of number have a valid Luhn checksum.
+func luhn(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		d, _ := strconv.Atoi(number[i : i+1])
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func isIn(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
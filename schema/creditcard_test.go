@@ -0,0 +1,108 @@
+package schema_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestCardBrand(t *testing.T) {
+	cases := map[string]string{
+		"4111 1111 1111 1111": schema.CardVisa,
+		"5555555555554444":    schema.CardMastercard,
+		"2223003122003222":    schema.CardMastercard,
+		"378282246310005":     schema.CardAmex,
+		"6011111111111117":    schema.CardDiscover,
+		"6445644564456445":    schema.CardDiscover,
+		"30569309025904":      schema.CardDiners,
+		"3530111333300000":    schema.CardJCB,
+		"6200000000000005":    schema.CardUnionPay,
+		"6759649826438453":    schema.CardMaestro,
+		"9999999999999999":    "",
+		"":                    "",
+	}
+	for number, brand := range cases {
+		if got := schema.CardBrand(number); got != brand {
+			t.Errorf("CardBrand(%q): expected %q, got %q", number, brand, got)
+		}
+	}
+}
+
+func TestCreditCardValidate(t *testing.T) {
+	cases := []fieldValidatorTestCase{
+		{Name: `Validate(visa)`, Validator: &schema.CreditCard{}, Input: "4111 1111 1111 1111", Expect: "4111111111111111"},
+		{Name: `Validate(mastercard)`, Validator: &schema.CreditCard{}, Input: "5555-5555-5555-4444", Expect: "5555555555554444"},
+		{Name: `Validate(amex)`, Validator: &schema.CreditCard{}, Input: "3782 822463 10005", Expect: "378282246310005"},
+		{Name: `Validate(diners)`, Validator: &schema.CreditCard{}, Input: "30569309025904", Expect: "30569309025904"},
+		{Name: `Validate(jcb)`, Validator: &schema.CreditCard{}, Input: "3530111333300000", Expect: "3530111333300000"},
+		{Name: `Validate(1)`, Validator: &schema.CreditCard{}, Input: 1, Error: "not a string"},
+		{Name: `Validate("")`, Validator: &schema.CreditCard{}, Input: "", Error: "invalid card number"},
+		{Name: `Validate(letters)`, Validator: &schema.CreditCard{}, Input: "4111 1111 1111 111a", Error: "invalid card number"},
+		{Name: `Validate(unknown brand)`, Validator: &schema.CreditCard{}, Input: "9999999999999995", Error: "unknown card brand"},
+		{Name: `Validate(amex 16 digits)`, Validator: &schema.CreditCard{}, Input: "3782822463100056", Error: "invalid length for amex card"},
+		{Name: `Validate(visa 15 digits)`, Validator: &schema.CreditCard{}, Input: "411111111111111", Error: "invalid length for visa card"},
+		{Name: `Validate(bad checksum)`, Validator: &schema.CreditCard{}, Input: "4111111111111112", Error: "invalid card number checksum"},
+		{
+			Name:      `{Brands:[visa]}.Validate(mastercard)`,
+			Validator: &schema.CreditCard{Brands: []string{schema.CardVisa}},
+			Input:     "5555555555554444",
+			Error:     "card brand mastercard not allowed",
+		},
+		{
+			Name:      `{Mask:true}.Validate(visa)`,
+			Validator: &schema.CreditCard{Mask: true},
+			Input:     "4111 1111 1111 1111",
+			Expect:    "**** **** **** 1111",
+		},
+		{
+			Name:      `{Mask:true}.Validate(amex)`,
+			Validator: &schema.CreditCard{Mask: true},
+			Input:     "378282246310005",
+			Expect:    "**** **** **** 0005",
+		},
+		{
+			Name:      `{Mask:true}.Validate(masked)`,
+			Validator: &schema.CreditCard{Mask: true},
+			Input:     "**** **** **** 0005",
+			Error:     "invalid card number",
+		},
+		{
+			Name:      `Validate(masked)`,
+			Validator: &schema.CreditCard{},
+			Input:     "**** **** **** 0005",
+			Error:     "invalid card number",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestCreditCardStoredValue(t *testing.T) {
+	s := schema.Schema{Fields: schema.Fields{
+		"name": {},
+		"card": {Hidden: true, Validator: &schema.CreditCard{Mask: true}},
+	}}
+	if err := s.Compile(nil); err != nil {
+		t.Fatal(err)
+	}
+	original := map[string]interface{}{"name": "a", "card": "**** **** **** 1111"}
+	for _, replace := range []bool{false, true} {
+		// The stored masked number is kept without being validated again.
+		changes, base := s.Prepare(context.Background(), map[string]interface{}{"name": "b"}, &original, replace)
+		doc, errs := s.Validate(changes, base)
+		if len(errs) > 0 {
+			t.Errorf("Validate(replace=%v) unexpected errs: %v", replace, errs)
+		}
+		if want := map[string]interface{}{"name": "b", "card": "**** **** **** 1111"}; !reflect.DeepEqual(doc, want) {
+			t.Errorf("Validate(replace=%v) doc = %v, want %v", replace, doc, want)
+		}
+	}
+	// A masked number submitted by the client is rejected.
+	changes, base := s.Prepare(context.Background(), map[string]interface{}{"card": "**** **** **** 0000"}, &original, false)
+	if _, errs := s.Validate(changes, base); len(errs) == 0 {
+		t.Error("Validate(masked) expected an error")
+	}
+}
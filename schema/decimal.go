@@ -0,0 +1,163 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal validates fixed-point decimal values such as monetary amounts.
+// Values are accepted as string or json.Number to avoid the precision loss of
+// floats, and are stored as *big.Rat.
+type Decimal struct {
+	// MaxDigits defines the maximum number of significant digits (default no
+	// limit).
+	MaxDigits int
+	// DecimalPlaces defines the maximum number of digits after the decimal
+	// point (default no limit). When set, serialized values are padded to this
+	// number of decimal places.
+	DecimalPlaces int
+	Boundaries    *Boundaries
+}
+
+// Compile implements the Compiler interface.
+func (v *Decimal) Compile(rc ReferenceChecker) error {
+	if v.MaxDigits < 0 || v.DecimalPlaces < 0 {
+		return errors.New("MaxDigits and DecimalPlaces must not be negative")
+	}
+	if v.MaxDigits > 0 && v.DecimalPlaces > v.MaxDigits {
+		return fmt.Errorf("DecimalPlaces (%d) is greater than MaxDigits (%d)", v.DecimalPlaces, v.MaxDigits)
+	}
+	return nil
+}
+
+// Validate validates and normalizes decimal values.
+func (v Decimal) Validate(value interface{}) (interface{}, error) {
+	r, err := v.parse(value)
+	if err != nil {
+		return nil, err
+	}
+	if v.Boundaries != nil {
+		format := func(bound float64) string { return strconv.FormatFloat(bound, 'f', -1, 64) }
+		cmp := func(bound float64) int {
+			if math.IsInf(bound, 0) {
+				return -cmpFloat(bound, 0)
+			}
+			// Compare with the shortest decimal representation of the bound
+			// rather than its binary value, so 0.1 is exactly 1/10.
+			b, _ := new(big.Rat).SetString(format(bound))
+			return r.Cmp(b)
+		}
+		if err := v.Boundaries.check(cmp, format); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (v Decimal) parse(value interface{}) (*big.Rat, error) {
+	var s string
+	switch t := value.(type) {
+	case string:
+		s = t
+	case json.Number:
+		s = string(t)
+	case *big.Rat:
+		s = t.FloatString(decimalScale(t))
+	default:
+		return nil, errors.New("not a decimal")
+	}
+	intPart, fracPart, ok := splitDecimal(s)
+	if !ok {
+		return nil, errors.New("not a decimal")
+	}
+	intPart = strings.TrimLeft(intPart, "0")
+	fracPart = strings.TrimRight(fracPart, "0")
+	if v.DecimalPlaces > 0 && len(fracPart) > v.DecimalPlaces {
+		return nil, fmt.Errorf("has more than %d decimal places", v.DecimalPlaces)
+	}
+	if v.MaxDigits > 0 && len(intPart)+len(fracPart) > v.MaxDigits {
+		return nil, fmt.Errorf("has more than %d digits", v.MaxDigits)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, errors.New("not a decimal")
+	}
+	return r, nil
+}
+
+// Serialize implements FieldSerializer. Decimals are serialized as strings so
+// their precision survives JSON round trips.
+func (v Decimal) Serialize(value interface{}) (interface{}, error) {
+	switch t := value.(type) {
+	case *big.Rat:
+		scale := v.DecimalPlaces
+		if s := decimalScale(t); s > scale {
+			scale = s
+		}
+		return t.FloatString(scale), nil
+	case string:
+		return t, nil
+	case json.Number:
+		return string(t), nil
+	}
+	return nil, errors.New("invalid type")
+}
+
+// LessFunc implements the FieldComparator interface.
+func (v Decimal) LessFunc() LessFunc {
+	return v.less
+}
+
+func (v Decimal) less(value, other interface{}) bool {
+	t, err1 := v.parse(value)
+	o, err2 := v.parse(other)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	return t.Cmp(o) < 0
+}
+
+// splitDecimal splits s, in the form [-+]digits[.digits], into its integer and
+// fractional parts.
+func splitDecimal(s string) (intPart, fracPart string, ok bool) {
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	intPart = s
+	if i := strings.IndexByte(s, '.'); i != -1 {
+		intPart, fracPart = s[:i], s[i+1:]
+		if fracPart == "" {
+			return "", "", false
+		}
+	}
+	if intPart == "" {
+		return "", "", false
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return "", "", false
+			}
+		}
+	}
+	return intPart, fracPart, true
+}
+
+// decimalScale returns the number of decimal places needed to represent r
+// exactly. The result is undefined for values with no finite decimal
+// representation, which are never produced by Decimal.
+func decimalScale(r *big.Rat) int {
+	scale := 0
+	x := new(big.Rat).Set(r)
+	ten := big.NewRat(10, 1)
+	for !x.IsInt() && scale < 100 {
+		x.Mul(x, ten)
+		scale++
+	}
+	return scale
+}
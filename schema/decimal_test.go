@@ -0,0 +1,181 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestDecimalCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{
+			Name:     "{MaxDigits:10,DecimalPlaces:2}",
+			Compiler: &schema.Decimal{MaxDigits: 10, DecimalPlaces: 2},
+		},
+		{
+			Name:     "{MaxDigits:2,DecimalPlaces:3}",
+			Compiler: &schema.Decimal{MaxDigits: 2, DecimalPlaces: 3},
+			Error:    "DecimalPlaces (3) is greater than MaxDigits (2)",
+		},
+		{
+			Name:     "{MaxDigits:-1}",
+			Compiler: &schema.Decimal{MaxDigits: -1},
+			Error:    "MaxDigits and DecimalPlaces must not be negative",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestDecimalValidate(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Validator schema.Decimal
+		Input     interface{}
+		Expect    *big.Rat
+		Error     string
+	}{
+		{Name: `Validate("12.50")`, Input: "12.50", Expect: big.NewRat(25, 2)},
+		{Name: `Validate("-0.1")`, Input: "-0.1", Expect: big.NewRat(-1, 10)},
+		{Name: `Validate(json.Number("3"))`, Input: json.Number("3"), Expect: big.NewRat(3, 1)},
+		{Name: `Validate(*big.Rat)`, Input: big.NewRat(1, 4), Expect: big.NewRat(1, 4)},
+		{Name: `Validate(float64)`, Input: 1.5, Error: "not a decimal"},
+		{Name: `Validate("1e3")`, Input: "1e3", Error: "not a decimal"},
+		{Name: `Validate("1/3")`, Input: "1/3", Error: "not a decimal"},
+		{Name: `Validate(".5")`, Input: ".5", Error: "not a decimal"},
+		{Name: `Validate("5.")`, Input: "5.", Error: "not a decimal"},
+		{
+			Name:      `{DecimalPlaces:2}.Validate("1.230")`,
+			Validator: schema.Decimal{DecimalPlaces: 2},
+			Input:     "1.230",
+			Expect:    big.NewRat(123, 100),
+		},
+		{
+			Name:      `{DecimalPlaces:2}.Validate("1.234")`,
+			Validator: schema.Decimal{DecimalPlaces: 2},
+			Input:     "1.234",
+			Error:     "has more than 2 decimal places",
+		},
+		{
+			Name:      `{MaxDigits:4}.Validate("0012.34")`,
+			Validator: schema.Decimal{MaxDigits: 4},
+			Input:     "0012.34",
+			Expect:    big.NewRat(1234, 100),
+		},
+		{
+			Name:      `{MaxDigits:4}.Validate("123.45")`,
+			Validator: schema.Decimal{MaxDigits: 4},
+			Input:     "123.45",
+			Error:     "has more than 4 digits",
+		},
+		{
+			Name:      `{Boundaries:{0,100}}.Validate("100.01")`,
+			Validator: schema.Decimal{Boundaries: &schema.Boundaries{Min: 0, Max: 100}},
+			Input:     "100.01",
+			Error:     "must be lower than or equal to 100",
+		},
+		{
+			Name:      `{Boundaries:{0.5,100}}.Validate("0.49")`,
+			Validator: schema.Decimal{Boundaries: &schema.Boundaries{Min: 0.5, Max: 100}},
+			Input:     "0.49",
+			Error:     "must be greater than or equal to 0.5",
+		},
+		{
+			Name:      `{Boundaries:{0,100,ExclusiveMin}}.Validate("0.00")`,
+			Validator: schema.Decimal{Boundaries: &schema.Boundaries{Min: 0, Max: 100, ExclusiveMin: true}},
+			Input:     "0.00",
+			Error:     "must be greater than 0",
+		},
+		{
+			Name:      `{Boundaries:{0,100,ExclusiveMax}}.Validate("100")`,
+			Validator: schema.Decimal{Boundaries: &schema.Boundaries{Min: 0, Max: 100, ExclusiveMax: true}},
+			Input:     "100",
+			Error:     "must be lower than 100",
+		},
+		{
+			Name:      `{Boundaries:{0.1,0.3}}.Validate("0.1")`,
+			Validator: schema.Decimal{Boundaries: &schema.Boundaries{Min: 0.1, Max: 0.3}},
+			Input:     "0.1",
+			Expect:    big.NewRat(1, 10),
+		},
+		{
+			Name:      `{Boundaries:{0.1,0.3}}.Validate("0.3")`,
+			Validator: schema.Decimal{Boundaries: &schema.Boundaries{Min: 0.1, Max: 0.3}},
+			Input:     "0.3",
+			Expect:    big.NewRat(3, 10),
+		},
+		{
+			Name:      `{Boundaries:{0.1,0.3,ExclusiveMin}}.Validate("0.1")`,
+			Validator: schema.Decimal{Boundaries: &schema.Boundaries{Min: 0.1, Max: 0.3, ExclusiveMin: true}},
+			Input:     "0.1",
+			Error:     "must be greater than 0.1",
+		},
+		{
+			Name:      `{Boundaries:{0.1,0.3}}.Validate("0.30000000000000001")`,
+			Validator: schema.Decimal{Boundaries: &schema.Boundaries{Min: 0.1, Max: 0.3}},
+			Input:     "0.30000000000000001",
+			Error:     "must be lower than or equal to 0.3",
+		},
+		{
+			Name:      `{Boundaries:{0,Inf}}.Validate("1000")`,
+			Validator: schema.Decimal{Boundaries: &schema.Boundaries{Min: 0, Max: math.Inf(1)}},
+			Input:     "1000",
+			Expect:    big.NewRat(1000, 1),
+		},
+	}
+	for i := range cases {
+		tc := cases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			v, err := tc.Validator.Validate(tc.Input)
+			if tc.Error != "" {
+				if err == nil || err.Error() != tc.Error {
+					t.Errorf("expected error: %v, got: %v", tc.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r, ok := v.(*big.Rat); !ok || r.Cmp(tc.Expect) != 0 {
+				t.Errorf("expected: %v, got: %v", tc.Expect, v)
+			}
+		})
+	}
+}
+
+func TestDecimalSerialize(t *testing.T) {
+	cases := []fieldSerializerTestCase{
+		{
+			Name:       `Serialize(*big.Rat)`,
+			Serializer: &schema.Decimal{},
+			Input:      big.NewRat(25, 2),
+			Expect:     "12.5",
+		},
+		{
+			Name:       `{DecimalPlaces:2}.Serialize(*big.Rat)`,
+			Serializer: &schema.Decimal{DecimalPlaces: 2},
+			Input:      big.NewRat(25, 2),
+			Expect:     "12.50",
+		},
+		{
+			Name:       `Serialize(string)`,
+			Serializer: &schema.Decimal{},
+			Input:      "12.50",
+			Expect:     "12.50",
+		},
+		{
+			Name:       `Serialize(float64)`,
+			Serializer: &schema.Decimal{},
+			Input:      12.5,
+			Error:      "invalid type",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
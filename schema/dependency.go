@@ -1,6 +1,10 @@
 package schema
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 // Predicate is an interface matching the query.Predicate type.
 type Predicate interface {
@@ -13,17 +17,31 @@ func Q() Predicate {
 	panic("schema.Q is deprecated, please use query.MustParsePredicate instead")
 }
 
+// fieldLister is implemented by the predicates able to list the fields they
+// reference, like query.Predicate.
+type fieldLister interface {
+	Fields() []string
+}
+
 // compileDependencies recursively compiles all field.Dependency against the
-// validator and report any error.
-func compileDependencies(s Schema, v Validator) error {
-	for _, def := range s.Fields {
+// validator and report any error. The fields referenced by a dependency are
+// looked up from the root of the validator, so a dependency can refer to a
+// field of a parent schema or, using the dotted notation, of a sub-schema.
+func compileDependencies(s Schema, v Validator, prefix string) error {
+	for field, def := range s.Fields {
+		path := prefix + field
 		if def.Dependency != nil {
+			if fl, ok := def.Dependency.(fieldLister); ok {
+				if unknown := unknownFields(fl.Fields(), v); len(unknown) > 0 {
+					return fmt.Errorf("%s: dependency references unknown fields: %s", path, strings.Join(unknown, ", "))
+				}
+			}
 			if err := def.Dependency.Prepare(v); err != nil {
-				return err
+				return fmt.Errorf("%s: invalid dependency: %v", path, err)
 			}
 		}
 		if def.Schema != nil {
-			if err := compileDependencies(*def.Schema, v); err != nil {
+			if err := compileDependencies(*def.Schema, v, path+"."); err != nil {
 				return err
 			}
 		}
@@ -31,6 +49,20 @@ func compileDependencies(s Schema, v Validator) error {
 	return nil
 }
 
+// unknownFields returns the sorted list of the fields not found in v.
+func unknownFields(fields []string, v Validator) []string {
+	var unknown []string
+	seen := map[string]bool{}
+	for _, field := range fields {
+		if !seen[field] && v.GetField(field) == nil {
+			unknown = append(unknown, field)
+		}
+		seen[field] = true
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
 func (s Schema) validateDependencies(changes map[string]interface{}, doc map[string]interface{}, prefix string) (errs map[string][]interface{}) {
 	errs = map[string][]interface{}{}
 	for name, value := range changes {
@@ -38,7 +70,7 @@ func (s Schema) validateDependencies(changes map[string]interface{}, doc map[str
 		field := s.GetField(path)
 		if field != nil && field.Dependency != nil {
 			if !field.Dependency.Match(doc) {
-				addFieldError(errs, name, fmt.Sprintf("does not match dependency: %+v", field.Dependency))
+				addFieldError(errs, name, ValidationError{CodeDependency, fmt.Sprintf("does not match dependency: %+v", field.Dependency), name, nil})
 			}
 		}
 		if subChanges, ok := value.(map[string]interface{}); ok {
@@ -49,3 +81,24 @@ func (s Schema) validateDependencies(changes map[string]interface{}, doc map[str
 	}
 	return errs
 }
+
+// validateExclusions reports an error on the fields excluded by another field
+// of doc when either of them is changed.
+func (s Schema) validateExclusions(changes map[string]interface{}, doc map[string]interface{}) (errs map[string][]interface{}) {
+	errs = map[string][]interface{}{}
+	for field, def := range s.Fields {
+		if v, found := doc[field]; !found || v == nil {
+			continue
+		}
+		_, changed := changes[field]
+		for _, name := range def.Excludes {
+			if v, found := doc[name]; !found || v == nil {
+				continue
+			}
+			if _, found := changes[name]; changed || found {
+				addFieldError(errs, name, ValidationError{CodeDependency, fmt.Sprintf("not allowed with %s", field), name, nil})
+			}
+		}
+	}
+	return errs
+}
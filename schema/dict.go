@@ -1,8 +1,10 @@
 package schema
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sort"
 )
 
 // Dict validates objects with variadic keys.
@@ -10,7 +12,8 @@ type Dict struct {
 	// KeysValidator is the validator to apply on dict keys.
 	KeysValidator FieldValidator
 
-	// Values describes the properties for each dict value.
+	// Values describes the properties for each dict value. Structured values
+	// can be described with a sub-schema (Values.Schema).
 	Values Field
 	// MinLen defines the minimum number of fields (default 0).
 	MinLen int
@@ -27,6 +30,9 @@ func (v *Dict) Compile(rc ReferenceChecker) (err error) {
 
 	}
 
+	if v.Values.Schema != nil {
+		return v.Values.Schema.Compile(rc)
+	}
 	if c, ok := v.Values.Validator.(Compiler); ok {
 		if err = c.Compile(rc); err != nil {
 			return
@@ -37,30 +43,39 @@ func (v *Dict) Compile(rc ReferenceChecker) (err error) {
 
 // Validate implements FieldValidator interface.
 func (v Dict) Validate(value interface{}) (interface{}, error) {
+	return v.ValidateCtx(context.Background(), value)
+}
+
+// ValidateCtx implements FieldValidatorCtx interface.
+func (v Dict) ValidateCtx(ctx context.Context, value interface{}) (interface{}, error) {
 	dict, ok := value.(map[string]interface{})
 	if !ok {
 		return nil, errors.New("not a dict")
 	}
 	dest := map[string]interface{}{}
+	var errs dictKeyErrors
 	for key, val := range dict {
 		if v.KeysValidator != nil {
 			nkey, err := v.KeysValidator.Validate(key)
 			if err != nil {
-				return nil, fmt.Errorf("invalid key `%s': %s", key, err)
+				errs = append(errs, dictKeyError{key, true, err})
+				continue
 			}
 			if key, ok = nkey.(string); !ok {
 				return nil, errors.New("key validator does not return string")
 			}
 		}
-		if v.Values.Validator != nil {
-			var err error
-			val, err = v.Values.Validator.Validate(val)
-			if err != nil {
-				return nil, fmt.Errorf("invalid value for key `%s': %s", key, err)
-			}
+		val, err := v.validateValue(ctx, val)
+		if err != nil {
+			errs = append(errs, dictKeyError{key, false, err})
+			continue
 		}
 		dest[key] = val
 	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].key < errs[j].key })
+		return nil, errs
+	}
 	l := len(dest)
 	if l < v.MinLen {
 		return nil, fmt.Errorf("has fewer properties than %d", v.MinLen)
@@ -71,6 +86,36 @@ func (v Dict) Validate(value interface{}) (interface{}, error) {
 	return dest, nil
 }
 
+// AcceptsJSONNumber implements the FieldNumberValidator interface.
+func (v Dict) AcceptsJSONNumber() bool {
+	return v.Values.acceptsJSONNumber(DefaultMaxDepth)
+}
+
+// validateValue validates a dict value using the Values sub-schema or
+// validator.
+func (v Dict) validateValue(ctx context.Context, value interface{}) (interface{}, error) {
+	if value == nil && v.Values.Nullable {
+		return nil, nil
+	}
+	if v.Values.Schema != nil {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("not a dict")
+		}
+		doc, errs := v.Values.Schema.ValidateCtx(ctx, obj, nil)
+		if len(errs) > 0 {
+			return nil, ErrorMap(errs)
+		}
+		return doc, nil
+	}
+	if v.Values.Validator != nil {
+		return v.Values.validateItem(func(value interface{}) (interface{}, error) {
+			return ValidateField(ctx, v.Values.Validator, value)
+		}, value)
+	}
+	return value, nil
+}
+
 // GetField implements the FieldGetter interface.
 func (v Dict) GetField(name string) *Field {
 	if v.KeysValidator != nil {
@@ -33,6 +33,14 @@ func TestDictCompile(t *testing.T) {
 			ReferenceChecker: fakeReferenceChecker{},
 			Error:            "invalid regexp: error parsing regexp: missing closing ]: `[invalid re`",
 		},
+		{
+			Name: "{Values.Schema:{foo:String{Regexp:invalid}}}",
+			Compiler: &schema.Dict{Values: schema.Field{Schema: &schema.Schema{Fields: schema.Fields{
+				"foo": {Validator: &schema.String{Regexp: "[invalid re"}},
+			}}}},
+			ReferenceChecker: fakeReferenceChecker{},
+			Error:            "foo: invalid regexp: error parsing regexp: missing closing ]: `[invalid re`",
+		},
 		{
 			Name:             "{Values.Validator:Reference{Path:valid}}",
 			Compiler:         &schema.Dict{Values: schema.Field{Validator: &schema.Reference{Path: "foo"}}},
@@ -68,7 +76,7 @@ func TestDictValidate(t *testing.T) {
 			Name:      `{KeysValidator:String{MinLen:3}}.Validate(invalid)`,
 			Validator: &schema.Dict{KeysValidator: &schema.String{MinLen: 3}},
 			Input:     map[string]interface{}{"foo": true, "ba": false},
-			Error:     "invalid key `ba': is shorter than 3",
+			Error:     "invalid key `ba': is shorter than 3 characters (got 2)",
 		},
 		{
 			Name:      `{Values.Validator:Bool}.Validate(valid)`,
@@ -112,6 +120,58 @@ func TestDictValidate(t *testing.T) {
 			Input:     map[string]interface{}{"foo": true, "bar": "value"},
 			Error:     "has more properties than 1",
 		},
+		{
+			Name: `{KeysValidator:String{MinLen:3},Values.Validator:Bool}.Validate(invalid)`,
+			Validator: &schema.Dict{
+				KeysValidator: &schema.String{MinLen: 3},
+				Values:        schema.Field{Validator: &schema.Bool{}},
+			},
+			Input: map[string]interface{}{"foo": "value", "ba": true, "baz": 1},
+			Error: "invalid key `ba': is shorter than 3 characters (got 2), invalid value for key `baz': not a Boolean, invalid value for key `foo': not a Boolean",
+		},
+		{
+			Name:      `{}.Validate(map[interface{}]interface{})`,
+			Validator: &schema.Dict{},
+			Input:     map[interface{}]interface{}{1: true},
+			Error:     "not a dict",
+		},
+		{
+			Name: `{KeysValidator:func}.Validate(non-string key)`,
+			Validator: &schema.Dict{KeysValidator: schema.FieldValidatorFunc(func(value interface{}) (interface{}, error) {
+				return 1, nil
+			})},
+			Input: map[string]interface{}{"foo": true},
+			Error: "key validator does not return string",
+		},
+		{
+			Name: `{KeysValidator:LanguageTag,Values.Validator:LanguageTag}.Validate(normalized)`,
+			Validator: &schema.Dict{
+				KeysValidator: &schema.LanguageTag{},
+				Values:        schema.Field{Validator: &schema.LanguageTag{}},
+			},
+			Input:  map[string]interface{}{"EN-us": "fr-fr", "de": "DE-at"},
+			Expect: map[string]interface{}{"en-US": "fr-FR", "de": "de-AT"},
+		},
+		{
+			Name: `{Values.Schema}.Validate(valid)`,
+			Validator: &schema.Dict{Values: schema.Field{Schema: &schema.Schema{
+				Fields: schema.Fields{
+					"title": {Required: true, Validator: &schema.String{}},
+				},
+			}}},
+			Input:  map[string]interface{}{"fr": map[string]interface{}{"title": "bonjour"}},
+			Expect: map[string]interface{}{"fr": map[string]interface{}{"title": "bonjour"}},
+		},
+		{
+			Name: `{Values.Schema}.Validate(invalid)`,
+			Validator: &schema.Dict{Values: schema.Field{Schema: &schema.Schema{
+				Fields: schema.Fields{
+					"title": {Required: true, Validator: &schema.String{}},
+				},
+			}}},
+			Input: map[string]interface{}{"fr": map[string]interface{}{}, "en": "hello"},
+			Error: "invalid value for key `en': not a dict, invalid value for key `fr': title is [required]",
+		},
 	}
 	for i := range testCases {
 		testCases[i].Run(t)
@@ -147,3 +207,56 @@ func TestDictGetField(t *testing.T) {
 		}
 	})
 }
+
+func TestDictValidateErrorsByKey(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"translations": {
+				Validator: &schema.Dict{
+					KeysValidator: &schema.LanguageTag{},
+					Values: schema.Field{Schema: &schema.Schema{
+						Fields: schema.Fields{
+							"title": {Required: true, Validator: &schema.String{MaxLen: 10}},
+						},
+					}},
+				},
+			},
+		},
+	}
+	if err := s.Compile(nil); err != nil {
+		t.Fatal(err)
+	}
+	_, errs := s.Validate(map[string]interface{}{
+		"translations": map[string]interface{}{
+			"en":     map[string]interface{}{"title": "hello"},
+			"fr_FR!": map[string]interface{}{"title": "bonjour"},
+			"de":     map[string]interface{}{"title": "guten morgen"},
+		},
+	}, map[string]interface{}{})
+	expect := map[string][]interface{}{
+		"translations": {
+			schema.ValidationError{
+				Code:    schema.CodeValidator,
+				Message: "invalid value for key `de': title is [is longer than 10 characters (got 12)], invalid key `fr_FR!': invalid language tag",
+				Field:   "translations",
+			},
+		},
+		"translations.de.title": {
+			schema.ValidationError{
+				Code:    schema.CodeValidator,
+				Message: "is longer than 10 characters (got 12)",
+				Field:   "title",
+			},
+		},
+		"translations.fr_FR!": {
+			schema.ValidationError{
+				Code:    schema.CodeValidator,
+				Message: "invalid key: invalid language tag",
+				Field:   "fr_FR!",
+			},
+		},
+	}
+	if flat := schema.FlattenErrors(errs); !reflect.DeepEqual(expect, flat) {
+		t.Errorf("FlattenErrors(Validate()) returned %#v, expected %#v", flat, expect)
+	}
+}
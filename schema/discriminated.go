@@ -0,0 +1,138 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Discriminated validates objects whose schema is selected by the value of a
+// discriminator property. For instance, with PropertyName set to "type", the
+// object {"type": "click", ...} is validated against Mapping["click"].
+//
+// Like Object, the selected schema validates the object as a new document. The
+// discriminator property doesn't have to be declared by the mapped schemas; it
+// is always kept in the validated object. On partial updates, the object
+// received by the validator is merged with its original value (see
+// Schema.PrepareMergePatch), so the original discriminator applies when it is
+// not changed.
+type Discriminated struct {
+	// PropertyName is the name of the discriminator property.
+	PropertyName string
+	// Mapping associates the accepted discriminator values to their schema.
+	Mapping map[string]*Schema
+}
+
+// Compile implements the ReferenceCompiler interface.
+func (v *Discriminated) Compile(rc ReferenceChecker) error {
+	if v.PropertyName == "" {
+		return errors.New("no property name defined")
+	}
+	if len(v.Mapping) == 0 {
+		return errors.New("no mapping defined")
+	}
+	for _, key := range v.keys() {
+		s := v.Mapping[key]
+		if s == nil {
+			return fmt.Errorf("%s: no schema defined", key)
+		}
+		if err := s.Compile(rc); err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// Describe implements the Describer interface.
+func (v Discriminated) Describe() map[string]interface{} {
+	mapping := make(map[string]interface{}, len(v.Mapping))
+	for key, s := range v.Mapping {
+		if s != nil {
+			mapping[key] = s.Describe()
+		}
+	}
+	return map[string]interface{}{
+		"type":          "object",
+		"discriminator": v.PropertyName,
+		"mapping":       mapping,
+	}
+}
+
+// Validate implements FieldValidator interface.
+func (v Discriminated) Validate(value interface{}) (interface{}, error) {
+	return v.ValidateCtx(context.Background(), value)
+}
+
+// ValidateCtx implements FieldValidatorCtx interface.
+func (v Discriminated) ValidateCtx(ctx context.Context, value interface{}) (interface{}, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("not an object")
+	}
+	d, found := obj[v.PropertyName]
+	if !found || d == nil {
+		return nil, ErrorMap{v.PropertyName: {ValidationError{CodeRequired, "required", v.PropertyName, nil}}}
+	}
+	key, _ := d.(string)
+	s := v.Mapping[key]
+	if s == nil {
+		msg := fmt.Sprintf("not one of [%s]", strings.Join(v.keys(), ", "))
+		return nil, ErrorMap{v.PropertyName: {ValidationError{CodeValidator, msg, v.PropertyName, nil}}}
+	}
+	payload := obj
+	if _, declared := s.Fields[v.PropertyName]; !declared {
+		payload = make(map[string]interface{}, len(obj))
+		for k, val := range obj {
+			if k != v.PropertyName {
+				payload[k] = val
+			}
+		}
+	}
+	changes, base := s.Prepare(ctx, payload, nil, false)
+	dest, errs := s.ValidateCtx(ctx, changes, base)
+	if len(errs) > 0 {
+		return nil, ErrorMap(errs)
+	}
+	dest[v.PropertyName] = key
+	return dest, nil
+}
+
+// AcceptsJSONNumber implements the FieldNumberValidator interface.
+func (v Discriminated) AcceptsJSONNumber() bool {
+	for _, s := range v.Mapping {
+		if s != nil && s.acceptsJSONNumber(s.maxDepth()) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetField implements the FieldGetter interface. The field is looked up in
+// the mapped schemas in the order of their discriminator value. The
+// discriminator property is returned as a String field accepting the
+// discriminator values.
+func (v Discriminated) GetField(name string) *Field {
+	if name == v.PropertyName {
+		return &Field{Validator: &String{Allowed: v.keys()}}
+	}
+	for _, key := range v.keys() {
+		if s := v.Mapping[key]; s != nil {
+			if f := s.GetField(name); f != nil {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// keys returns the sorted discriminator values of the mapping.
+func (v Discriminated) keys() []string {
+	keys := make([]string, 0, len(v.Mapping))
+	for key := range v.Mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,140 @@
+package schema_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func newEventValidator() *schema.Discriminated {
+	return &schema.Discriminated{
+		PropertyName: "type",
+		Mapping: map[string]*schema.Schema{
+			"click": {Fields: schema.Fields{
+				"x": {Required: true, Filterable: true, Validator: &schema.Integer{}},
+				"y": {Default: 0, Validator: &schema.Integer{}},
+			}},
+			"key": {Fields: schema.Fields{
+				"type": {Validator: &schema.String{}},
+				"code": {Required: true, Validator: &schema.String{}},
+			}},
+		},
+	}
+}
+
+func TestDiscriminatedCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{
+			Name:     "{PropertyName:type,Mapping:valid}",
+			Compiler: newEventValidator(),
+		},
+		{
+			Name:     "{Mapping:valid}",
+			Compiler: &schema.Discriminated{Mapping: newEventValidator().Mapping},
+			Error:    "no property name defined",
+		},
+		{
+			Name:     "{PropertyName:type}",
+			Compiler: &schema.Discriminated{PropertyName: "type"},
+			Error:    "no mapping defined",
+		},
+		{
+			Name:     "{PropertyName:type,Mapping:{click:nil}}",
+			Compiler: &schema.Discriminated{PropertyName: "type", Mapping: map[string]*schema.Schema{"click": nil}},
+			Error:    "click: no schema defined",
+		},
+		{
+			Name: "{PropertyName:type,Mapping:{click:invalid}}",
+			Compiler: &schema.Discriminated{PropertyName: "type", Mapping: map[string]*schema.Schema{
+				"click": {Fields: schema.Fields{"x": {Validator: &schema.String{Regexp: "["}}}},
+			}},
+			Error: "click: x: invalid regexp: error parsing regexp: missing closing ]: `[`",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestDiscriminatedValidate(t *testing.T) {
+	cases := []fieldValidatorTestCase{
+		{
+			Name:      `Validate(click)`,
+			Validator: newEventValidator(),
+			Input:     map[string]interface{}{"type": "click", "x": 1},
+			Expect:    map[string]interface{}{"type": "click", "x": 1, "y": 0},
+		},
+		{
+			Name:      `Validate(key)`,
+			Validator: newEventValidator(),
+			Input:     map[string]interface{}{"type": "key", "code": "a"},
+			Expect:    map[string]interface{}{"type": "key", "code": "a"},
+		},
+		{
+			Name:      `Validate(click with key fields)`,
+			Validator: newEventValidator(),
+			Input:     map[string]interface{}{"type": "click", "x": 1, "code": "a"},
+			Error:     "code is [invalid field]",
+		},
+		{
+			Name:      `Validate(invalid click)`,
+			Validator: newEventValidator(),
+			Input:     map[string]interface{}{"type": "click"},
+			Error:     "x is [required]",
+		},
+		{
+			Name:      `Validate(unknown type)`,
+			Validator: newEventValidator(),
+			Input:     map[string]interface{}{"type": "scroll"},
+			Error:     "type is [not one of [click, key]]",
+		},
+		{
+			Name:      `Validate(no type)`,
+			Validator: newEventValidator(),
+			Input:     map[string]interface{}{"x": 1},
+			Error:     "type is [required]",
+		},
+		{
+			Name:      `Validate(string)`,
+			Validator: newEventValidator(),
+			Input:     "click",
+			Error:     "not an object",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestDiscriminatedGetField(t *testing.T) {
+	v := newEventValidator()
+	assert.NoError(t, v.Compile(nil))
+	if f := v.GetField("x"); assert.NotNil(t, f) {
+		assert.True(t, f.Filterable)
+	}
+	assert.NotNil(t, v.GetField("code"))
+	if f := v.GetField("type"); assert.NotNil(t, f) {
+		_, err := f.Validator.Validate("scroll")
+		assert.EqualError(t, err, "not one of [click, key]")
+	}
+	assert.Nil(t, v.GetField("unknown"))
+}
+
+func TestDiscriminatedMergePatch(t *testing.T) {
+	s := schema.Schema{Fields: schema.Fields{
+		"event": {Validator: newEventValidator()},
+	}}
+	assert.NoError(t, s.Compile(nil))
+	original := map[string]interface{}{
+		"event": map[string]interface{}{"type": "click", "x": 1, "y": 2},
+	}
+	changes, base := s.PrepareMergePatch(context.Background(), map[string]interface{}{
+		"event": map[string]interface{}{"x": 3},
+	}, &original)
+	doc, errs := s.Validate(changes, base)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"type": "click", "x": 3, "y": 2}, doc["event"])
+}
@@ -0,0 +1,188 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// lookupMX is used to resolve the MX records of a domain. It is a variable so
+// it can be replaced in tests.
+var lookupMX = net.DefaultResolver.LookupMX
+
+// Email validates email address values.
+type Email struct {
+	// AllowedDomains restricts the accepted addresses to the listed domains.
+	AllowedDomains []string
+	// CheckMX rejects addresses whose domain does not have at least one MX
+	// record. Note that a DNS lookup is performed on each validation, and
+	// canceled with the context passed to ValidateCtx.
+	CheckMX bool
+	// MXTimeout sets the timeout of the MX lookup (default 5 seconds).
+	MXTimeout time.Duration
+}
+
+// Compile implements the Compiler interface.
+func (v *Email) Compile(rc ReferenceChecker) error {
+	return nil
+}
+
+// Validate validates and normalizes email address values. The address is
+// lowercased. Internationalized domain names are accepted and converted to
+// their ASCII form for the MX lookup.
+func (v Email) Validate(value interface{}) (interface{}, error) {
+	return v.ValidateCtx(context.Background(), value)
+}
+
+// ValidateCtx implements the FieldValidatorCtx interface. The MX lookup is
+// bound to ctx, MXTimeout being an upper bound of its duration.
+func (v Email) ValidateCtx(ctx context.Context, value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("not a string")
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Name != "" || addr.Address != s {
+		return nil, errors.New("malformed email")
+	}
+	s = strings.ToLower(s)
+	domain := s[strings.LastIndexByte(s, '@')+1:]
+	if len(v.AllowedDomains) > 0 {
+		found := false
+		for _, allowed := range v.AllowedDomains {
+			if strings.ToLower(allowed) == domain {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New("domain not allowed")
+		}
+	}
+	if v.CheckMX {
+		timeout := v.MXTimeout
+		if timeout == 0 {
+			timeout = 5 * time.Second
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		lookupCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		if mx, err := lookupMX(lookupCtx, domainToASCII(domain)); err != nil || len(mx) == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return nil, errors.New("domain has no MX record")
+		}
+	}
+	return s, nil
+}
+
+// domainToASCII converts each non-ASCII label of domain to its punycode form
+// prefixed by "xn--" (RFC 3490).
+func domainToASCII(domain string) string {
+	labels := strings.Split(domain, ".")
+	for i, label := range labels {
+		for _, r := range label {
+			if r >= utf8.RuneSelf {
+				labels[i] = "xn--" + punycode(label)
+				break
+			}
+		}
+	}
+	return strings.Join(labels, ".")
+}
+
+// Punycode parameters as defined by RFC 3492.
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+// punycode encodes s following the RFC 3492 algorithm.
+func punycode(s string) string {
+	runes := []rune(s)
+	out := make([]byte, 0, len(s)+8)
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		}
+	}
+	b := len(out)
+	h := b
+	if b > 0 {
+		out = append(out, '-')
+	}
+	n, delta, bias := rune(punyInitialN), 0, punyInitialBias
+	for h < len(runes) {
+		m := rune(utf8.MaxRune)
+		for _, r := range runes {
+			if r >= n && r < m {
+				m = r
+			}
+		}
+		delta += int(m-n) * (h + 1)
+		n = m
+		for _, r := range runes {
+			if r < n {
+				delta++
+			}
+			if r != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := k - bias
+				if t < punyTMin {
+					t = punyTMin
+				} else if t > punyTMax {
+					t = punyTMax
+				}
+				if q < t {
+					break
+				}
+				out = append(out, punyDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out = append(out, punyDigit(q))
+			bias = punyAdapt(delta, h+1, h == b)
+			delta = 0
+			h++
+		}
+		delta++
+		n++
+	}
+	return string(out)
+}
+
+func punyAdapt(delta, numPoints int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+	k := 0
+	for delta > ((punyBase-punyTMin)*punyTMax)/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punyDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
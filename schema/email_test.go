@@ -0,0 +1,160 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailValidator(t *testing.T) {
+	v, err := Email{}.Validate("john.doe@Example.COM")
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@example.com", v)
+	v, err = Email{}.Validate("John.Doe+tag@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe+tag@example.com", v)
+	v, err = Email{}.Validate("jean@Bücher.de")
+	assert.NoError(t, err)
+	assert.Equal(t, "jean@bücher.de", v)
+	v, err = Email{}.Validate("john.doe")
+	assert.EqualError(t, err, "malformed email")
+	assert.Nil(t, v)
+	v, err = Email{}.Validate("John <john@example.com>")
+	assert.EqualError(t, err, "malformed email")
+	assert.Nil(t, v)
+	v, err = Email{}.Validate(1)
+	assert.EqualError(t, err, "not a string")
+	assert.Nil(t, v)
+	v, err = Email{AllowedDomains: []string{"example.com"}}.Validate("john@EXAMPLE.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "john@example.com", v)
+	v, err = Email{AllowedDomains: []string{"example.com"}}.Validate("john@example.org")
+	assert.EqualError(t, err, "domain not allowed")
+	assert.Nil(t, v)
+}
+
+var networkTests = flag.Bool("network", false, "run tests requiring network access")
+
+func TestEmailValidatorCheckMX(t *testing.T) {
+	defer func(f func(ctx context.Context, name string) ([]*net.MX, error)) {
+		lookupMX = f
+	}(lookupMX)
+	lookupMX = func(ctx context.Context, name string) ([]*net.MX, error) {
+		switch name {
+		case "example.com", "xn--bcher-kva.de":
+			return []*net.MX{{Host: "mx.example.com.", Pref: 10}}, nil
+		case "nomx.com":
+			return nil, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	v, err := Email{CheckMX: true}.Validate("john@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "john@example.com", v)
+	v, err = Email{CheckMX: true}.Validate("jean@bücher.de")
+	assert.NoError(t, err)
+	assert.Equal(t, "jean@bücher.de", v)
+	v, err = Email{CheckMX: true}.Validate("john@nomx.com")
+	assert.EqualError(t, err, "domain has no MX record")
+	assert.Nil(t, v)
+	v, err = Email{CheckMX: true}.Validate("john@unknown.com")
+	assert.EqualError(t, err, "domain has no MX record")
+	assert.Nil(t, v)
+}
+
+func TestEmailValidatorCheckMXContext(t *testing.T) {
+	defer func(f func(ctx context.Context, name string) ([]*net.MX, error)) {
+		lookupMX = f
+	}(lookupMX)
+	var deadline time.Time
+	lookupMX = func(ctx context.Context, name string) ([]*net.MX, error) {
+		deadline, _ = ctx.Deadline()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	// The lookup is bound to the caller's deadline when shorter than
+	// MXTimeout, and the context error is reported.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	v, err := Email{CheckMX: true, MXTimeout: time.Minute}.ValidateCtx(ctx, "john@example.com")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Nil(t, v)
+	assert.Equal(t, want, deadline)
+
+	// MXTimeout still bounds the lookup.
+	start := time.Now()
+	v, err = Email{CheckMX: true, MXTimeout: 10 * time.Millisecond}.ValidateCtx(context.Background(), "john@example.com")
+	assert.EqualError(t, err, "domain has no MX record")
+	assert.Nil(t, v)
+	assert.WithinDuration(t, start.Add(10*time.Millisecond), deadline, time.Second)
+
+	// No lookup is made once the context is canceled.
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	deadline = time.Time{}
+	v, err = Email{CheckMX: true}.ValidateCtx(ctx, "john@example.com")
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, v)
+	assert.True(t, deadline.IsZero())
+}
+
+func TestEmailValidatorCheckMXNetwork(t *testing.T) {
+	if !*networkTests {
+		t.Skip("network tests disabled, use -network to enable")
+	}
+	v, err := Email{CheckMX: true}.Validate("postmaster@gmail.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "postmaster@gmail.com", v)
+	v, err = Email{CheckMX: true}.Validate("john@domain.invalid")
+	assert.EqualError(t, err, "domain has no MX record")
+	assert.Nil(t, v)
+}
+
+func TestDomainToASCII(t *testing.T) {
+	assert.Equal(t, "example.com", domainToASCII("example.com"))
+	assert.Equal(t, "xn--bcher-kva.de", domainToASCII("bücher.de"))
+	assert.Equal(t, "xn--mnchen-3ya.xn--bcher-kva.de", domainToASCII("münchen.bücher.de"))
+	assert.Equal(t, "xn--wgv71a119e.jp", domainToASCII("日本語.jp"))
+}
+
+func TestEmailValidatorCheckMXPrepare(t *testing.T) {
+	defer func(f func(ctx context.Context, name string) ([]*net.MX, error)) {
+		lookupMX = f
+	}(lookupMX)
+	var lookups int
+	lookupMX = func(ctx context.Context, name string) ([]*net.MX, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		lookups++
+		return []*net.MX{{Host: "mx.example.com.", Pref: 10}}, nil
+	}
+	s := Schema{Fields: Fields{"email": {Validator: &Email{CheckMX: true}}}}
+	assert.NoError(t, s.Compile(nil))
+	original := map[string]interface{}{"email": "john@example.com"}
+	payload := map[string]interface{}{"email": "jane@example.com"}
+
+	// The domain of an updated address is looked up once, by Validate.
+	changes, base := s.Prepare(context.Background(), payload, &original, false)
+	assert.Equal(t, 0, lookups)
+	doc, errs := s.ValidateCtx(context.Background(), changes, base)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"email": "jane@example.com"}, doc)
+	assert.Equal(t, 1, lookups)
+
+	// No lookup is made once the request is canceled.
+	lookups = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	changes, base = s.Prepare(ctx, payload, &original, false)
+	_, errs = s.ValidateCtx(ctx, changes, base)
+	assert.NotEmpty(t, errs)
+	assert.Equal(t, 0, lookups)
+}
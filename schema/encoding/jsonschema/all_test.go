@@ -3,10 +3,14 @@
 package jsonschema_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"sort"
 	"testing"
 
 	"github.com/rs/rest-layer/schema"
+	"github.com/rs/rest-layer/schema/encoding/jsonschema"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,11 +21,79 @@ func TestEncoder(t *testing.T) {
 			schema: schema.Schema{
 				Fields: schema.Fields{
 					"i": {
-						Validator: &dummyValidator{},
+						Description: "not representable",
+						Validator:   &dummyValidator{},
 					},
 				},
 			},
-			expectError: "not implemented",
+			expect: `{
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"i": {
+						"description": "not representable"
+					}
+				}
+			}`,
+		},
+		{
+			name: "Hidden=true",
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"name": {
+						Validator: &schema.String{},
+					},
+					"secret": {
+						Required:  true,
+						Hidden:    true,
+						Validator: &schema.String{},
+					},
+				},
+			},
+			expect: `{
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"name": {
+						"type": "string"
+					}
+				}
+			}`,
+		},
+		{
+			name: "Schema!=nil",
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"student": {
+						Description: "The student",
+						Schema: &schema.Schema{
+							Fields: schema.Fields{
+								"name": {
+									Required:  true,
+									Validator: &schema.String{},
+								},
+							},
+						},
+					},
+				},
+			},
+			expect: `{
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"student": {
+						"type": "object",
+						"description": "The student",
+						"additionalProperties": false,
+						"properties": {
+							"name": {
+								"type": "string"
+							}
+						},
+						"required": ["name"]
+					}
+				}
+			}`,
 		},
 		{
 			name: "Validator=dummyBuilder",
@@ -66,6 +138,71 @@ func TestEncoder(t *testing.T) {
 				}
 			}`,
 		},
+		{
+			name: "Compute",
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"name": {
+						Compute: func(ctx context.Context, doc map[string]interface{}) (interface{}, error) {
+							return "", nil
+						},
+						Validator: &schema.String{},
+					},
+				},
+			},
+			expect: `{
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"name": {
+						"type": "string",
+						"readOnly": true
+					}
+				}
+			}`,
+		},
+		{
+			name: "Nullable=true",
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"name": {
+						Nullable:  true,
+						Validator: &schema.String{},
+					},
+				},
+			},
+			expect: `{
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"name": {
+						"type": ["string", "null"]
+					}
+				}
+			}`,
+		},
+		// deprecated is defined by JSON Schema draft 2019-09.
+		{
+			name: "Deprecated=true",
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"name": {
+						Deprecated: true,
+						Validator:  &schema.String{},
+					},
+				},
+			},
+			expect: `{
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"name": {
+						"type": "string",
+						"deprecated": true
+					}
+				}
+			}`,
+		},
 		{
 			name: `Validator=String,type(Default)==string`,
 			schema: schema.Schema{
@@ -338,3 +475,81 @@ func TestEncoder(t *testing.T) {
 		testCases[i].Run(t)
 	}
 }
+
+func TestEncoderWarnings(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"a": {
+				Validator: &dummyValidator{},
+			},
+			"b": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"c": {
+							Validator: &schema.Array{Values: schema.Field{Validator: &dummyValidator{}}},
+						},
+					},
+				},
+			},
+			"d": {
+				Validator: &schema.String{},
+			},
+		},
+	}
+	enc := jsonschema.NewEncoder(new(bytes.Buffer))
+	assert.NoError(t, enc.Encode(&s))
+	warnings := enc.Warnings()
+	sort.Strings(warnings)
+	assert.Equal(t, []string{"a: not implemented", "b.c: not implemented"}, warnings)
+}
+
+func TestEncoderWarningsNested(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"list": {
+				Validator: &schema.Array{Values: schema.Field{
+					Validator: &schema.AnyOf{&schema.String{}, &dummyValidator{}},
+				}},
+			},
+			"dict": {
+				Validator: &schema.Dict{Values: schema.Field{Validator: &dummyValidator{}}},
+			},
+			"items": {
+				Validator: &schema.Array{Values: schema.Field{
+					Validator: &schema.Object{Schema: &schema.Schema{
+						Fields: schema.Fields{"e": {Validator: &dummyValidator{}}},
+					}},
+				}},
+			},
+		},
+	}
+	b := new(bytes.Buffer)
+	enc := jsonschema.NewEncoder(b)
+	assert.NoError(t, enc.Encode(&s))
+	warnings := enc.Warnings()
+	sort.Strings(warnings)
+	assert.Equal(t, []string{"dict: not implemented", "items.e: not implemented", "list: not implemented"}, warnings)
+	// Only the nested validators which can not be represented are permissive.
+	assert.JSONEq(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"list": {
+				"type": "array",
+				"items": {"anyOf": [{"type": "string"}, {}]}
+			},
+			"dict": {
+				"type": "object",
+				"additionalProperties": true
+			},
+			"items": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"additionalProperties": false,
+					"properties": {"e": {}}
+				}
+			}
+		}
+	}`, b.String())
+}
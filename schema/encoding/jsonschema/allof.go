@@ -10,11 +10,15 @@ type allOfBuilder schema.AllOf
 
 var (
 	//ErrNoSchemaList is returned when trying to JSON Encode an empty
-	//schema.AnyOf or schema.AllOf slice.
+	//schema.AnyOf, schema.AllOf or schema.OneOf slice.
 	ErrNoSchemaList = errors.New("at least one schema must be specified")
 )
 
 func (v allOfBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	return v.buildJSONSchema(&encodeState{}, "")
+}
+
+func (v allOfBuilder) buildJSONSchema(e *encodeState, path string) (map[string]interface{}, error) {
 	if len(v) == 0 {
 		return nil, ErrNoSchemaList
 	}
@@ -22,11 +26,7 @@ func (v allOfBuilder) BuildJSONSchema() (map[string]interface{}, error) {
 	subSchemas := make([]map[string]interface{}, 0, len(v))
 
 	for i := range v {
-		b, err := ValidatorBuilder(v[i])
-		if err != nil {
-			return nil, err
-		}
-		schema, err := b.BuildJSONSchema()
+		schema, err := e.buildValidator(v[i], path)
 		if err != nil {
 			return nil, err
 		}
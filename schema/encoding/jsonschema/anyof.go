@@ -5,6 +5,10 @@ import "github.com/rs/rest-layer/schema"
 type anyOfBuilder schema.AnyOf
 
 func (v anyOfBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	return v.buildJSONSchema(&encodeState{}, "")
+}
+
+func (v anyOfBuilder) buildJSONSchema(e *encodeState, path string) (map[string]interface{}, error) {
 	if len(v) == 0 {
 		return nil, ErrNoSchemaList
 	}
@@ -12,11 +16,7 @@ func (v anyOfBuilder) BuildJSONSchema() (map[string]interface{}, error) {
 	subSchemas := make([]map[string]interface{}, 0, len(v))
 
 	for i := range v {
-		b, err := ValidatorBuilder(v[i])
-		if err != nil {
-			return nil, err
-		}
-		schema, err := b.BuildJSONSchema()
+		schema, err := e.buildValidator(v[i], path)
 		if err != nil {
 			return nil, err
 		}
@@ -5,6 +5,10 @@ import "github.com/rs/rest-layer/schema"
 type arrayBuilder schema.Array
 
 func (v arrayBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	return v.buildJSONSchema(&encodeState{}, "")
+}
+
+func (v arrayBuilder) buildJSONSchema(e *encodeState, path string) (map[string]interface{}, error) {
 	m := map[string]interface{}{
 		"type": "array",
 	}
@@ -14,15 +18,15 @@ func (v arrayBuilder) BuildJSONSchema() (map[string]interface{}, error) {
 	if v.MaxLen > 0 {
 		m["maxItems"] = v.MaxLen
 	}
+	if v.Unique && !v.DedupeSilently {
+		m["uniqueItems"] = true
+	}
 
 	// Retrieve values validator JSON schema.
 	var valuesSchema map[string]interface{}
 	if v.Values.Validator != nil {
-		b, err := ValidatorBuilder(v.Values.Validator)
-		if err != nil {
-			return nil, err
-		}
-		valuesSchema, err = b.BuildJSONSchema()
+		var err error
+		valuesSchema, err = e.buildValidator(v.Values.Validator, path)
 		if err != nil {
 			return nil, err
 		}
@@ -58,6 +58,30 @@ func TestArray(t *testing.T) {
 			},
 			customValidate: fieldValidator("a", `{"type": "array", "maxItems": 42}`),
 		},
+		{
+			// http://json-schema.org/latest/json-schema-validation.html#rfc.section.5.12
+			name: "Unique=true",
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"a": schema.Field{
+						Validator: &schema.Array{Unique: true},
+					},
+				},
+			},
+			customValidate: fieldValidator("a", `{"type": "array", "uniqueItems": true}`),
+		},
+		{
+			// Duplicates are accepted, and removed, by the validator.
+			name: "Unique=true,DedupeSilently=true",
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"a": schema.Field{
+						Validator: &schema.Array{Unique: true, DedupeSilently: true},
+					},
+				},
+			},
+			customValidate: fieldValidator("a", `{"type": "array"}`),
+		},
 	}
 	for i := range testCases {
 		testCases[i].Run(t)
@@ -0,0 +1,20 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type binaryBuilder schema.Binary
+
+func (v binaryBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	m := map[string]interface{}{
+		"type":            "string",
+		"contentEncoding": "base64",
+	}
+	if v.MaxLen > 0 {
+		// Size of the padded base64 representation.
+		m["maxLength"] = (v.MaxLen + 2) / 3 * 4
+	}
+	if len(v.AllowedTypes) == 1 {
+		m["contentMediaType"] = v.AllowedTypes[0]
+	}
+	return m, nil
+}
@@ -0,0 +1,45 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestBinaryValidatorEncode(t *testing.T) {
+	testCases := []encoderTestCase{
+		{
+			name: ``,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"data": {
+						Validator: &schema.Binary{},
+					},
+				},
+			},
+			customValidate: fieldValidator("data", `{
+				"type": "string",
+				"contentEncoding": "base64"
+			}`),
+		},
+		{
+			name: `MaxLen=10,AllowedTypes=["image/png"]`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"data": {
+						Validator: &schema.Binary{MaxLen: 10, AllowedTypes: []string{"image/png"}},
+					},
+				},
+			},
+			customValidate: fieldValidator("data", `{
+				"type": "string",
+				"contentEncoding": "base64",
+				"contentMediaType": "image/png",
+				"maxLength": 16
+			}`),
+		},
+	}
+	for i := range testCases {
+		testCases[i].Run(t)
+	}
+}
@@ -0,0 +1,20 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type colorBuilder schema.Color
+
+func (v colorBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	m := map[string]interface{}{
+		"type": "string",
+	}
+	if !v.AllowNamed {
+		// Named colors can't be expressed with a pattern.
+		if v.AllowAlpha {
+			m["pattern"] = "^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$"
+		} else {
+			m["pattern"] = "^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$"
+		}
+	}
+	return m, nil
+}
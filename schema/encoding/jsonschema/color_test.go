@@ -0,0 +1,54 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestColorValidatorEncode(t *testing.T) {
+	testCases := []encoderTestCase{
+		{
+			name: ``,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"color": {
+						Validator: &schema.Color{},
+					},
+				},
+			},
+			customValidate: fieldValidator("color", `{
+				"type": "string",
+				"pattern": "^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$"
+			}`),
+		},
+		{
+			name: `AllowAlpha=true`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"color": {
+						Validator: &schema.Color{AllowAlpha: true},
+					},
+				},
+			},
+			customValidate: fieldValidator("color", `{
+				"type": "string",
+				"pattern": "^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$"
+			}`),
+		},
+		{
+			name: `AllowNamed=true`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"color": {
+						Validator: &schema.Color{AllowNamed: true},
+					},
+				},
+			},
+			customValidate: fieldValidator("color", `{"type": "string"}`),
+		},
+	}
+	for i := range testCases {
+		testCases[i].Run(t)
+	}
+}
@@ -0,0 +1,12 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type countryCodeBuilder schema.CountryCode
+
+func (v countryCodeBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"type": "string",
+		"enum": schema.CountryCode(v).Codes(),
+	}, nil
+}
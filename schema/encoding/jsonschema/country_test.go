@@ -0,0 +1,24 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestCountryCodeValidatorEncode(t *testing.T) {
+	codes, _ := json.Marshal(schema.CountryCode{Format: schema.CountryAlpha3}.Codes())
+	testCase := encoderTestCase{
+		name: ``,
+		schema: schema.Schema{
+			Fields: schema.Fields{
+				"country": {
+					Validator: &schema.CountryCode{Format: schema.CountryAlpha3},
+				},
+			},
+		},
+		customValidate: fieldValidator("country", `{"type": "string", "enum": `+string(codes)+`}`),
+	}
+	testCase.Run(t)
+}
@@ -0,0 +1,25 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestCreditCardValidatorEncode(t *testing.T) {
+	testCase := encoderTestCase{
+		name: ``,
+		schema: schema.Schema{
+			Fields: schema.Fields{
+				"card": {
+					Validator: &schema.CreditCard{Mask: true},
+				},
+			},
+		},
+		customValidate: fieldValidator("card", `{
+			"type": "string",
+			"pattern": "^[0-9 -]+$"
+		}`),
+	}
+	testCase.Run(t)
+}
@@ -0,0 +1,12 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type decimalBuilder schema.Decimal
+
+func (v decimalBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"type":    "string",
+		"pattern": `^[-+]?[0-9]+(\.[0-9]+)?$`,
+	}, nil
+}
@@ -0,0 +1,25 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestDecimalValidatorEncode(t *testing.T) {
+	testCase := encoderTestCase{
+		name: ``,
+		schema: schema.Schema{
+			Fields: schema.Fields{
+				"amount": {
+					Validator: &schema.Decimal{DecimalPlaces: 2},
+				},
+			},
+		},
+		customValidate: fieldValidator("amount", `{
+			"type": "string",
+			"pattern": "^[-+]?[0-9]+(\\.[0-9]+)?$"
+		}`),
+	}
+	testCase.Run(t)
+}
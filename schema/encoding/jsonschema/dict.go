@@ -17,6 +17,10 @@ var (
 )
 
 func (v dictBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	return v.buildJSONSchema(&encodeState{}, "")
+}
+
+func (v dictBuilder) buildJSONSchema(e *encodeState, path string) (map[string]interface{}, error) {
 	m := map[string]interface{}{
 		"type": "object",
 	}
@@ -56,12 +60,14 @@ func (v dictBuilder) BuildJSONSchema() (map[string]interface{}, error) {
 
 	// Retrieve values validator JSON schema.
 	var valuesSchema map[string]interface{}
-	if v.Values.Validator != nil {
-		b, err := ValidatorBuilder(v.Values.Validator)
-		if err != nil {
+	if v.Values.Schema != nil {
+		valuesSchema = map[string]interface{}{}
+		if err := e.addSchemaProperties(valuesSchema, v.Values.Schema, path+"."); err != nil {
 			return nil, err
 		}
-		valuesSchema, err = b.BuildJSONSchema()
+	} else if v.Values.Validator != nil {
+		var err error
+		valuesSchema, err = e.buildValidator(v.Values.Validator, path)
 		if err != nil {
 			return nil, err
 		}
@@ -285,6 +285,35 @@ func TestDictValidatorEncode(t *testing.T) {
 				}
 			}`),
 		},
+		{
+			name: `Values.Schema={title:String}"`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"d": {
+						Validator: &schema.Dict{
+							Values: schema.Field{
+								Schema: &schema.Schema{
+									Fields: schema.Fields{
+										"title": {Required: true, Validator: &schema.String{}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			customValidate: fieldValidator("d", `{
+				"type": "object",
+				"additionalProperties": {
+					"type": "object",
+					"additionalProperties": false,
+					"properties": {
+						"title": {"type": "string"}
+					},
+					"required": ["title"]
+				}
+			}`),
+		},
 	}
 	for i := range testCases {
 		testCases[i].Run(t)
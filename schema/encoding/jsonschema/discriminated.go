@@ -0,0 +1,62 @@
+package jsonschema
+
+import (
+	"sort"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+type discriminatedBuilder schema.Discriminated
+
+func (v discriminatedBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	return v.buildJSONSchema(&encodeState{}, "")
+}
+
+func (v discriminatedBuilder) buildJSONSchema(e *encodeState, path string) (map[string]interface{}, error) {
+	if len(v.Mapping) == 0 {
+		return nil, ErrNoSchemaList
+	}
+	keys := make([]string, 0, len(v.Mapping))
+	for key := range v.Mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	subSchemas := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		if v.Mapping[key] == nil {
+			return nil, ErrNoSchema
+		}
+		m := map[string]interface{}{}
+		if err := e.addSchemaProperties(m, v.Mapping[key], path+"."); err != nil {
+			return nil, err
+		}
+		// The discriminator is always required and set to the key.
+		props, _ := m["properties"].(map[string]interface{})
+		if props == nil {
+			props = map[string]interface{}{}
+			m["properties"] = props
+		}
+		props[v.PropertyName] = map[string]interface{}{"enum": []string{key}}
+		required, _ := m["required"].([]string)
+		if !containsString(required, v.PropertyName) {
+			required = append(required, v.PropertyName)
+			sort.Strings(required)
+			m["required"] = required
+		}
+		subSchemas = append(subSchemas, m)
+	}
+
+	return map[string]interface{}{
+		"oneOf": subSchemas,
+	}, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
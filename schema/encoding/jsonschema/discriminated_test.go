@@ -0,0 +1,68 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestDiscriminatedValidatorEncode(t *testing.T) {
+	testCases := []encoderTestCase{
+		{
+			name: `{}`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"a": {
+						Validator: &schema.Discriminated{PropertyName: "type"},
+					},
+				},
+			},
+			expectError: "at least one schema must be specified",
+		},
+		{
+			name: `{click,key}`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"a": {
+						Validator: &schema.Discriminated{
+							PropertyName: "type",
+							Mapping: map[string]*schema.Schema{
+								"click": {Fields: schema.Fields{
+									"x": {Required: true, Validator: &schema.Integer{}},
+								}},
+								"key": {Fields: schema.Fields{
+									"code": {Validator: &schema.String{}},
+								}},
+							},
+						},
+					},
+				},
+			},
+			customValidate: fieldValidator("a", `{
+				"oneOf": [
+					{
+						"type": "object",
+						"additionalProperties": false,
+						"properties": {
+							"type": {"enum": ["click"]},
+							"x": {"type": "integer"}
+						},
+						"required": ["type", "x"]
+					},
+					{
+						"type": "object",
+						"additionalProperties": false,
+						"properties": {
+							"type": {"enum": ["key"]},
+							"code": {"type": "string"}
+						},
+						"required": ["type"]
+					}
+				]
+			}`),
+		},
+	}
+	for i := range testCases {
+		testCases[i].Run(t)
+	}
+}
@@ -0,0 +1,12 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type emailBuilder schema.Email
+
+func (v emailBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"type":   "string",
+		"format": "email",
+	}, nil
+}
@@ -0,0 +1,25 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestEmailValidatorEncode(t *testing.T) {
+	testCase := encoderTestCase{
+		name: ``,
+		schema: schema.Schema{
+			Fields: schema.Fields{
+				"email": {
+					Validator: &schema.Email{},
+				},
+			},
+		},
+		customValidate: fieldValidator("email", `{
+			"type": "string",
+			"format": "email"
+		}`),
+	}
+	testCase.Run(t)
+}
@@ -9,11 +9,14 @@ import (
 
 // Encoder writes the JSON Schema representation of a schema.Schema to an output
 // stream. Note that only a sub-set of the FieldValidator types in the schema
-// package is supported at the moment. Custom validators are also not yet
-// handled. Attempting to encode a schema containing such fields will result in
-// a ErrNotImplemented error.
+// package is supported at the moment. Custom validators must implement the
+// Builder interface to be represented. Validators which can not be
+// represented, including nested ones (i.e.: the values of an array), are
+// encoded with a permissive (empty) schema and a warning is recorded, see
+// Warnings. Hidden fields are omitted.
 type Encoder struct {
-	w io.Writer
+	w        io.Writer
+	warnings []string
 }
 
 // NewEncoder returns a new JSONSchema Encoder that writes to w.
@@ -25,7 +28,10 @@ func NewEncoder(w io.Writer) *Encoder {
 // a newline character.
 func (e *Encoder) Encode(s *schema.Schema) error {
 	m := make(map[string]interface{})
-	if err := addSchemaProperties(m, s); err != nil {
+	state := &encodeState{}
+	err := state.addSchemaProperties(m, s, "")
+	e.warnings = state.warnings
+	if err != nil {
 		return err
 	}
 	enc := json.NewEncoder(e.w)
@@ -33,6 +39,12 @@ func (e *Encoder) Encode(s *schema.Schema) error {
 
 }
 
+// Warnings returns the warnings recorded by the last call to Encode, one per
+// field that could not be represented.
+func (e *Encoder) Warnings() []string {
+	return e.warnings
+}
+
 // The Builder interface should be implemented by custom schema.FieldValidator
 // implementations to allow JSON Schema serialization.
 type Builder interface {
@@ -24,9 +24,17 @@ func (v floatBuilder) BuildJSONSchema() (map[string]interface{}, error) {
 
 func addBoundariesProperties(m map[string]interface{}, b *schema.Boundaries) {
 	if !math.IsNaN(b.Min) && !math.IsInf(b.Min, -1) {
-		m["minimum"] = b.Min
+		if b.ExclusiveMin {
+			m["exclusiveMinimum"] = b.Min
+		} else {
+			m["minimum"] = b.Min
+		}
 	}
 	if !math.IsNaN(b.Max) && !math.IsInf(b.Max, 1) {
-		m["maximum"] = b.Max
+		if b.ExclusiveMax {
+			m["exclusiveMaximum"] = b.Max
+		} else {
+			m["maximum"] = b.Max
+		}
 	}
 }
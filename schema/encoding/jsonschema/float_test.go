@@ -51,6 +51,24 @@ func TestFloatValidatorEncode(t *testing.T) {
 			},
 			customValidate: fieldValidator("f", `{"type": "number", "minimum": 0, "maximum": 100}`),
 		},
+		{
+			name: "Boundaries={Min:0,Max:100,ExclusiveMin,ExclusiveMax}",
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"f": schema.Field{
+						Validator: &schema.Float{
+							Boundaries: &schema.Boundaries{
+								Min:          0,
+								Max:          100,
+								ExclusiveMin: true,
+								ExclusiveMax: true,
+							},
+						},
+					},
+				},
+			},
+			customValidate: fieldValidator("f", `{"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 100}`),
+		},
 		{
 			name: "Boundaries={Min:0,Max:Inf}",
 			schema: schema.Schema{
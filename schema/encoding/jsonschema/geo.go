@@ -0,0 +1,42 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type geoPointBuilder schema.GeoPoint
+
+func (v geoPointBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	lng := map[string]interface{}{"type": "number", "minimum": -180, "maximum": 180}
+	lat := map[string]interface{}{"type": "number", "minimum": -90, "maximum": 90}
+	position := map[string]interface{}{
+		"type":     "array",
+		"minItems": 2,
+		"maxItems": 2,
+		"items":    []map[string]interface{}{lng, lat},
+	}
+	switch v.Format {
+	case "object":
+		return map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"lat": lat,
+				"lng": lng,
+			},
+			"required":             []string{"lat", "lng"},
+			"additionalProperties": false,
+		}, nil
+	case "geojson-array":
+		return position, nil
+	}
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"type": map[string]interface{}{
+				"type": "string",
+				"enum": []string{"Point"},
+			},
+			"coordinates": position,
+		},
+		"required":             []string{"type", "coordinates"},
+		"additionalProperties": false,
+	}, nil
+}
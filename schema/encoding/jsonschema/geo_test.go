@@ -0,0 +1,84 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestGeoPointValidatorEncode(t *testing.T) {
+	testCase := encoderTestCase{
+		name: ``,
+		schema: schema.Schema{
+			Fields: schema.Fields{
+				"location": {
+					Validator: &schema.GeoPoint{},
+				},
+			},
+		},
+		customValidate: fieldValidator("location", `{
+			"type": "object",
+			"properties": {
+				"type": {"type": "string", "enum": ["Point"]},
+				"coordinates": {
+					"type": "array",
+					"minItems": 2,
+					"maxItems": 2,
+					"items": [
+						{"type": "number", "minimum": -180, "maximum": 180},
+						{"type": "number", "minimum": -90, "maximum": 90}
+					]
+				}
+			},
+			"required": ["type", "coordinates"],
+			"additionalProperties": false
+		}`),
+	}
+	testCase.Run(t)
+}
+
+func TestGeoPointValidatorEncodeFormats(t *testing.T) {
+	testCases := []encoderTestCase{
+		{
+			name: `{Format:object}`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"location": {
+						Validator: &schema.GeoPoint{Format: "object"},
+					},
+				},
+			},
+			customValidate: fieldValidator("location", `{
+				"type": "object",
+				"properties": {
+					"lat": {"type": "number", "minimum": -90, "maximum": 90},
+					"lng": {"type": "number", "minimum": -180, "maximum": 180}
+				},
+				"required": ["lat", "lng"],
+				"additionalProperties": false
+			}`),
+		},
+		{
+			name: `{Format:geojson-array}`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"location": {
+						Validator: &schema.GeoPoint{Format: "geojson-array"},
+					},
+				},
+			},
+			customValidate: fieldValidator("location", `{
+				"type": "array",
+				"minItems": 2,
+				"maxItems": 2,
+				"items": [
+					{"type": "number", "minimum": -180, "maximum": 180},
+					{"type": "number", "minimum": -90, "maximum": 90}
+				]
+			}`),
+		},
+	}
+	for i := range testCases {
+		testCases[i].Run(t)
+	}
+}
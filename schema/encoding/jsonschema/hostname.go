@@ -0,0 +1,17 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type hostnameBuilder schema.Hostname
+
+func (v hostnameBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	m := map[string]interface{}{
+		"type": "string",
+	}
+	if !v.AllowWildcard && !v.AllowIDN {
+		// Wildcards and internationalized names are not valid hostnames
+		// before normalization.
+		m["format"] = "hostname"
+	}
+	return m, nil
+}
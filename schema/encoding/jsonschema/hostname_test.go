@@ -0,0 +1,42 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestHostnameValidatorEncode(t *testing.T) {
+	testCases := []encoderTestCase{
+		{
+			name: `Hostname{}`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"host": {
+						Validator: &schema.Hostname{},
+					},
+				},
+			},
+			customValidate: fieldValidator("host", `{
+				"type": "string",
+				"format": "hostname"
+			}`),
+		},
+		{
+			name: `Hostname{AllowWildcard:true}`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"host": {
+						Validator: &schema.Hostname{AllowWildcard: true},
+					},
+				},
+			},
+			customValidate: fieldValidator("host", `{
+				"type": "string"
+			}`),
+		},
+	}
+	for i := range testCases {
+		testCases[i].Run(t)
+	}
+}
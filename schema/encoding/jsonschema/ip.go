@@ -5,12 +5,31 @@ import "github.com/rs/rest-layer/schema"
 type ipBuilder schema.IP
 
 func (v ipBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	if v.CIDR {
+		return cidrBuilder{}.BuildJSONSchema()
+	}
 	m := map[string]interface{}{
 		"type": "string",
-		"oneOf": []map[string]interface{}{
+	}
+	switch {
+	case v.AllowV4 && !v.AllowV6:
+		m["format"] = "ipv4"
+	case v.AllowV6 && !v.AllowV4:
+		m["format"] = "ipv6"
+	default:
+		m["oneOf"] = []map[string]interface{}{
 			{"format": "ipv4"},
 			{"format": "ipv6"},
-		},
+		}
 	}
 	return m, nil
 }
+
+type cidrBuilder schema.CIDR
+
+func (v cidrBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	// JSON Schema does not define a format for IP networks.
+	return map[string]interface{}{
+		"type": "string",
+	}, nil
+}
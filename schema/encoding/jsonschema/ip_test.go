@@ -26,3 +26,63 @@ func TestIPValidatorEncode(t *testing.T) {
 	}
 	testCase.Run(t)
 }
+
+func TestIPValidatorEncodeVersion(t *testing.T) {
+	testCases := []encoderTestCase{
+		{
+			name: `AllowV4=true`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"ip": {
+						Validator: &schema.IP{AllowV4: true},
+					},
+				},
+			},
+			customValidate: fieldValidator("ip", `{"type": "string", "format": "ipv4"}`),
+		},
+		{
+			name: `AllowV6=true`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"ip": {
+						Validator: &schema.IP{AllowV6: true},
+					},
+				},
+			},
+			customValidate: fieldValidator("ip", `{"type": "string", "format": "ipv6"}`),
+		},
+	}
+	for i := range testCases {
+		testCases[i].Run(t)
+	}
+}
+
+func TestIPValidatorEncodeCIDR(t *testing.T) {
+	testCase := encoderTestCase{
+		name: `CIDR=true`,
+		schema: schema.Schema{
+			Fields: schema.Fields{
+				"net": {
+					Validator: &schema.IP{CIDR: true, AllowV4: true},
+				},
+			},
+		},
+		customValidate: fieldValidator("net", `{"type": "string"}`),
+	}
+	testCase.Run(t)
+}
+
+func TestCIDRValidatorEncode(t *testing.T) {
+	testCase := encoderTestCase{
+		name: ``,
+		schema: schema.Schema{
+			Fields: schema.Fields{
+				"net": {
+					Validator: &schema.CIDR{},
+				},
+			},
+		},
+		customValidate: fieldValidator("net", `{"type": "string"}`),
+	}
+	testCase.Run(t)
+}
@@ -0,0 +1,18 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type jsonBuilder schema.JSON
+
+func (v jsonBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	switch {
+	case v.AllowObject && v.AllowArray:
+		m["type"] = []string{"object", "array"}
+	case v.AllowObject:
+		m["type"] = "object"
+	case v.AllowArray:
+		m["type"] = "array"
+	}
+	return m, nil
+}
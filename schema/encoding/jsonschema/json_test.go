@@ -0,0 +1,48 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestJSONValidatorEncode(t *testing.T) {
+	testCases := []encoderTestCase{
+		{
+			name: ``,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"metadata": {
+						Validator: &schema.JSON{MaxBytes: 1024},
+					},
+				},
+			},
+			customValidate: fieldValidator("metadata", `{}`),
+		},
+		{
+			name: `AllowObject=true`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"metadata": {
+						Validator: &schema.JSON{AllowObject: true},
+					},
+				},
+			},
+			customValidate: fieldValidator("metadata", `{"type": "object"}`),
+		},
+		{
+			name: `AllowObject=true,AllowArray=true`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"metadata": {
+						Validator: &schema.JSON{AllowObject: true, AllowArray: true},
+					},
+				},
+			},
+			customValidate: fieldValidator("metadata", `{"type": ["object", "array"]}`),
+		},
+	}
+	for i := range testCases {
+		testCases[i].Run(t)
+	}
+}
@@ -0,0 +1,13 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type languageTagBuilder schema.LanguageTag
+
+func (v languageTagBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	// Supported tags also accept their sub-tags so they can't be expressed as
+	// an enum.
+	return map[string]interface{}{
+		"type": "string",
+	}, nil
+}
@@ -0,0 +1,26 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestLanguageTagValidatorEncode(t *testing.T) {
+	testCases := []encoderTestCase{
+		{
+			name: ``,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"locale": {
+						Validator: &schema.LanguageTag{Supported: []string{"en"}},
+					},
+				},
+			},
+			customValidate: fieldValidator("locale", `{"type": "string"}`),
+		},
+	}
+	for i := range testCases {
+		testCases[i].Run(t)
+	}
+}
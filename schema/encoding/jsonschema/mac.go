@@ -0,0 +1,12 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type macAddressBuilder schema.MACAddress
+
+func (v macAddressBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	// JSON Schema does not define a format for MAC addresses.
+	return map[string]interface{}{
+		"type": "string",
+	}, nil
+}
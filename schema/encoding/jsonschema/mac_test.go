@@ -0,0 +1,22 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestMACAddressValidatorEncode(t *testing.T) {
+	testCase := encoderTestCase{
+		name: ``,
+		schema: schema.Schema{
+			Fields: schema.Fields{
+				"mac": {
+					Validator: &schema.MACAddress{},
+				},
+			},
+		},
+		customValidate: fieldValidator("mac", `{"type": "string"}`),
+	}
+	testCase.Run(t)
+}
@@ -0,0 +1,12 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type mediaTypeBuilder schema.MediaType
+
+func (v mediaTypeBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	// JSON Schema does not define a format for media types.
+	return map[string]interface{}{
+		"type": "string",
+	}, nil
+}
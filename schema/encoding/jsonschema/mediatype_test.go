@@ -0,0 +1,26 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestMediaTypeValidatorEncode(t *testing.T) {
+	testCases := []encoderTestCase{
+		{
+			name: ``,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"content_type": {
+						Validator: &schema.MediaType{Allowed: []string{"image/*"}},
+					},
+				},
+			},
+			customValidate: fieldValidator("content_type", `{"type": "string"}`),
+		},
+	}
+	for i := range testCases {
+		testCases[i].Run(t)
+	}
+}
@@ -15,12 +15,16 @@ var (
 )
 
 func (v objectBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	return v.buildJSONSchema(&encodeState{}, "")
+}
+
+func (v objectBuilder) buildJSONSchema(e *encodeState, path string) (map[string]interface{}, error) {
 	if v.Schema == nil {
 		return nil, ErrNoSchema
 	}
 
 	m := map[string]interface{}{}
-	err := addSchemaProperties(m, v.Schema)
+	err := e.addSchemaProperties(m, v.Schema, path+".")
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,30 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type oneOfBuilder schema.OneOf
+
+func (v oneOfBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	return v.buildJSONSchema(&encodeState{}, "")
+}
+
+func (v oneOfBuilder) buildJSONSchema(e *encodeState, path string) (map[string]interface{}, error) {
+	if len(v) == 0 {
+		return nil, ErrNoSchemaList
+	}
+
+	subSchemas := make([]map[string]interface{}, 0, len(v))
+
+	for i := range v {
+		schema, err := e.buildValidator(v[i], path)
+		if err != nil {
+			return nil, err
+		}
+		subSchemas = append(subSchemas, schema)
+	}
+
+	return map[string]interface{}{
+		"oneOf": subSchemas,
+	}, nil
+
+}
@@ -0,0 +1,45 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestOneOfValidatorEncode(t *testing.T) {
+	testCases := []encoderTestCase{
+		{
+			name: `[]`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"a": {
+						Validator: &schema.OneOf{},
+					},
+				},
+			},
+			expectError: "at least one schema must be specified",
+		},
+		{
+			name: `[Integer,String]`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"a": {
+						Validator: &schema.OneOf{
+							&schema.Integer{},
+							&schema.String{},
+						},
+					},
+				},
+			},
+			customValidate: fieldValidator("a", `{
+				"oneOf": [
+					{"type": "integer"},
+					{"type": "string"}
+				]
+			}`),
+		},
+	}
+	for i := range testCases {
+		testCases[i].Run(t)
+	}
+}
@@ -2,6 +2,7 @@ package jsonschema
 
 import (
 	"errors"
+	"fmt"
 	"sort"
 
 	"github.com/rs/rest-layer/schema"
@@ -13,7 +14,18 @@ var (
 	ErrNotImplemented = errors.New("not implemented")
 )
 
-func addSchemaProperties(m map[string]interface{}, s *schema.Schema) (err error) {
+// encodeState holds the state of a single schema encoding.
+type encodeState struct {
+	warnings []string
+}
+
+// stateBuilder is implemented by the builders of validators holding nested
+// validators or schemas, so those are encoded with the state of the encoding.
+type stateBuilder interface {
+	buildJSONSchema(e *encodeState, path string) (map[string]interface{}, error)
+}
+
+func (e *encodeState) addSchemaProperties(m map[string]interface{}, s *schema.Schema, path string) (err error) {
 	if s == nil {
 		return
 	}
@@ -29,25 +41,25 @@ func addSchemaProperties(m map[string]interface{}, s *schema.Schema) (err error)
 		m["maxProperties"] = s.MaxLen
 	}
 	if len(s.Fields) > 0 {
-		err = addFields(m, s.Fields)
+		err = e.addFields(m, s.Fields, path)
 	}
 
 	return err
 }
 
-func addFields(m map[string]interface{}, fields schema.Fields) error {
+func (e *encodeState) addFields(m map[string]interface{}, fields schema.Fields, path string) error {
 	props := make(map[string]interface{}, len(fields))
 	required := []string{}
 
 	for fieldName, field := range fields {
+		if field.Hidden {
+			// Hidden fields are never exposed to the client.
+			continue
+		}
 		if field.Required {
 			required = append(required, fieldName)
 		}
-		builder, err := ValidatorBuilder(field.Validator)
-		if err != nil {
-			return err
-		}
-		fieldMap, err := builder.BuildJSONSchema()
+		fieldMap, err := e.buildField(field, path+fieldName)
 		if err != nil {
 			return err
 		}
@@ -63,12 +75,53 @@ func addFields(m map[string]interface{}, fields schema.Fields) error {
 	return nil
 }
 
+// buildField returns the JSON Schema representation of a field's validator or
+// sub-schema.
+func (e *encodeState) buildField(field schema.Field, path string) (map[string]interface{}, error) {
+	s := field.Schema
+	if o, ok := field.Validator.(*schema.Object); ok && s == nil && o.Schema != nil {
+		s = o.Schema
+	}
+	if s != nil {
+		m := map[string]interface{}{}
+		err := e.addSchemaProperties(m, s, path+".")
+		return m, err
+	}
+	return e.buildValidator(field.Validator, path)
+}
+
+// buildValidator returns the JSON Schema representation of v, a validator of
+// the field at path or of its items. Validators which can not be represented,
+// including nested ones, are encoded with a permissive schema and a warning
+// is recorded.
+func (e *encodeState) buildValidator(v schema.FieldValidator, path string) (m map[string]interface{}, err error) {
+	builder, err := ValidatorBuilder(v)
+	if err == nil {
+		if sb, ok := builder.(stateBuilder); ok {
+			m, err = sb.buildJSONSchema(e, path)
+		} else {
+			m, err = builder.BuildJSONSchema()
+		}
+	}
+	if err == ErrNotImplemented {
+		e.warnings = append(e.warnings, fmt.Sprintf("%s: %v", path, err))
+		return map[string]interface{}{}, nil
+	}
+	return m, err
+}
+
 func addFieldProperties(m map[string]interface{}, field schema.Field) {
 	if field.Description != "" {
 		m["description"] = field.Description
 	}
-	if field.ReadOnly {
-		m["readOnly"] = field.ReadOnly
+	if field.ReadOnly || field.Compute != nil || field.OnCompute != nil {
+		m["readOnly"] = true
+	}
+	if field.Deprecated {
+		m["deprecated"] = true
+	}
+	if t, ok := m["type"].(string); ok && field.Nullable {
+		m["type"] = []string{t, "null"}
 	}
 	if field.Default != nil {
 		m["default"] = field.Default
@@ -94,22 +147,60 @@ func ValidatorBuilder(v schema.FieldValidator) (Builder, error) {
 		return (*passwordBuilder)(t), nil
 	case *schema.IP:
 		return (*ipBuilder)(t), nil
+	case *schema.CIDR:
+		return (*cidrBuilder)(t), nil
+	case *schema.MACAddress:
+		return (*macAddressBuilder)(t), nil
+	case *schema.Email:
+		return (*emailBuilder)(t), nil
+	case *schema.Hostname:
+		return (*hostnameBuilder)(t), nil
 	case *schema.URL:
 		return (*urlBuilder)(t), nil
+	case *schema.UUID:
+		return (*uuidBuilder)(t), nil
+	case *schema.Slug:
+		return (*slugBuilder)(t), nil
+	case *schema.CountryCode:
+		return (*countryCodeBuilder)(t), nil
+	case *schema.LanguageTag:
+		return (*languageTagBuilder)(t), nil
+	case *schema.Timezone:
+		return (*timezoneBuilder)(t), nil
+	case *schema.CreditCard:
+		return (*creditCardBuilder)(t), nil
+	case *schema.Color:
+		return (*colorBuilder)(t), nil
+	case *schema.MediaType:
+		return (*mediaTypeBuilder)(t), nil
+	case *schema.Binary:
+		return (*binaryBuilder)(t), nil
+	case *schema.GeoPoint:
+		return (*geoPointBuilder)(t), nil
 	case *schema.Time:
 		return (*timeBuilder)(t), nil
 	case *schema.Integer:
 		return (*integerBuilder)(t), nil
 	case *schema.Float:
 		return (*floatBuilder)(t), nil
+	case *schema.Decimal:
+		return (*decimalBuilder)(t), nil
 	case *schema.Array:
 		return (*arrayBuilder)(t), nil
 	case *schema.Object:
 		return (*objectBuilder)(t), nil
+	case *schema.Discriminated:
+		return (*discriminatedBuilder)(t), nil
+	case *schema.Struct:
+		return (*structBuilder)(t), nil
+	case *schema.JSON:
+		return (*jsonBuilder)(t), nil
 	case *schema.Dict:
 		return (*dictBuilder)(t), nil
 	case *schema.AnyOf:
 		return (*anyOfBuilder)(t), nil
+	case *schema.OneOf:
+		return (*oneOfBuilder)(t), nil
 	case *schema.AllOf:
 		return (*allOfBuilder)(t), nil
 	case *schema.Reference:
@@ -0,0 +1,22 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type slugBuilder schema.Slug
+
+func (v slugBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	m := map[string]interface{}{
+		"type": "string",
+	}
+	if v.Strict {
+		// Non strict slugs accept any string and normalize it.
+		m["pattern"] = "^[a-z0-9]+(?:-[a-z0-9]+)*$"
+		if v.MinLen > 0 {
+			m["minLength"] = v.MinLen
+		}
+		if v.MaxLen > 0 {
+			m["maxLength"] = v.MaxLen
+		}
+	}
+	return m, nil
+}
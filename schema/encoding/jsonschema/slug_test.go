@@ -0,0 +1,42 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestSlugValidatorEncode(t *testing.T) {
+	testCases := []encoderTestCase{
+		{
+			name: ``,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"slug": {
+						Validator: &schema.Slug{MaxLen: 10},
+					},
+				},
+			},
+			customValidate: fieldValidator("slug", `{"type": "string"}`),
+		},
+		{
+			name: `Strict=true,MinLen=2,MaxLen=10`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"slug": {
+						Validator: &schema.Slug{Strict: true, MinLen: 2, MaxLen: 10},
+					},
+				},
+			},
+			customValidate: fieldValidator("slug", `{
+				"type": "string",
+				"pattern": "^[a-z0-9]+(?:-[a-z0-9]+)*$",
+				"minLength": 2,
+				"maxLength": 10
+			}`),
+		},
+	}
+	for i := range testCases {
+		testCases[i].Run(t)
+	}
+}
@@ -12,8 +12,8 @@ func (v stringBuilder) BuildJSONSchema() (map[string]interface{}, error) {
 	if v.Regexp != "" {
 		m["pattern"] = v.Regexp
 	}
-	if len(v.Allowed) > 0 {
-		m["enum"] = v.Allowed
+	if allowed := schema.String(v).AllowedValues(); len(allowed) > 0 {
+		m["enum"] = allowed
 	}
 	if v.MinLen > 0 {
 		m["minLength"] = v.MinLen
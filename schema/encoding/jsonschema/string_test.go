@@ -63,6 +63,20 @@ func TestStringValidatorEncode(t *testing.T) {
 			},
 			customValidate: fieldValidator("s", `{"type": "string", "enum": ["one", "two"]}`),
 		},
+		{
+			name: `Allowed=["one"],AllowedDescriptions={"three":"3","two":"2"}`,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"s": {
+						Validator: &schema.String{
+							Allowed:             []string{"one"},
+							AllowedDescriptions: map[string]string{"three": "3", "two": "2"},
+						},
+					},
+				},
+			},
+			customValidate: fieldValidator("s", `{"type": "string", "enum": ["one", "three", "two"]}`),
+		},
 		{
 			name: `MinLen=3,MaxLen=23`,
 			schema: schema.Schema{
@@ -0,0 +1,12 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type structBuilder schema.Struct
+
+func (v structBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	// The properties of the Go struct are not described.
+	return map[string]interface{}{
+		"type": "object",
+	}, nil
+}
@@ -0,0 +1,22 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestStructValidatorEncode(t *testing.T) {
+	testCase := encoderTestCase{
+		name: ``,
+		schema: schema.Schema{
+			Fields: schema.Fields{
+				"s": {
+					Validator: &schema.Struct{Prototype: struct{ A string }{}},
+				},
+			},
+		},
+		customValidate: fieldValidator("s", `{"type": "object"}`),
+	}
+	testCase.Run(t)
+}
@@ -0,0 +1,13 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type timezoneBuilder schema.Timezone
+
+func (v timezoneBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	// Names are matched case insensitively and aliases are accepted, so the
+	// list of canonical names can't be used as an enum.
+	return map[string]interface{}{
+		"type": "string",
+	}, nil
+}
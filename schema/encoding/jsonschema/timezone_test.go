@@ -0,0 +1,26 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestTimezoneValidatorEncode(t *testing.T) {
+	testCases := []encoderTestCase{
+		{
+			name: ``,
+			schema: schema.Schema{
+				Fields: schema.Fields{
+					"tz": {
+						Validator: &schema.Timezone{Strict: true},
+					},
+				},
+			},
+			customValidate: fieldValidator("tz", `{"type": "string"}`),
+		},
+	}
+	for i := range testCases {
+		testCases[i].Run(t)
+	}
+}
@@ -0,0 +1,12 @@
+package jsonschema
+
+import "github.com/rs/rest-layer/schema"
+
+type uuidBuilder schema.UUID
+
+func (v uuidBuilder) BuildJSONSchema() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"type":   "string",
+		"format": "uuid",
+	}, nil
+}
@@ -0,0 +1,25 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestUUIDValidatorEncode(t *testing.T) {
+	testCase := encoderTestCase{
+		name: ``,
+		schema: schema.Schema{
+			Fields: schema.Fields{
+				"id": {
+					Validator: &schema.UUID{},
+				},
+			},
+		},
+		customValidate: fieldValidator("id", `{
+			"type": "string",
+			"format": "uuid"
+		}`),
+	}
+	testCase.Run(t)
+}
@@ -1,11 +1,338 @@
 package schema
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 )
 
+// ErrorCode identifies the kind of a ValidationError.
+type ErrorCode string
+
+// Validation error codes reported by Schema.Validate.
+const (
+	// CodeRequired is used when a required field is missing or null.
+	CodeRequired ErrorCode = "required"
+	// CodeReadOnly is used when a read-only field is changed by the client.
+	CodeReadOnly ErrorCode = "read-only"
+	// CodeImmutable is used when a create-only field is changed by the client
+	// on update.
+	CodeImmutable ErrorCode = "immutable"
+	// CodeForbidden is used when a field is changed by a client its WriteIf
+	// function rejects.
+	CodeForbidden ErrorCode = "forbidden"
+	// CodeInvalidField is used when a field is not defined by the schema.
+	CodeInvalidField ErrorCode = "invalid-field"
+	// CodeDependency is used when a field dependency does not match.
+	CodeDependency ErrorCode = "dependency"
+	// CodeLength is used when a document has too few or too many fields.
+	CodeLength ErrorCode = "length"
+	// CodeNull is used when null is set on a field which is not Nullable and
+	// whose validator rejects it.
+	CodeNull ErrorCode = "null"
+	// CodeValidator is used for errors returned by a FieldValidator.
+	CodeValidator ErrorCode = "validator"
+	// CodeHook is used when the OnInitErr or OnUpdateErr hook of a field
+	// fails.
+	CodeHook ErrorCode = "hook"
+	// CodeDelete is used when the OnDelete hook of a removed field, or a
+	// delete hook called by Schema.ValidateDelete, fails.
+	CodeDelete ErrorCode = "delete"
+	// CodeDeprecated is used for warnings about changes on deprecated fields.
+	CodeDeprecated ErrorCode = "deprecated"
+	// CodeMaxDepth is used when a sub-document is nested deeper than the
+	// MaxDepth of the schema.
+	CodeMaxDepth ErrorCode = "max-depth"
+	// CodeFatal is used for a FatalError returned by a FieldValidator.
+	CodeFatal ErrorCode = "fatal"
+	// CodeConflict is used when a payload sets both a field and one of its
+	// aliases.
+	CodeConflict ErrorCode = "conflict"
+)
+
+// Codes of the FieldError values returned by the built-in validators. Schema
+// reports them with the CodeValidator code; they identify the error in
+// Field.ErrorMessages.
+const (
+	// CodeMin is used for values lower than the minimum of their Boundaries
+	// ("min" param).
+	CodeMin ErrorCode = "min"
+	// CodeMax is used for values greater than the maximum of their Boundaries
+	// ("max" param).
+	CodeMax ErrorCode = "max"
+	// CodeMinLen is used for strings shorter than their MinLen ("min" and
+	// "len" params).
+	CodeMinLen ErrorCode = "min-len"
+	// CodeMaxLen is used for strings longer than their MaxLen ("max" and "len"
+	// params).
+	CodeMaxLen ErrorCode = "max-len"
+	// CodePattern is used for strings not matching their Regexp ("pattern"
+	// param).
+	CodePattern ErrorCode = "pattern"
+	// CodeAllowed is used for values which are not one of the allowed values.
+	CodeAllowed ErrorCode = "allowed"
+)
+
+// FieldError is an error returned by a FieldValidator identified by a Code, so
+// its message can be customized per field (see Field.ErrorMessages). Params
+// holds the values referenced by the custom messages as "{name}".
+type FieldError struct {
+	Code    ErrorCode
+	Message string
+	Params  map[string]interface{}
+}
+
+// Error implements the built-in error interface.
+func (err FieldError) Error() string {
+	return err.Message
+}
+
+// String implements the fmt.Stringer interface.
+func (err FieldError) String() string {
+	return err.Message
+}
+
+// FatalError can be returned by a FieldValidator to abort the validation of
+// the whole document (i.e.: on a signature mismatch). Schema.Validate then
+// reports this error alone, on the field in error, with the CodeFatal code;
+// the errors of the other fields are not collected.
+type FatalError struct {
+	Err error
+}
+
+// Error implements the built-in error interface.
+func (err FatalError) Error() string {
+	return err.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (err FatalError) Unwrap() error {
+	return err.Err
+}
+
+// isFatalError returns true if err is or wraps a FatalError.
+func isFatalError(err error) bool {
+	var fatal FatalError
+	return errors.As(err, &fatal)
+}
+
+// isFatal returns true if errs only holds the error reported for a FatalError,
+// possibly nested in sub-documents errors.
+func isFatal(errs map[string][]interface{}) bool {
+	if len(errs) != 1 {
+		return false
+	}
+	for _, values := range errs {
+		if len(values) != 1 {
+			return false
+		}
+		switch v := values[0].(type) {
+		case ValidationError:
+			return v.Code == CodeFatal
+		case map[string][]interface{}:
+			return isFatal(v)
+		}
+	}
+	return false
+}
+
+// ValidationError is the type of the errors stored in the errs map returned by
+// Schema.Validate. It prints and marshals to JSON as its Message, so the
+// representation exposed to API clients is unchanged.
+//
+// Before the introduction of this type, errors were stored as plain strings.
+// Callers type-asserting errors to string should switch to a fmt.Stringer (or
+// error) assertion, or type-assert to ValidationError to access the Code.
+type ValidationError struct {
+	// Code identifies the kind of error.
+	Code ErrorCode
+	// Message is the human readable error message.
+	Message string
+	// Field is the name of the field in error, relative to the schema that
+	// reported it. It is empty for document level errors.
+	Field string
+	// Details holds the nested errors reported by the validator, keyed by
+	// sub-field name, when available (i.e.: Object validators). It is not
+	// exposed in the JSON representation; use FlattenErrors to get the full
+	// path of nested errors. Array item and dict entry errors are not stored
+	// here but reported next to the ValidationError, keyed by item index or
+	// dict key.
+	Details map[string][]interface{}
+}
+
+// Error implements the built-in error interface.
+func (err ValidationError) Error() string {
+	return err.Message
+}
+
+// String implements the fmt.Stringer interface.
+func (err ValidationError) String() string {
+	return err.Message
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (err ValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(err.Message)
+}
+
+// FlattenErrors returns a copy of errs, as returned by Schema.Validate, where
+// nested errors are reported at the top level under their full dotted path
+// (i.e.: "address.zip" or "contacts.0.email") instead of being nested under
+// their parent field. The summary error of an array or dict field with invalid
+// items is kept under the path of the field.
+func FlattenErrors(errs map[string][]interface{}) map[string][]interface{} {
+	flat := map[string][]interface{}{}
+	flattenErrors(flat, "", errs)
+	return flat
+}
+
+func flattenErrors(flat map[string][]interface{}, prefix string, errs map[string][]interface{}) {
+	for field, values := range errs {
+		path := joinErrorPath(prefix, field)
+		for _, v := range values {
+			switch e := v.(type) {
+			case map[string][]interface{}:
+				flattenErrors(flat, path, e)
+			case ErrorMap:
+				flattenErrors(flat, path, e)
+			case ValidationError:
+				if len(e.Details) > 0 {
+					flattenErrors(flat, path, e.Details)
+					continue
+				}
+				flat[path] = append(flat[path], v)
+			default:
+				flat[path] = append(flat[path], v)
+			}
+		}
+	}
+}
+
+func joinErrorPath(prefix, field string) string {
+	if prefix == "" {
+		return field
+	}
+	if field == "" {
+		return prefix
+	}
+	return prefix + "." + field
+}
+
+// errorDetails returns the nested errors held by err, if any.
+func errorDetails(err error) map[string][]interface{} {
+	switch e := err.(type) {
+	case ErrorMap:
+		return e
+	case itemErrors:
+		return e.errorMap()
+	}
+	return nil
+}
+
+// itemErrors is implemented by the errors of validators reporting the errors
+// of their items keyed by index or key (i.e.: Array or Dict).
+type itemErrors interface {
+	error
+	errorMap() map[string][]interface{}
+}
+
+// itemErrorValues returns the values stored under key in the errorMap of an
+// itemErrors for err.
+func itemErrorValues(key string, err error) []interface{} {
+	d := errorDetails(err)
+	if d == nil {
+		return []interface{}{ValidationError{CodeValidator, err.Error(), key, nil}}
+	}
+	if _, ok := err.(itemErrors); ok {
+		// Nested array or dict, keep its summary as well.
+		return []interface{}{ValidationError{CodeValidator, err.Error(), key, nil}, d}
+	}
+	return []interface{}{d}
+}
+
+// arrayItemError is the error of an invalid array item. Its index is zero
+// based while the message reports a one based position.
+type arrayItemError struct {
+	index int
+	err   error
+}
+
+// Error implements the built-in error interface.
+func (err arrayItemError) Error() string {
+	return fmt.Sprintf("invalid value at #%d: %s", err.index+1, err.err)
+}
+
+// arrayItemErrors is returned by Array when some of its items are invalid.
+type arrayItemErrors struct {
+	errs []arrayItemError
+	// items holds the items of the array, the valid ones being normalized.
+	items []interface{}
+}
+
+// Error implements the built-in error interface.
+func (errs arrayItemErrors) Error() string {
+	msgs := make([]string, 0, len(errs.errs))
+	for _, err := range errs.errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// errorMap returns the errors keyed by item index.
+func (errs arrayItemErrors) errorMap() map[string][]interface{} {
+	m := make(map[string][]interface{}, len(errs.errs))
+	for _, err := range errs.errs {
+		key := strconv.Itoa(err.index)
+		m[key] = append(m[key], itemErrorValues(key, err.err)...)
+	}
+	return m
+}
+
+// dictKeyError is the error of an invalid dict key or value.
+type dictKeyError struct {
+	key string
+	// invalidKey is true if the key itself is invalid.
+	invalidKey bool
+	err        error
+}
+
+// Error implements the built-in error interface.
+func (err dictKeyError) Error() string {
+	if err.invalidKey {
+		return fmt.Sprintf("invalid key `%s': %s", err.key, err.err)
+	}
+	return fmt.Sprintf("invalid value for key `%s': %s", err.key, err.err)
+}
+
+// dictKeyErrors is returned by Dict when some of its keys or values are
+// invalid. Errors are sorted by key.
+type dictKeyErrors []dictKeyError
+
+// Error implements the built-in error interface.
+func (errs dictKeyErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// errorMap returns the errors keyed by dict key.
+func (errs dictKeyErrors) errorMap() map[string][]interface{} {
+	m := make(map[string][]interface{}, len(errs))
+	for _, err := range errs {
+		if err.invalidKey {
+			m[err.key] = append(m[err.key], ValidationError{CodeValidator, "invalid key: " + err.err.Error(), err.key, nil})
+			continue
+		}
+		m[err.key] = append(m[err.key], itemErrorValues(err.key, err.err)...)
+	}
+	return m
+}
+
 // ErrorMap contains a map of errors by field name.
 type ErrorMap map[string][]interface{}
 
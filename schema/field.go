@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // Fields defines a map of name -> field pairs
@@ -17,17 +18,72 @@ type Field struct {
 	Description string
 	// Required throws an error when the field is not provided at creation.
 	Required bool
+	// RequiredWhen makes the field required when the function returns true.
+	// The function is called with the final document (i.e.: with changes
+	// applied on the base) of the schema holding the field. Use Dependency to
+	// reject the field when it should be absent.
+	RequiredWhen func(doc map[string]interface{}) bool
 	// ReadOnly throws an error when a field is changed by the client.
 	// Default and OnInit/OnUpdate hooks can be used to set/change read-only
 	// fields.
 	ReadOnly bool
+	// CreateOnly lets the client set the field when the item is created but
+	// throws an error when it is changed afterward. As with ReadOnly, hooks
+	// can still change the field and the stored value may be resubmitted.
+	CreateOnly bool
+	// Nullable accepts and stores null as the value of the field, without
+	// calling its validator. A null value is then distinct from an absent
+	// field. The null value of a field which is not nullable is passed to its
+	// validator, and reported as "cannot be null" if rejected.
+	Nullable bool
 	// Hidden allows writes but hides the field's content from the client. When
 	// this field is enabled, PUTing the document without the field would not
 	// remove the field but use the previous document's value if any.
 	Hidden bool
+	// VisibleIf can be set on a hidden field to reveal it when the function
+	// returns true for the context of the read request (i.e.: for
+	// administrators, based on the auth info stored in the context). Hidden
+	// fields with VisibleIf can be selected in projections.
+	VisibleIf func(ctx context.Context) bool
+	// ReadIf hides the field from the output of read requests for which the
+	// function returns false (i.e.: to reveal an email address to
+	// authenticated users only, based on the auth info stored in the
+	// context). Unlike Hidden fields, such fields can be selected in
+	// projections, but they are omitted from the response when hidden.
+	ReadIf func(ctx context.Context) bool
+	// WriteIf rejects the changes of the field with a "forbidden field" error
+	// when the function returns false for the context of the write request
+	// (i.e.: to let administrators only set a role). A field omitted from a
+	// replacement document by such a client keeps its stored value.
+	WriteIf func(ctx context.Context) bool
+	// NoDefaultProjection omits the field from read responses unless the
+	// projection names it explicitly, i.e. for large text or binary fields.
+	// Empty and star (*) projections don't select it.
+	NoDefaultProjection bool
+	// Deprecated marks the field as deprecated. Changing a deprecated field
+	// is still accepted, but reported as a warning by
+	// Schema.ValidateWithWarnings.
+	Deprecated bool
+	// DeprecationMessage overrides the message of the deprecation warnings
+	// (default "deprecated"), i.e.: to name the replacing field.
+	DeprecationMessage string
 	// Default defines the value be stored on the field when when item is
-	// created and this field is not provided by the client.
+	// created and this field is not provided by the client. Maps and slices
+	// are copied so documents never share them. Compile checks the value with
+	// the validator of the field, unless it requires a lookup (Reference or
+	// Email with CheckMX, or an Array or a Dict of those).
 	Default interface{}
+	// DefaultFunc can be set to a function generating the default value of the
+	// field, for instance from the request context or the current time. It is
+	// called under the same conditions as Default is assigned, and can't be
+	// set together with Default. The default value is set before the OnInit
+	// hook is called, which receives it as the current value.
+	DefaultFunc func(ctx context.Context) interface{}
+	// Transform lists the transformers applied in order to the value of the
+	// field provided by the client, on creation and on update, before it is
+	// compared with the stored value and validated (i.e.: TrimSpace and
+	// ToLower for an email address). Null values are not transformed.
+	Transform []Transformer
 	// OnInit can be set to a function to generate the value of this field
 	// when item is created. The function takes the current value if any
 	// and returns the value to be stored.
@@ -36,6 +92,52 @@ type Field struct {
 	// when item is updated. The function takes the current value if any
 	// and returns the value to be stored.
 	OnUpdate func(ctx context.Context, value interface{}) interface{}
+	// OnInitErr and OnUpdateErr are variants of OnInit and OnUpdate which can
+	// fail. When set, they are called instead of OnInit and OnUpdate
+	// respectively. A returned error is reported by Validate for the field.
+	OnInitErr   func(ctx context.Context, value interface{}) (interface{}, error)
+	OnUpdateErr func(ctx context.Context, value interface{}) (interface{}, error)
+	// OnInitDoc and OnUpdateDoc are variants of OnInitErr and OnUpdateErr
+	// which also receive the document, i.e.: to set a display name from the
+	// first and last names. They are called by Prepare once the other hooks
+	// of all the fields have run, in no particular order, with the same
+	// snapshot of the root document: the base with the changes applied,
+	// before validation. Fields of sub-schemas receive the root document too.
+	// The values returned by the other OnInitDoc and OnUpdateDoc hooks are not
+	// visible in the snapshot, which must not be modified.
+	OnInitDoc   func(ctx context.Context, value interface{}, doc map[string]interface{}) (interface{}, error)
+	OnUpdateDoc func(ctx context.Context, value interface{}, doc map[string]interface{}) (interface{}, error)
+	// OnDelete can be set to a function called when the field is removed
+	// from an existing item, i.e.: when it is omitted from a replacement
+	// document or set to null in a merge patch. The function takes the
+	// previous value of the field. A returned error is reported by Validate
+	// for the field. It is also called by Schema.ValidateDelete when the
+	// document holding the field is deleted.
+	OnDelete func(ctx context.Context, oldValue interface{}) error
+	// OnRead can be set to a function transforming the stored value of the
+	// field each time the item is serialized for output (i.e.: to normalize
+	// values stored before a normalization was introduced). The stored value
+	// is not changed. It is not called for hidden fields.
+	OnRead func(ctx context.Context, value interface{}) interface{}
+	// Compute makes the field virtual: its value is never stored but computed
+	// from the stored document (i.e.: a full name from the first and last
+	// names) each time the document is serialized. Like read-only fields,
+	// computed fields can't be changed by the client.
+	Compute func(ctx context.Context, doc map[string]interface{}) (interface{}, error)
+	// OnCompute derives the stored value of the field from the other fields of
+	// the validated document (i.e.: search terms), on creation and on update,
+	// so it never goes stale. It is called once the document of the schema
+	// holding the field is successfully validated, and its result replaces the
+	// value of the field. An error is reported as a validation error on the
+	// field. Like read-only fields, the field can't be changed by the client.
+	OnCompute func(ctx context.Context, doc map[string]interface{}) (interface{}, error)
+	// ErrorMessages overrides the messages of the errors reported on the field
+	// by their code: either the code of a ValidationError (i.e.: "required"
+	// or "read-only") or the code of a FieldError returned by the validator
+	// (i.e.: "min" or "pattern"). The "{name}" placeholders are replaced by
+	// the params of the FieldError (i.e.: "must be at least {min}"). The
+	// "validator" code applies to the other validator errors.
+	ErrorMessages map[ErrorCode]string
 	// Params defines a param handler for the field. The handler may change the field's
 	// value depending on the passed parameters.
 	Params Params
@@ -51,6 +153,17 @@ type Field struct {
 	// Dependency rejects the field if the schema predicate doesn't match the document.
 	// Use query.MustParsePredicate(`{field: "value"}`) to populate this field.
 	Dependency Predicate
+	// Excludes lists the names of sibling fields which must be absent when
+	// this field is set. A dependency error is reported on the excluded
+	// fields.
+	Excludes []string
+	// Aliases lists former names of the field, accepted in payloads in place
+	// of the field name (i.e.: during the transition after a renaming). Their
+	// value is stored under the field name, and setting both an alias and the
+	// field name is rejected. Aliases are resolved by Schema.GetField, so
+	// filters and sorts validate against the field, but the documents are
+	// always serialized with the field name.
+	Aliases []string
 	// Filterable defines that the field can be used with the `filter` parameter.
 	// When this property is set to `true`, you may want to ensure the backend
 	// database has this field indexed.
@@ -63,29 +176,307 @@ type Field struct {
 	Schema *Schema
 }
 
+// defaultValue returns the default value of the field and whether it has one.
+func (f Field) defaultValue(ctx context.Context) (interface{}, bool) {
+	if f.DefaultFunc != nil {
+		return f.DefaultFunc(ctx), true
+	}
+	return copyValue(f.Default), f.Default != nil
+}
+
+// copyValue returns a deep copy of the maps and slices of v.
+func copyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			c[k] = copyValue(v)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(t))
+		for i, v := range t {
+			c[i] = copyValue(v)
+		}
+		return c
+	}
+	return v
+}
+
+// Clone returns a copy of f with its own sub-schema (cloned recursively),
+// Params and ErrorMessages maps and Excludes, Aliases and Transform slices, so
+// the clone can be modified without affecting f.
+//
+// Validators, hooks, Dependency and Default values are shared with f; they
+// are expected to be immutable once the schema is compiled. Sub-schemas held
+// by validators (i.e.: Object or Array of Object) are thus shared too: set a
+// new validator on the clone to change them.
+func (f Field) Clone() Field {
+	if f.Schema != nil {
+		s := f.Schema.Clone()
+		f.Schema = &s
+	}
+	if f.Params != nil {
+		params := make(Params, len(f.Params))
+		for name, p := range f.Params {
+			params[name] = p
+		}
+		f.Params = params
+	}
+	if f.Excludes != nil {
+		f.Excludes = append([]string(nil), f.Excludes...)
+	}
+	if f.Transform != nil {
+		f.Transform = append([]Transformer(nil), f.Transform...)
+	}
+	if f.ErrorMessages != nil {
+		msgs := make(map[ErrorCode]string, len(f.ErrorMessages))
+		for code, msg := range f.ErrorMessages {
+			msgs[code] = msg
+		}
+		f.ErrorMessages = msgs
+	}
+	if f.Aliases != nil {
+		f.Aliases = append([]string(nil), f.Aliases...)
+	}
+	return f
+}
+
+// transform returns value transformed by the transformers of f.
+func (f Field) transform(value interface{}) interface{} {
+	for _, t := range f.Transform {
+		value = t.Transform(value)
+	}
+	return value
+}
+
+// errorMessage returns the message reported on f for an error with code, msg
+// and params, as customized by f.ErrorMessages.
+func (f Field) errorMessage(code ErrorCode, msg string, params map[string]interface{}) string {
+	custom, found := f.ErrorMessages[code]
+	if !found {
+		return msg
+	}
+	for name, value := range params {
+		custom = strings.Replace(custom, "{"+name+"}", fmt.Sprint(value), -1)
+	}
+	return custom
+}
+
+// validatorMessage returns the message reported on f for err, returned by its
+// validator.
+func (f Field) validatorMessage(err error) string {
+	var fe FieldError
+	if errors.As(err, &fe) {
+		if _, found := f.ErrorMessages[fe.Code]; found {
+			return f.errorMessage(fe.Code, fe.Message, fe.Params)
+		}
+	}
+	return f.errorMessage(CodeValidator, err.Error(), nil)
+}
+
+// IsHidden returns true if the field must be hidden from the output of a read
+// request with ctx: the field is Hidden and VisibleIf, if set, returns false,
+// or ReadIf is set and returns false.
+func (f Field) IsHidden(ctx context.Context) bool {
+	return (f.Hidden && (f.VisibleIf == nil || !f.VisibleIf(ctx))) || (f.ReadIf != nil && !f.ReadIf(ctx))
+}
+
+// isWritable returns false if the WriteIf function of the field is set and
+// returns false for ctx.
+func (f Field) isWritable(ctx context.Context) bool {
+	return f.WriteIf == nil || f.WriteIf(ctx)
+}
+
+// errNull is reported for null values rejected by the validator of a field
+// which is not Nullable.
+var errNull = errors.New("cannot be null")
+
+// validateItem validates value, an item of an array or dict described by f,
+// handling null values as described by Field.Nullable.
+func (f Field) validateItem(validate func(value interface{}) (interface{}, error), value interface{}) (interface{}, error) {
+	if value == nil && f.Nullable {
+		return nil, nil
+	}
+	v, err := validate(value)
+	if err != nil && value == nil {
+		return nil, errNull
+	}
+	return v, err
+}
+
+// isComputedValue returns true if value is equal to the value of the computed
+// field f for the original document.
+func (f Field) isComputedValue(ctx context.Context, value interface{}, original *map[string]interface{}) bool {
+	if original == nil {
+		return false
+	}
+	computed, err := f.Compute(ctx, *original)
+	if err != nil {
+		return false
+	}
+	if f.Validator != nil && !needsLookup(f.Validator, DefaultMaxDepth) {
+		if v, err := ValidateField(ctx, f.Validator, value); err == nil {
+			value = v
+		}
+	}
+	return reflect.DeepEqual(value, computed)
+}
+
+// hook returns the OnInit (when init is true) or OnUpdate hook of the field,
+// preferring their error returning variant, or nil if none is set.
+func (f Field) hook(init bool) func(ctx context.Context, value interface{}) (interface{}, error) {
+	hookErr, hook := f.OnUpdateErr, f.OnUpdate
+	if init {
+		hookErr, hook = f.OnInitErr, f.OnInit
+	}
+	if hookErr != nil {
+		return hookErr
+	}
+	if hook != nil {
+		return func(ctx context.Context, value interface{}) (interface{}, error) {
+			return hook(ctx, value), nil
+		}
+	}
+	return nil
+}
+
+// docHook returns the OnInitDoc (when init is true) or OnUpdateDoc hook of the
+// field.
+func (f Field) docHook(init bool) func(ctx context.Context, value interface{}, doc map[string]interface{}) (interface{}, error) {
+	if init {
+		return f.OnInitDoc
+	}
+	return f.OnUpdateDoc
+}
+
+// getSubField returns the field at path name within the sub-schema or the
+// FieldGetter validator of f, or nil if not found.
+func (f Field) getSubField(name string) *Field {
+	if f.Schema != nil {
+		return f.Schema.GetField(name)
+	}
+	if fg, ok := f.Validator.(FieldGetter); ok {
+		return fg.GetField(name)
+	}
+	return nil
+}
+
 // Compile implements the ReferenceCompiler interface and recursively compile sub schemas
 // and validators when they implement Compiler interface.
 func (f Field) Compile(rc ReferenceChecker) error {
+	if f.Schema != nil {
+		if err := compileDependencies(*f.Schema, f.Schema, ""); err != nil {
+			return err
+		}
+	}
+	return f.compile(rc)
+}
+
+// compile compiles f as a field of a schema, leaving the compilation of the
+// dependencies of its sub-schema to the root schema.
+func (f Field) compile(rc ReferenceChecker) error {
 	// TODO check field name format (alpha num + _ and -).
+	if f.Compute != nil && (f.Required || f.Filterable || f.Sortable) {
+		return errors.New("computed field can't be required, filterable or sortable")
+	}
+	if f.OnCompute != nil && (f.Compute != nil || f.Required) {
+		return errors.New("on-compute field can't be computed or required")
+	}
+	if f.CreateOnly && (f.ReadOnly || f.Compute != nil) {
+		return errors.New("create-only field can't be read-only or computed")
+	}
+	if f.Default != nil && f.DefaultFunc != nil {
+		return errors.New("default and default func can't be both set")
+	}
 	if f.Schema != nil {
 		// Recursively compile sub schema if any.
-		if err := f.Schema.Compile(rc); err != nil {
-			return fmt.Errorf(".%v", err)
+		if err := f.Schema.compile(rc, false); err != nil {
+			return err
 		}
 	} else if f.Validator != nil {
 		// Compile validator if it implements the ReferenceCompiler or Compiler interface.
 		if c, ok := f.Validator.(Compiler); ok {
 			if err := c.Compile(rc); err != nil {
-				return fmt.Errorf(": %v", err)
+				return err
 			}
 		}
 		if reflect.ValueOf(f.Validator).Kind() != reflect.Ptr {
-			return errors.New(": not a schema.Validator pointer")
+			return errors.New("not a schema.Validator pointer")
+		}
+		// Check the default value, unless it requires a lookup.
+		if f.Default != nil && !needsLookup(f.Validator, DefaultMaxDepth) {
+			if _, err := ValidateField(context.Background(), f.Validator, f.Default); err != nil {
+				return fmt.Errorf("invalid default: %v", err)
+			}
 		}
 	}
 	return nil
 }
 
+// needsLookup returns true if validating values with v requires a storage or
+// DNS lookup, i.e.: v is a Reference, an Email checking MX records, or a
+// validator wrapping those (Array, Dict, AnyOf, Object...) up to depth
+// nesting levels.
+func needsLookup(v FieldValidator, depth int) bool {
+	if depth <= 0 {
+		return false
+	}
+	switch t := v.(type) {
+	case *Reference:
+		return true
+	case *Email:
+		return t.CheckMX
+	case *Array:
+		return t.Values.needsLookup(depth - 1)
+	case *Dict:
+		return t.Values.needsLookup(depth - 1)
+	case *Object:
+		return t.Schema != nil && t.Schema.needsLookup(depth-1)
+	case *Discriminated:
+		for _, s := range t.Mapping {
+			if s != nil && s.needsLookup(depth-1) {
+				return true
+			}
+		}
+	case *AnyOf:
+		return anyNeedsLookup(*t, depth-1)
+	case *AllOf:
+		return anyNeedsLookup(*t, depth-1)
+	case *OneOf:
+		return anyNeedsLookup(*t, depth-1)
+	}
+	return false
+}
+
+// anyNeedsLookup returns true if one of validators needs a lookup.
+func anyNeedsLookup(validators []FieldValidator, depth int) bool {
+	for _, v := range validators {
+		if needsLookup(v, depth) {
+			return true
+		}
+	}
+	return false
+}
+
+// needsLookup returns true if validating the values of f requires a lookup.
+func (f Field) needsLookup(depth int) bool {
+	if f.Schema != nil {
+		return f.Schema.needsLookup(depth)
+	}
+	return needsLookup(f.Validator, depth)
+}
+
+// needsLookup returns true if validating a field of s requires a lookup.
+func (s Schema) needsLookup(depth int) bool {
+	for _, def := range s.Fields {
+		if def.needsLookup(depth) {
+			return true
+		}
+	}
+	return false
+}
+
 // FieldHandler is the piece of logic modifying the field value based on passed
 // parameters
 type FieldHandler func(ctx context.Context, value interface{}, params map[string]interface{}) (interface{}, error)
@@ -107,6 +498,66 @@ func (f FieldValidatorFunc) Validate(value interface{}) (interface{}, error) {
 	return f(value)
 }
 
+// FieldValidatorCtx is a context aware variant of FieldValidator for
+// validators performing I/O (i.e.: checking that a referenced document
+// exists). When a FieldValidator implements both interfaces, ValidateCtx is
+// preferred by Schema.ValidateCtx. Implementations should abort and return
+// ctx.Err() when the context is done.
+type FieldValidatorCtx interface {
+	ValidateCtx(ctx context.Context, value interface{}) (interface{}, error)
+}
+
+// FieldValidatorCtxFunc is an adapter to allow the use of ordinary functions
+// as context aware field validators. Its Validate method calls f with
+// context.Background().
+type FieldValidatorCtxFunc func(ctx context.Context, value interface{}) (interface{}, error)
+
+// Validate calls f(context.Background(), value).
+func (f FieldValidatorCtxFunc) Validate(value interface{}) (interface{}, error) {
+	return f(context.Background(), value)
+}
+
+// ValidateCtx calls f(ctx, value).
+func (f FieldValidatorCtxFunc) ValidateCtx(ctx context.Context, value interface{}) (interface{}, error) {
+	return f(ctx, value)
+}
+
+// FieldBatchValidator is implemented by validators able to validate several
+// values at once more efficiently (i.e.: checking that referenced documents
+// exist with a single storage request). Array uses it to validate its items.
+type FieldBatchValidator interface {
+	// ValidateBatch returns the normalized values and the error of each value
+	// (nil if valid), in the order of values.
+	ValidateBatch(ctx context.Context, values []interface{}) ([]interface{}, []error)
+}
+
+// FieldNumberValidator is implemented by validators expecting the numbers of
+// REST payloads as json.Number values, to inspect their literal form (i.e.:
+// Integer in Strict mode). Schema.Prepare converts the numbers of the other
+// fields to float64, as json.Unmarshal does. Validators wrapping other
+// validators (i.e.: AnyOf or Array) accept them if one of those does.
+type FieldNumberValidator interface {
+	// AcceptsJSONNumber returns true if the validator expects json.Number
+	// values.
+	AcceptsJSONNumber() bool
+}
+
+// acceptsJSONNumber returns true if v implements FieldNumberValidator and
+// accepts json.Number values.
+func acceptsJSONNumber(v FieldValidator) bool {
+	n, ok := v.(FieldNumberValidator)
+	return ok && n.AcceptsJSONNumber()
+}
+
+// ValidateField validates value using validator's FieldValidatorCtx
+// implementation if any, or its Validate method otherwise.
+func ValidateField(ctx context.Context, validator FieldValidator, value interface{}) (interface{}, error) {
+	if v, ok := validator.(FieldValidatorCtx); ok {
+		return v.ValidateCtx(ctx, value)
+	}
+	return validator.Validate(value)
+}
+
 // FieldSerializer is used to convert the value between it's representation form
 // and it internal storable form. A FieldValidator which implement this
 // interface will have its Serialize method called before marshaling.
@@ -117,6 +568,74 @@ type FieldSerializer interface {
 	Serialize(value interface{}) (interface{}, error)
 }
 
+// FieldSerializerCtx is a context aware variant of FieldSerializer for
+// serializers performing I/O (i.e.: resolving a stored value to a display
+// value). When a FieldValidator implements both interfaces, SerializeCtx is
+// preferred. Implementations should abort and return ctx.Err() when the
+// context is done.
+type FieldSerializerCtx interface {
+	SerializeCtx(ctx context.Context, value interface{}) (interface{}, error)
+}
+
+// SerializeField serializes value using validator's FieldSerializerCtx or
+// FieldSerializer implementation. If validator implements neither, value is
+// returned unchanged.
+func SerializeField(ctx context.Context, validator FieldValidator, value interface{}) (interface{}, error) {
+	switch s := validator.(type) {
+	case FieldSerializerCtx:
+		return s.SerializeCtx(ctx, value)
+	case FieldSerializer:
+		return s.Serialize(value)
+	}
+	return value, nil
+}
+
+// Describer is implemented by validators able to describe their constraints
+// (i.e.: to build forms in a UI). The returned map must be JSON serializable.
+type Describer interface {
+	Describe() map[string]interface{}
+}
+
+// Describe returns a JSON serializable description of the field, made of the
+// constraints of its validator if it implements Describer, or of its
+// sub-schema, along with its description, required, readOnly and default
+// properties when set.
+func (f Field) Describe() map[string]interface{} {
+	m := map[string]interface{}{}
+	if d, ok := f.Validator.(Describer); ok {
+		m = d.Describe()
+	}
+	if f.Schema != nil {
+		m["type"] = "object"
+		m["schema"] = f.Schema.Describe()
+	}
+	if f.Description != "" {
+		m["description"] = f.Description
+	}
+	if f.Required {
+		m["required"] = true
+	}
+	if f.ReadOnly || f.Compute != nil || f.OnCompute != nil {
+		m["readOnly"] = true
+	}
+	if f.CreateOnly {
+		m["createOnly"] = true
+	}
+	if f.Nullable {
+		m["nullable"] = true
+	}
+	if f.Deprecated {
+		m["deprecated"] = true
+		if f.DeprecationMessage != "" {
+			m["deprecationMessage"] = f.DeprecationMessage
+		}
+	}
+	if f.Default != nil {
+		m["default"] = f.Default
+	}
+	return m
+}
+
 // FieldGetter defines an interface for fetching sub-fields from a Schema or
 // FieldValidator implementation that allows (JSON) object values.
 type FieldGetter interface {
@@ -1,20 +1,87 @@
 package schema
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 )
 
 // Boundaries defines min/max for an integer.
 type Boundaries struct {
 	Min float64
 	Max float64
+	// ExclusiveMin and ExclusiveMax exclude Min and Max respectively from
+	// the accepted values.
+	ExclusiveMin bool
+	ExclusiveMax bool
+}
+
+// check returns an error if a value is out of the boundaries. The cmp
+// function compares the value with a bound and returns -1, 0 or +1 like
+// big.Rat.Cmp, and format formats a bound for error messages. NaN bounds are
+// ignored.
+func (b Boundaries) check(cmp func(bound float64) int, format func(bound float64) string) error {
+	if !math.IsNaN(b.Min) {
+		if c := cmp(b.Min); c < 0 || (c == 0 && b.ExclusiveMin) {
+			if b.ExclusiveMin {
+				return boundError(CodeMin, "must be greater than %s", format(b.Min))
+			}
+			return boundError(CodeMin, "must be greater than or equal to %s", format(b.Min))
+		}
+	}
+	if !math.IsNaN(b.Max) {
+		if c := cmp(b.Max); c > 0 || (c == 0 && b.ExclusiveMax) {
+			if b.ExclusiveMax {
+				return boundError(CodeMax, "must be lower than %s", format(b.Max))
+			}
+			return boundError(CodeMax, "must be lower than or equal to %s", format(b.Max))
+		}
+	}
+	return nil
+}
+
+// boundError returns the FieldError of a value out of its boundaries, bound
+// being stored as the "min" or "max" param depending on code.
+func boundError(code ErrorCode, format, bound string) error {
+	return FieldError{code, fmt.Sprintf(format, bound), map[string]interface{}{string(code): bound}}
+}
+
+// describe adds the finite boundaries to m.
+func (b Boundaries) describe(m map[string]interface{}) {
+	if !math.IsNaN(b.Min) && !math.IsInf(b.Min, 0) {
+		m["min"] = b.Min
+		if b.ExclusiveMin {
+			m["exclusiveMin"] = true
+		}
+	}
+	if !math.IsNaN(b.Max) && !math.IsInf(b.Max, 0) {
+		m["max"] = b.Max
+		if b.ExclusiveMax {
+			m["exclusiveMax"] = true
+		}
+	}
+}
+
+// cmpFloat compares a and b, returning -1, 0 or +1.
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
 }
 
 // Float validates float based values.
 type Float struct {
 	Allowed    []float64
 	Boundaries *Boundaries
+	// Coerce accepts strings holding a decimal number (i.e.: "4.2") and
+	// converts them to float64.
+	Coerce bool
 }
 
 // ValidateQuery implements schema.FieldQueryValidator interface
@@ -30,18 +97,33 @@ func (v Float) get(value interface{}) (float64, error) {
 	return f, nil
 }
 
+// Describe implements the Describer interface.
+func (v Float) Describe() map[string]interface{} {
+	m := map[string]interface{}{"type": "float"}
+	if len(v.Allowed) > 0 {
+		m["allowed"] = v.Allowed
+	}
+	if v.Boundaries != nil {
+		v.Boundaries.describe(m)
+	}
+	return m
+}
+
 // Validate validates and normalize float based value.
 func (v Float) Validate(value interface{}) (interface{}, error) {
+	value, err := v.parse(value)
+	if err != nil {
+		return nil, err
+	}
 	f, err := v.get(value)
 	if err != nil {
 		return nil, err
 	}
 	if v.Boundaries != nil {
-		if f < v.Boundaries.Min {
-			return nil, fmt.Errorf("is lower than %.2f", v.Boundaries.Min)
-		}
-		if f > v.Boundaries.Max {
-			return nil, fmt.Errorf("is greater than %.2f", v.Boundaries.Max)
+		cmp := func(bound float64) int { return cmpFloat(f, bound) }
+		format := func(bound float64) string { return fmt.Sprintf("%.2f", bound) }
+		if err := v.Boundaries.check(cmp, format); err != nil {
+			return nil, err
 		}
 	}
 	if len(v.Allowed) > 0 {
@@ -54,13 +136,27 @@ func (v Float) Validate(value interface{}) (interface{}, error) {
 		}
 		if !found {
 			// TODO: build the list of allowed values.
-			return nil, fmt.Errorf("not one of the allowed values")
+			return nil, FieldError{CodeAllowed, "not one of the allowed values", nil}
 		}
 	}
 	return f, nil
 }
 
 func (v Float) parse(value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok && v.Coerce {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, errors.New("not a float")
+		}
+		return f, nil
+	}
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return nil, errors.New("not a float")
+		}
+		return f, nil
+	}
 	f, ok := value.(float64)
 	if !ok {
 		return nil, errors.New("not a float")
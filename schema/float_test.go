@@ -1,6 +1,7 @@
 package schema_test
 
 import (
+	"encoding/json"
 	"errors"
 	"math"
 	"reflect"
@@ -51,13 +52,13 @@ func TestFloatValidator(t *testing.T) {
 	assert.EqualError(t, err, "not a float")
 	assert.Nil(t, s)
 	s, err = schema.Float{Boundaries: &schema.Boundaries{Min: 0, Max: 2}}.Validate(3.1)
-	assert.EqualError(t, err, "is greater than 2.00")
+	assert.EqualError(t, err, "must be lower than or equal to 2.00")
 	assert.Nil(t, s)
 	s, err = schema.Float{Boundaries: &schema.Boundaries{Min: 0, Max: 2}}.Validate(1.1)
 	assert.NoError(t, err)
 	assert.Equal(t, 1.1, s)
 	s, err = schema.Float{Boundaries: &schema.Boundaries{Min: 2, Max: 10}}.Validate(1.1)
-	assert.EqualError(t, err, "is lower than 2.00")
+	assert.EqualError(t, err, "must be greater than or equal to 2.00")
 	assert.Nil(t, s)
 	s, err = schema.Float{Boundaries: &schema.Boundaries{Min: 2, Max: 10}}.Validate(3.1)
 	assert.NoError(t, err)
@@ -75,10 +76,10 @@ func TestFloatValidator(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 3.1, s)
 	s, err = schema.Float{Boundaries: &schema.Boundaries{}}.Validate(1.1)
-	assert.EqualError(t, err, "is greater than 0.00")
+	assert.EqualError(t, err, "must be lower than or equal to 0.00")
 	assert.Nil(t, s)
 	s, err = schema.Float{Boundaries: &schema.Boundaries{}}.Validate(-1.1)
-	assert.EqualError(t, err, "is lower than 0.00")
+	assert.EqualError(t, err, "must be greater than or equal to 0.00")
 	assert.Nil(t, s)
 	s, err = schema.Float{Allowed: []float64{.1, .2, .3}}.Validate(.4)
 	assert.EqualError(t, err, "not one of the allowed values")
@@ -88,6 +89,52 @@ func TestFloatValidator(t *testing.T) {
 	assert.Equal(t, .2, s)
 }
 
+func TestFloatValidatorExclusiveBoundaries(t *testing.T) {
+	v := schema.Float{Boundaries: &schema.Boundaries{Min: 0, Max: 1, ExclusiveMin: true, ExclusiveMax: true}}
+	s, err := v.Validate(0.0)
+	assert.EqualError(t, err, "must be greater than 0.00")
+	assert.Nil(t, s)
+	s, err = v.Validate(1.0)
+	assert.EqualError(t, err, "must be lower than 1.00")
+	assert.Nil(t, s)
+	s, err = v.Validate(0.000001)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.000001, s)
+	s, err = schema.Float{Boundaries: &schema.Boundaries{Min: math.NaN(), Max: 1, ExclusiveMin: true}}.Validate(0.0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, s)
+}
+
+func TestFloatValidatorCoerce(t *testing.T) {
+	v := schema.Float{Coerce: true}
+	s, err := v.Validate("4.2")
+	assert.NoError(t, err)
+	assert.Equal(t, 4.2, s)
+	s, err = v.Validate("42")
+	assert.NoError(t, err)
+	assert.Equal(t, 42.0, s)
+	s, err = v.Validate(4.2)
+	assert.NoError(t, err)
+	assert.Equal(t, 4.2, s)
+	for _, input := range []string{"4.2abc", "", "NaN", "Inf", "4,2"} {
+		s, err = v.Validate(input)
+		assert.EqualError(t, err, "not a float", input)
+		assert.Nil(t, s, input)
+	}
+	s, err = schema.Float{}.Validate("4.2")
+	assert.EqualError(t, err, "not a float")
+	assert.Nil(t, s)
+}
+
+func TestFloatValidatorNumber(t *testing.T) {
+	s, err := schema.Float{}.Validate(json.Number("4.2"))
+	assert.NoError(t, err)
+	assert.Equal(t, 4.2, s)
+	s, err = schema.Float{}.Validate(json.Number("4,2"))
+	assert.EqualError(t, err, "not a float")
+	assert.Nil(t, s)
+}
+
 func TestFloatLesser(t *testing.T) {
 	cases := []struct {
 		name         string
@@ -112,3 +159,16 @@ func TestFloatLesser(t *testing.T) {
 		})
 	}
 }
+
+func TestFloatDescribe(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{"type": "float"}, schema.Float{}.Describe())
+	assert.Equal(t, map[string]interface{}{
+		"type":         "float",
+		"allowed":      []float64{0.5, 1.5},
+		"max":          2.0,
+		"exclusiveMax": true,
+	}, schema.Float{
+		Allowed:    []float64{0.5, 1.5},
+		Boundaries: &schema.Boundaries{Min: math.NaN(), Max: 2, ExclusiveMax: true},
+	}.Describe())
+}
@@ -0,0 +1,135 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GeoPoint validates geographic points given as a GeoJSON Point object
+// ({"type": "Point", "coordinates": [longitude, latitude]}), a latitude and
+// longitude object ({"lat": latitude, "lng": longitude}) or a GeoJSON position
+// array ([longitude, latitude]).
+//
+// Whatever the input, the value is normalized with float64 coordinates to the
+// representation selected by Format:
+//
+//   - "" (default): a GeoJSON Point object, which can be indexed as is by
+//     storage handlers supporting GeoJSON (i.e.: MongoDB 2dsphere indexes);
+//   - "object": a {"lat": latitude, "lng": longitude} object;
+//   - "geojson-array": a [longitude, latitude] array.
+//
+// GeoPoint implements FieldSerializer so values stored using another format
+// are represented using Format.
+type GeoPoint struct {
+	Format string
+}
+
+// Compile implements the Compiler interface.
+func (v *GeoPoint) Compile(rc ReferenceChecker) error {
+	switch v.Format {
+	case "", "object", "geojson-array":
+		return nil
+	}
+	return fmt.Errorf("invalid Format (%s): must be empty, object or geojson-array", v.Format)
+}
+
+// Validate implements FieldValidator.
+func (v GeoPoint) Validate(value interface{}) (interface{}, error) {
+	lng, lat, err := parseGeoPoint(value)
+	if err != nil {
+		return nil, err
+	}
+	if lng < -180 || lng > 180 {
+		return nil, errors.New("longitude is out of range [-180, 180]")
+	}
+	if lat < -90 || lat > 90 {
+		return nil, errors.New("latitude is out of range [-90, 90]")
+	}
+	return v.format(lng, lat), nil
+}
+
+// Serialize implements FieldSerializer.
+func (v GeoPoint) Serialize(value interface{}) (interface{}, error) {
+	lng, lat, err := parseGeoPoint(value)
+	if err != nil {
+		return nil, err
+	}
+	return v.format(lng, lat), nil
+}
+
+// format returns the representation of the point selected by v.Format.
+func (v GeoPoint) format(lng, lat float64) interface{} {
+	switch v.Format {
+	case "object":
+		return map[string]interface{}{"lat": lat, "lng": lng}
+	case "geojson-array":
+		return []interface{}{lng, lat}
+	}
+	return map[string]interface{}{
+		"type":        "Point",
+		"coordinates": []interface{}{lng, lat},
+	}
+}
+
+// parseGeoPoint returns the coordinates of a point in any of the formats
+// supported by GeoPoint.
+func parseGeoPoint(value interface{}) (lng, lat float64, err error) {
+	switch t := value.(type) {
+	case []interface{}:
+		return parseGeoCoordinates(t)
+	case map[string]interface{}:
+		_, hasType := t["type"]
+		_, hasCoords := t["coordinates"]
+		if hasType || hasCoords {
+			if err = checkObjectKeys(t, "type", "coordinates"); err != nil {
+				return
+			}
+			if typ, _ := t["type"].(string); typ != "Point" {
+				return 0, 0, errors.New("type is not Point")
+			}
+			coords, _ := t["coordinates"].([]interface{})
+			return parseGeoCoordinates(coords)
+		}
+		if err = checkObjectKeys(t, "lat", "lng"); err != nil {
+			return
+		}
+		return parseGeoNumbers(t["lng"], t["lat"])
+	}
+	return 0, 0, errors.New("not an object or a [longitude, latitude] array")
+}
+
+// checkObjectKeys returns an error listing the keys of m which are not in keys.
+func checkObjectKeys(m map[string]interface{}, keys ...string) error {
+	var invalid []string
+	for k := range m {
+		if k != keys[0] && k != keys[1] {
+			invalid = append(invalid, k)
+		}
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return fmt.Errorf("invalid key: %s", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+func parseGeoCoordinates(coords []interface{}) (lng, lat float64, err error) {
+	if len(coords) != 2 {
+		return 0, 0, errors.New("coordinates is not a [longitude, latitude] array")
+	}
+	return parseGeoNumbers(coords[0], coords[1])
+}
+
+func parseGeoNumbers(lngValue, latValue interface{}) (lng, lat float64, err error) {
+	lng, ok := isNumber(lngValue)
+	if !ok {
+		return 0, 0, errors.New("longitude is not a number")
+	}
+	lat, ok = isNumber(latValue)
+	if !ok {
+		return 0, 0, errors.New("latitude is not a number")
+	}
+	return lng, lat, nil
+}
@@ -0,0 +1,260 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestGeoPointValidate(t *testing.T) {
+	point := func(coords ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "Point", "coordinates": coords}
+	}
+	cases := []fieldValidatorTestCase{
+		{
+			Name:      `Validate(Point)`,
+			Validator: &schema.GeoPoint{},
+			Input:     point(2.35, 48.85),
+			Expect:    point(2.35, 48.85),
+		},
+		{
+			Name:      `Validate(Point{int})`,
+			Validator: &schema.GeoPoint{},
+			Input:     point(2, -48),
+			Expect:    point(2.0, -48.0),
+		},
+		{
+			Name:      `Validate(string)`,
+			Validator: &schema.GeoPoint{},
+			Input:     "2.35,48.85",
+			Error:     "not an object or a [longitude, latitude] array",
+		},
+		{
+			Name:      `Validate(extra keys)`,
+			Validator: &schema.GeoPoint{},
+			Input:     map[string]interface{}{"type": "Point", "coordinates": []interface{}{0.0, 0.0}, "foo": 1, "bar": 2},
+			Error:     "invalid key: bar, foo",
+		},
+		{
+			Name:      `Validate(LineString)`,
+			Validator: &schema.GeoPoint{},
+			Input:     map[string]interface{}{"type": "LineString", "coordinates": []interface{}{0.0, 0.0}},
+			Error:     "type is not Point",
+		},
+		{
+			Name:      `Validate(missing coordinates)`,
+			Validator: &schema.GeoPoint{},
+			Input:     map[string]interface{}{"type": "Point"},
+			Error:     "coordinates is not a [longitude, latitude] array",
+		},
+		{
+			Name:      `Validate(3 coordinates)`,
+			Validator: &schema.GeoPoint{},
+			Input:     point(1.0, 2.0, 3.0),
+			Error:     "coordinates is not a [longitude, latitude] array",
+		},
+		{
+			Name:      `Validate(non-numeric longitude)`,
+			Validator: &schema.GeoPoint{},
+			Input:     point("2.35", 48.85),
+			Error:     "longitude is not a number",
+		},
+		{
+			Name:      `Validate(non-numeric latitude)`,
+			Validator: &schema.GeoPoint{},
+			Input:     point(2.35, nil),
+			Error:     "latitude is not a number",
+		},
+		{
+			Name:      `Validate(longitude out of range)`,
+			Validator: &schema.GeoPoint{},
+			Input:     point(180.1, 0.0),
+			Error:     "longitude is out of range [-180, 180]",
+		},
+		{
+			Name:      `Validate(boundaries)`,
+			Validator: &schema.GeoPoint{},
+			Input:     point(-180, 90),
+			Expect:    point(-180.0, 90.0),
+		},
+		{
+			Name:      `Validate(latitude out of range)`,
+			Validator: &schema.GeoPoint{},
+			Input:     point(180, -90.000001),
+			Error:     "latitude is out of range [-90, 90]",
+		},
+		{
+			Name:      `Validate({lat,lng})`,
+			Validator: &schema.GeoPoint{},
+			Input:     map[string]interface{}{"lat": 48.85, "lng": 2.35},
+			Expect:    point(2.35, 48.85),
+		},
+		{
+			Name:      `Validate([lng,lat])`,
+			Validator: &schema.GeoPoint{},
+			Input:     []interface{}{2.35, 48.85},
+			Expect:    point(2.35, 48.85),
+		},
+		{
+			Name:      `{Format:object}.Validate(Point)`,
+			Validator: &schema.GeoPoint{Format: "object"},
+			Input:     point(2.35, 48.85),
+			Expect:    map[string]interface{}{"lat": 48.85, "lng": 2.35},
+		},
+		{
+			Name:      `{Format:object}.Validate({lat,lng})`,
+			Validator: &schema.GeoPoint{Format: "object"},
+			Input:     map[string]interface{}{"lat": 90, "lng": 180},
+			Expect:    map[string]interface{}{"lat": 90.0, "lng": 180.0},
+		},
+		{
+			Name:      `{Format:object}.Validate({lat,lng} out of range)`,
+			Validator: &schema.GeoPoint{Format: "object"},
+			Input:     map[string]interface{}{"lat": 91, "lng": 2.35},
+			Error:     "latitude is out of range [-90, 90]",
+		},
+		{
+			Name:      `{Format:object}.Validate({lat,lng,alt})`,
+			Validator: &schema.GeoPoint{Format: "object"},
+			Input:     map[string]interface{}{"lat": 48.85, "lng": 2.35, "alt": 35},
+			Error:     "invalid key: alt",
+		},
+		{
+			Name:      `{Format:object}.Validate({lat})`,
+			Validator: &schema.GeoPoint{Format: "object"},
+			Input:     map[string]interface{}{"lat": 48.85},
+			Error:     "longitude is not a number",
+		},
+		{
+			Name:      `{Format:geojson-array}.Validate([lng,lat])`,
+			Validator: &schema.GeoPoint{Format: "geojson-array"},
+			Input:     []interface{}{-180, -90},
+			Expect:    []interface{}{-180.0, -90.0},
+		},
+		{
+			Name:      `{Format:geojson-array}.Validate({lat,lng})`,
+			Validator: &schema.GeoPoint{Format: "geojson-array"},
+			Input:     map[string]interface{}{"lat": 48.85, "lng": 2.35},
+			Expect:    []interface{}{2.35, 48.85},
+		},
+		{
+			Name:      `{Format:geojson-array}.Validate([lng,lat] out of range)`,
+			Validator: &schema.GeoPoint{Format: "geojson-array"},
+			Input:     []interface{}{-180.5, 0},
+			Error:     "longitude is out of range [-180, 180]",
+		},
+		{
+			Name:      `{Format:geojson-array}.Validate([lng])`,
+			Validator: &schema.GeoPoint{Format: "geojson-array"},
+			Input:     []interface{}{2.35},
+			Error:     "coordinates is not a [longitude, latitude] array",
+		},
+		{
+			// Common mistake of swapping latitude and longitude.
+			Name:      `Validate([lat, lng])`,
+			Validator: &schema.GeoPoint{},
+			Input:     point(48.85, 122.42),
+			Error:     "latitude is out of range [-90, 90]",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestGeoPointCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{Name: "{Format:}", Compiler: &schema.GeoPoint{}},
+		{Name: "{Format:object}", Compiler: &schema.GeoPoint{Format: "object"}},
+		{Name: "{Format:geojson-array}", Compiler: &schema.GeoPoint{Format: "geojson-array"}},
+		{
+			Name:     "{Format:wkt}",
+			Compiler: &schema.GeoPoint{Format: "wkt"},
+			Error:    "invalid Format (wkt): must be empty, object or geojson-array",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestGeoPointSerialize(t *testing.T) {
+	cases := []fieldSerializerTestCase{
+		{
+			Name:       `{Format:object}.Serialize({lat,lng})`,
+			Serializer: &schema.GeoPoint{Format: "object"},
+			Input:      map[string]interface{}{"lat": 48.85, "lng": 2.35},
+			Expect:     map[string]interface{}{"lat": 48.85, "lng": 2.35},
+		},
+		{
+			Name:       `{Format:object}.Serialize([lng,lat])`,
+			Serializer: &schema.GeoPoint{Format: "object"},
+			Input:      []interface{}{2.35, 48.85},
+			Expect:     map[string]interface{}{"lat": 48.85, "lng": 2.35},
+		},
+		{
+			Name:       `{Format:geojson-array}.Serialize({lat,lng})`,
+			Serializer: &schema.GeoPoint{Format: "geojson-array"},
+			Input:      map[string]interface{}{"lat": 48.85, "lng": 2.35},
+			Expect:     []interface{}{2.35, 48.85},
+		},
+		{
+			Name:       `{}.Serialize([lng,lat])`,
+			Serializer: &schema.GeoPoint{},
+			Input:      []interface{}{2.35, 48.85},
+			Expect:     map[string]interface{}{"type": "Point", "coordinates": []interface{}{2.35, 48.85}},
+		},
+		{
+			Name:       `{}.Serialize(string)`,
+			Serializer: &schema.GeoPoint{},
+			Input:      "2.35,48.85",
+			Error:      "not an object or a [longitude, latitude] array",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestGeoPointSubSchema(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"place": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"name":     {Validator: &schema.String{}},
+						"location": {Required: true, Validator: &schema.GeoPoint{}},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	doc, errs := s.Validate(map[string]interface{}{
+		"place": map[string]interface{}{
+			"name":     "Paris",
+			"location": map[string]interface{}{"type": "Point", "coordinates": []interface{}{2, 48.85}},
+		},
+	}, map[string]interface{}{})
+	assert.Len(t, errs, 0)
+	assert.Equal(t, map[string]interface{}{
+		"place": map[string]interface{}{
+			"name":     "Paris",
+			"location": map[string]interface{}{"type": "Point", "coordinates": []interface{}{2.0, 48.85}},
+		},
+	}, doc)
+
+	_, errs = s.Validate(map[string]interface{}{
+		"place": map[string]interface{}{
+			"location": map[string]interface{}{"type": "Point", "coordinates": []interface{}{200.0, 48.85}},
+		},
+	}, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{
+		"place": {map[string][]interface{}{
+			"location": {schema.ValidationError{Code: schema.CodeValidator, Message: "longitude is out of range [-180, 180]", Field: "location"}},
+		}},
+	}, errs)
+}
@@ -0,0 +1,88 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Hostname validates DNS host and domain names (RFC 1123): dot separated
+// labels of at most 63 letters, digits and hyphens, not starting nor ending
+// with a hyphen, for a total of at most 253 characters.
+type Hostname struct {
+	// AllowWildcard accepts names starting with a "*." wildcard label (i.e.:
+	// "*.example.com").
+	AllowWildcard bool
+	// AllowIDN accepts internationalized domain names, converted to their
+	// ASCII form (i.e.: "bücher.example" is stored as
+	// "xn--bcher-kva.example"). Note that the labels are lowercased but not
+	// otherwise normalized (no NFKC nor nameprep mapping).
+	AllowIDN bool
+}
+
+// Compile implements the Compiler interface.
+func (v *Hostname) Compile(rc ReferenceChecker) error {
+	return nil
+}
+
+// Validate validates and normalizes host names: the name is lowercased and
+// the trailing dot of fully qualified names is removed.
+func (v Hostname) Validate(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("not a string")
+	}
+	name := strings.ToLower(strings.TrimSuffix(s, "."))
+	wildcard := strings.HasPrefix(name, "*.")
+	if wildcard {
+		if !v.AllowWildcard {
+			return nil, errors.New("wildcard not allowed")
+		}
+		name = name[2:]
+	}
+	if name == "" {
+		return nil, errors.New("empty hostname")
+	}
+	for i := 0; i < len(name); i++ {
+		if name[i] >= utf8.RuneSelf {
+			if !v.AllowIDN {
+				return nil, errors.New("internationalized domain names not allowed")
+			}
+			name = domainToASCII(name)
+			break
+		}
+	}
+	for _, label := range strings.Split(name, ".") {
+		if err := checkHostnameLabel(label); err != nil {
+			return nil, err
+		}
+	}
+	if wildcard {
+		name = "*." + name
+	}
+	if len(name) > 253 {
+		return nil, errors.New("hostname is longer than 253 characters")
+	}
+	return name, nil
+}
+
+// checkHostnameLabel returns an error if label is not a valid lowercase
+// hostname label.
+func checkHostnameLabel(label string) error {
+	if label == "" {
+		return errors.New("empty label")
+	}
+	if len(label) > 63 {
+		return fmt.Errorf("label %s is longer than 63 characters", label)
+	}
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return fmt.Errorf("label %s starts or ends with a hyphen", label)
+	}
+	for i := 0; i < len(label); i++ {
+		if c := label[i]; (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return fmt.Errorf("label %s contains invalid characters", label)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package schema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestHostnameValidate(t *testing.T) {
+	cases := []fieldValidatorTestCase{
+		{
+			Name:      `Validate(example.com)`,
+			Validator: &schema.Hostname{},
+			Input:     "example.com",
+			Expect:    "example.com",
+		},
+		{
+			Name:      `Validate(WWW.Example.COM.)`,
+			Validator: &schema.Hostname{},
+			Input:     "WWW.Example.COM.",
+			Expect:    "www.example.com",
+		},
+		{
+			Name:      `Validate(localhost)`,
+			Validator: &schema.Hostname{},
+			Input:     "localhost",
+			Expect:    "localhost",
+		},
+		{
+			Name:      `Validate(xn--bcher-kva.example)`,
+			Validator: &schema.Hostname{},
+			Input:     "xn--bcher-kva.example",
+			Expect:    "xn--bcher-kva.example",
+		},
+		{
+			Name:      `{AllowIDN:true}.Validate(Bücher.example)`,
+			Validator: &schema.Hostname{AllowIDN: true},
+			Input:     "Bücher.example",
+			Expect:    "xn--bcher-kva.example",
+		},
+		{
+			Name:      `{AllowIDN:true}.Validate(münchen.de)`,
+			Validator: &schema.Hostname{AllowIDN: true},
+			Input:     "münchen.de",
+			Expect:    "xn--mnchen-3ya.de",
+		},
+		{
+			Name:      `Validate(bücher.example)`,
+			Validator: &schema.Hostname{},
+			Input:     "bücher.example",
+			Error:     "internationalized domain names not allowed",
+		},
+		{
+			Name:      `{AllowWildcard:true}.Validate(*.Example.com)`,
+			Validator: &schema.Hostname{AllowWildcard: true},
+			Input:     "*.Example.com",
+			Expect:    "*.example.com",
+		},
+		{
+			Name:      `{AllowWildcard:true,AllowIDN:true}.Validate(*.bücher.example)`,
+			Validator: &schema.Hostname{AllowWildcard: true, AllowIDN: true},
+			Input:     "*.bücher.example",
+			Expect:    "*.xn--bcher-kva.example",
+		},
+		{
+			Name:      `Validate(*.example.com)`,
+			Validator: &schema.Hostname{},
+			Input:     "*.example.com",
+			Error:     "wildcard not allowed",
+		},
+		{
+			Name:      `{AllowWildcard:true}.Validate(www.*.example.com)`,
+			Validator: &schema.Hostname{AllowWildcard: true},
+			Input:     "www.*.example.com",
+			Error:     "label * contains invalid characters",
+		},
+		{
+			Name:      `{AllowWildcard:true}.Validate(*.)`,
+			Validator: &schema.Hostname{AllowWildcard: true},
+			Input:     "*.",
+			Error:     "label * contains invalid characters",
+		},
+		{
+			Name:      `Validate(63 characters label)`,
+			Validator: &schema.Hostname{},
+			Input:     strings.Repeat("a", 63) + ".com",
+			Expect:    strings.Repeat("a", 63) + ".com",
+		},
+		{
+			Name:      `Validate(64 characters label)`,
+			Validator: &schema.Hostname{},
+			Input:     strings.Repeat("a", 64) + ".com",
+			Error:     "label " + strings.Repeat("a", 64) + " is longer than 63 characters",
+		},
+		{
+			Name:      `Validate(255 characters)`,
+			Validator: &schema.Hostname{},
+			Input:     strings.Repeat(strings.Repeat("a", 63)+".", 4),
+			Error:     "hostname is longer than 253 characters",
+		},
+		{
+			Name:      `Validate(example..com)`,
+			Validator: &schema.Hostname{},
+			Input:     "example..com",
+			Error:     "empty label",
+		},
+		{
+			Name:      `Validate(-example.com)`,
+			Validator: &schema.Hostname{},
+			Input:     "-example.com",
+			Error:     "label -example starts or ends with a hyphen",
+		},
+		{
+			Name:      `Validate(exa_mple.com)`,
+			Validator: &schema.Hostname{},
+			Input:     "exa_mple.com",
+			Error:     "label exa_mple contains invalid characters",
+		},
+		{
+			Name:      `Validate("")`,
+			Validator: &schema.Hostname{},
+			Input:     "",
+			Error:     "empty hostname",
+		},
+		{
+			Name:      `Validate(1)`,
+			Validator: &schema.Hostname{},
+			Input:     1,
+			Error:     "not a string",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
@@ -1,15 +1,36 @@
 package schema
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"strconv"
 )
 
 // Integer validates integer based values.
 type Integer struct {
 	Allowed    []int
 	Boundaries *Boundaries
+	// Coerce accepts strings holding a decimal integer (i.e.: "42") and
+	// converts them to int.
+	Coerce bool
+	// Strict rejects numbers which are not written as integers (i.e.: "3.0"
+	// or "3e0"), which are otherwise accepted when integral. The check
+	// applies to json.Number values: the numbers of REST payloads are passed
+	// as is to strict fields (including their transformers and hooks), see
+	// FieldNumberValidator. float64 values don't retain their literal form,
+	// so integral ones are still accepted. Strict can't be combined with
+	// Coerce.
+	Strict bool
+}
+
+// Compile implements the Compiler interface.
+func (v *Integer) Compile(rc ReferenceChecker) error {
+	if v.Coerce && v.Strict {
+		return errors.New("Coerce and Strict can't be both set")
+	}
+	return nil
 }
 
 // ValidateQuery implements schema.FieldQueryValidator interface
@@ -25,6 +46,18 @@ func (v Integer) get(value interface{}) (int, error) {
 	return i, nil
 }
 
+// Describe implements the Describer interface.
+func (v Integer) Describe() map[string]interface{} {
+	m := map[string]interface{}{"type": "integer"}
+	if len(v.Allowed) > 0 {
+		m["allowed"] = v.Allowed
+	}
+	if v.Boundaries != nil {
+		v.Boundaries.describe(m)
+	}
+	return m
+}
+
 // Validate validates and normalize integer based value.
 func (v Integer) Validate(value interface{}) (interface{}, error) {
 	val, err := v.parse(value)
@@ -36,11 +69,10 @@ func (v Integer) Validate(value interface{}) (interface{}, error) {
 		return nil, err
 	}
 	if v.Boundaries != nil {
-		if float64(i) < v.Boundaries.Min {
-			return nil, fmt.Errorf("is lower than %.0f", v.Boundaries.Min)
-		}
-		if float64(i) > v.Boundaries.Max {
-			return nil, fmt.Errorf("is greater than %.0f", v.Boundaries.Max)
+		cmp := func(bound float64) int { return cmpFloat(float64(i), bound) }
+		format := func(bound float64) string { return fmt.Sprintf("%.0f", bound) }
+		if err := v.Boundaries.check(cmp, format); err != nil {
+			return nil, err
 		}
 	}
 	if len(v.Allowed) > 0 {
@@ -53,13 +85,34 @@ func (v Integer) Validate(value interface{}) (interface{}, error) {
 		}
 		if !found {
 			// TODO: build the list of allowed values.
-			return nil, fmt.Errorf("not one of the allowed values")
+			return nil, FieldError{CodeAllowed, "not one of the allowed values", nil}
 		}
 	}
 	return i, nil
 }
 
+// AcceptsJSONNumber implements the FieldNumberValidator interface: numbers
+// are checked as json.Number values in Strict mode.
+func (v Integer) AcceptsJSONNumber() bool {
+	return v.Strict
+}
+
 func (v Integer) parse(value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok && v.Coerce {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errors.New("not an integer")
+		}
+		return i, nil
+	}
+	if n, ok := value.(json.Number); ok {
+		if i, err := strconv.Atoi(string(n)); err == nil {
+			return i, nil
+		}
+		if f, err := n.Float64(); err == nil && !v.Strict {
+			value = f
+		}
+	}
 	if f, ok := value.(float64); ok {
 		// JSON unmarshaling treat all numbers as float64, try to convert it to
 		// int if not fraction.
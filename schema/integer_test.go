@@ -1,6 +1,7 @@
 package schema_test
 
 import (
+	"encoding/json"
 	"errors"
 	"math"
 	"reflect"
@@ -50,13 +51,13 @@ func TestIntegerValidator(t *testing.T) {
 	assert.EqualError(t, err, "not an integer")
 	assert.Nil(t, s)
 	s, err = schema.Integer{Boundaries: &schema.Boundaries{Min: 0, Max: 2}}.Validate(3)
-	assert.EqualError(t, err, "is greater than 2")
+	assert.EqualError(t, err, "must be lower than or equal to 2")
 	assert.Nil(t, s)
 	s, err = schema.Integer{Boundaries: &schema.Boundaries{Min: 0, Max: 2}}.Validate(1)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, s)
 	s, err = schema.Integer{Boundaries: &schema.Boundaries{Min: 2, Max: 10}}.Validate(1)
-	assert.EqualError(t, err, "is lower than 2")
+	assert.EqualError(t, err, "must be greater than or equal to 2")
 	assert.Nil(t, s)
 	s, err = schema.Integer{Boundaries: &schema.Boundaries{Min: 2, Max: 10}}.Validate(3)
 	assert.NoError(t, err)
@@ -74,10 +75,10 @@ func TestIntegerValidator(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 3, s)
 	s, err = schema.Integer{Boundaries: &schema.Boundaries{}}.Validate(1)
-	assert.EqualError(t, err, "is greater than 0")
+	assert.EqualError(t, err, "must be lower than or equal to 0")
 	assert.Nil(t, s)
 	s, err = schema.Integer{Boundaries: &schema.Boundaries{}}.Validate(-1)
-	assert.EqualError(t, err, "is lower than 0")
+	assert.EqualError(t, err, "must be greater than or equal to 0")
 	assert.Nil(t, s)
 	s, err = schema.Integer{Allowed: []int{1, 2, 3}}.Validate(4)
 	assert.EqualError(t, err, "not one of the allowed values")
@@ -87,6 +88,73 @@ func TestIntegerValidator(t *testing.T) {
 	assert.Equal(t, 2, s)
 }
 
+func TestIntegerValidatorExclusiveBoundaries(t *testing.T) {
+	v := schema.Integer{Boundaries: &schema.Boundaries{Min: 0, Max: 10, ExclusiveMin: true, ExclusiveMax: true}}
+	s, err := v.Validate(0)
+	assert.EqualError(t, err, "must be greater than 0")
+	assert.Nil(t, s)
+	s, err = v.Validate(10)
+	assert.EqualError(t, err, "must be lower than 10")
+	assert.Nil(t, s)
+	s, err = v.Validate(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, s)
+	s, err = v.Validate(9)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, s)
+	s, err = schema.Integer{Boundaries: &schema.Boundaries{Min: 0, Max: math.Inf(1), ExclusiveMin: true}}.Validate(-1)
+	assert.EqualError(t, err, "must be greater than 0")
+	assert.Nil(t, s)
+}
+
+func TestIntegerValidatorCoerce(t *testing.T) {
+	v := schema.Integer{Coerce: true}
+	s, err := v.Validate("42")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, s)
+	s, err = v.Validate("-42")
+	assert.NoError(t, err)
+	assert.Equal(t, -42, s)
+	s, err = v.Validate(42.0)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, s)
+	for _, input := range []string{"42abc", "4.2", "", " 42", "1e3"} {
+		s, err = v.Validate(input)
+		assert.EqualError(t, err, "not an integer", input)
+		assert.Nil(t, s, input)
+	}
+	s, err = schema.Integer{Coerce: true, Boundaries: &schema.Boundaries{Min: 0, Max: 10}}.Validate("42")
+	assert.EqualError(t, err, "must be lower than or equal to 10")
+	assert.Nil(t, s)
+}
+
+func TestIntegerValidatorStrict(t *testing.T) {
+	lenient, strict := schema.Integer{}, schema.Integer{Strict: true}
+	for _, v := range []schema.Integer{lenient, strict} {
+		s, err := v.Validate(json.Number("3"))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, s)
+		s, err = v.Validate(3.0)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, s)
+		for _, input := range []interface{}{json.Number("3.5"), 3.5, "3"} {
+			s, err = v.Validate(input)
+			assert.EqualError(t, err, "not an integer", "%v", input)
+			assert.Nil(t, s)
+		}
+	}
+	for _, input := range []json.Number{"3.0", "3e0"} {
+		s, err := lenient.Validate(input)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, s)
+		s, err = strict.Validate(input)
+		assert.EqualError(t, err, "not an integer", string(input))
+		assert.Nil(t, s)
+	}
+	assert.EqualError(t, (&schema.Integer{Coerce: true, Strict: true}).Compile(nil), "Coerce and Strict can't be both set")
+	assert.NoError(t, (&schema.Integer{Strict: true}).Compile(nil))
+}
+
 func TestIntegerLesser(t *testing.T) {
 	cases := []struct {
 		name         string
@@ -111,3 +179,21 @@ func TestIntegerLesser(t *testing.T) {
 		})
 	}
 }
+
+func TestIntegerDescribe(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, schema.Integer{}.Describe())
+	assert.Equal(t, map[string]interface{}{
+		"type":         "integer",
+		"allowed":      []int{1, 2},
+		"min":          0.0,
+		"exclusiveMin": true,
+		"max":          10.0,
+	}, schema.Integer{
+		Allowed:    []int{1, 2},
+		Boundaries: &schema.Boundaries{Min: 0, Max: 10, ExclusiveMin: true},
+	}.Describe())
+	assert.Equal(t, map[string]interface{}{
+		"type": "integer",
+		"min":  1.0,
+	}, schema.Integer{Boundaries: &schema.Boundaries{Min: 1, Max: math.Inf(1)}}.Describe())
+}
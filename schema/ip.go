@@ -10,10 +10,33 @@ type IP struct {
 	// StoreBinary activates storage of the IP as binary to save space.
 	// The storage requirement is 4 bytes for IPv4 and 16 bytes for IPv6.
 	StoreBinary bool
+	// AllowV4 and AllowV6 restrict the accepted IP versions. When none is set,
+	// both IPv4 and IPv6 addresses are accepted.
+	AllowV4 bool
+	AllowV6 bool
+	// DenyPrivate rejects loopback, private (RFC 1918 and RFC 4193),
+	// link-local and unspecified addresses. It can not be combined with CIDR,
+	// as a network may hold both private and public addresses: Compile
+	// returns an error if both are set.
+	DenyPrivate bool
+	// CIDR makes the validator accept IP networks in CIDR notation instead of
+	// single addresses, with the same behavior as the CIDR validator.
+	CIDR bool
+}
+
+// Compile implements the Compiler interface.
+func (v *IP) Compile(rc ReferenceChecker) error {
+	if v.CIDR && v.DenyPrivate {
+		return errors.New("DenyPrivate is not supported with CIDR")
+	}
+	return nil
 }
 
 // Validate implements FieldValidator
 func (v IP) Validate(value interface{}) (interface{}, error) {
+	if v.CIDR {
+		return v.cidr().Validate(value)
+	}
 	s, ok := value.(string)
 	if !ok {
 		return nil, errors.New("invalid type")
@@ -22,6 +45,12 @@ func (v IP) Validate(value interface{}) (interface{}, error) {
 	if ip == nil {
 		return nil, errors.New("invalid IP format")
 	}
+	if err := checkIPVersion(ip, v.AllowV4, v.AllowV6); err != nil {
+		return nil, err
+	}
+	if v.DenyPrivate && isLocalIP(ip) {
+		return nil, errors.New("private IP not allowed")
+	}
 	if v.StoreBinary {
 		// If IP is a v4, store it's 4 bytes representation to save space.
 		if v4 := ip.To4(); v4 != nil {
@@ -34,6 +63,9 @@ func (v IP) Validate(value interface{}) (interface{}, error) {
 
 // Serialize implements FieldSerializer.
 func (v IP) Serialize(value interface{}) (interface{}, error) {
+	if v.CIDR {
+		return v.cidr().Serialize(value)
+	}
 	if !v.StoreBinary {
 		return value, nil
 	}
@@ -46,3 +78,75 @@ func (v IP) Serialize(value interface{}) (interface{}, error) {
 	}
 	return net.IP(b).String(), nil
 }
+
+func (v IP) cidr() CIDR {
+	return CIDR{StoreBinary: v.StoreBinary, AllowV4: v.AllowV4, AllowV6: v.AllowV6}
+}
+
+// CIDR validates IP network values in CIDR notation.
+type CIDR struct {
+	// StoreBinary activates storage of the network as binary to save space.
+	// The IP is stored followed by the prefix length, for a storage
+	// requirement of 5 bytes for IPv4 and 17 bytes for IPv6.
+	StoreBinary bool
+	// AllowV4 and AllowV6 restrict the accepted IP versions. When none is set,
+	// both IPv4 and IPv6 networks are accepted.
+	AllowV4 bool
+	AllowV6 bool
+}
+
+// Validate implements FieldValidator. The network is normalized so host bits
+// are cleared (i.e.: 10.1.2.3/8 is stored as 10.0.0.0/8).
+func (v CIDR) Validate(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("invalid type")
+	}
+	_, ipnet, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, errors.New("invalid CIDR format")
+	}
+	if err := checkIPVersion(ipnet.IP, v.AllowV4, v.AllowV6); err != nil {
+		return nil, err
+	}
+	if v.StoreBinary {
+		ones, _ := ipnet.Mask.Size()
+		b := make([]byte, 0, len(ipnet.IP)+1)
+		b = append(b, ipnet.IP...)
+		return append(b, byte(ones)), nil
+	}
+	return ipnet.String(), nil
+}
+
+// Serialize implements FieldSerializer.
+func (v CIDR) Serialize(value interface{}) (interface{}, error) {
+	if !v.StoreBinary {
+		return value, nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return nil, errors.New("invalid type")
+	}
+	if len(b) != 5 && len(b) != 17 {
+		return nil, errors.New("invalid size")
+	}
+	ip := net.IP(b[:len(b)-1])
+	ipnet := net.IPNet{IP: ip, Mask: net.CIDRMask(int(b[len(b)-1]), len(ip)*8)}
+	return ipnet.String(), nil
+}
+
+// checkIPVersion returns an error if ip's version is not allowed. When neither
+// allowV4 nor allowV6 is set, all versions are allowed.
+func checkIPVersion(ip net.IP, allowV4, allowV6 bool) error {
+	if !allowV4 && !allowV6 {
+		return nil
+	}
+	if ip.To4() != nil {
+		if !allowV4 {
+			return errors.New("IPv4 not allowed")
+		}
+	} else if !allowV6 {
+		return errors.New("IPv6 not allowed")
+	}
+	return nil
+}
@@ -41,3 +41,144 @@ func TestIPValidatorSerialize(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "1.2.3.4", v)
 }
+
+func TestIPValidatorVersions(t *testing.T) {
+	v, err := IP{AllowV4: true}.Validate("1.2.3.4")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3.4", v)
+	v, err = IP{AllowV4: true}.Validate("2001:1265::ae4:0:5b:6b0")
+	assert.EqualError(t, err, "IPv6 not allowed")
+	assert.Nil(t, v)
+	v, err = IP{AllowV6: true}.Validate("1.2.3.4")
+	assert.EqualError(t, err, "IPv4 not allowed")
+	assert.Nil(t, v)
+	v, err = IP{AllowV6: true}.Validate("2001:1265::ae4:0:5b:6b0")
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:1265::ae4:0:5b:6b0", v)
+	v, err = IP{AllowV4: true, AllowV6: true}.Validate("2001:1265::ae4:0:5b:6b0")
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:1265::ae4:0:5b:6b0", v)
+}
+
+func TestIPValidatorDenyPrivate(t *testing.T) {
+	for _, ip := range []string{"10.0.0.1", "172.16.3.4", "192.168.1.1", "127.0.0.1", "169.254.1.1", "::1", "fe80::1", "fd00::1"} {
+		v, err := IP{DenyPrivate: true}.Validate(ip)
+		assert.EqualError(t, err, "private IP not allowed", ip)
+		assert.Nil(t, v)
+		_, err = IP{}.Validate(ip)
+		assert.NoError(t, err, ip)
+	}
+	v, err := IP{DenyPrivate: true}.Validate("8.8.8.8")
+	assert.NoError(t, err)
+	assert.Equal(t, "8.8.8.8", v)
+}
+
+func TestIPValidatorCompile(t *testing.T) {
+	assert.NoError(t, (&IP{DenyPrivate: true}).Compile(nil))
+	assert.NoError(t, (&IP{CIDR: true}).Compile(nil))
+	assert.EqualError(t, (&IP{CIDR: true, DenyPrivate: true}).Compile(nil), "DenyPrivate is not supported with CIDR")
+}
+
+func TestCIDRValidator(t *testing.T) {
+	v, err := CIDR{}.Validate("10.1.2.3/8")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", v)
+	v, err = CIDR{}.Validate("2001:DB8::/32")
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:db8::/32", v)
+	v, err = CIDR{}.Validate("10.1.2.3")
+	assert.EqualError(t, err, "invalid CIDR format")
+	assert.Nil(t, v)
+	v, err = CIDR{}.Validate("10.1.2.3/33")
+	assert.EqualError(t, err, "invalid CIDR format")
+	assert.Nil(t, v)
+	v, err = CIDR{}.Validate(1)
+	assert.EqualError(t, err, "invalid type")
+	assert.Nil(t, v)
+	v, err = CIDR{AllowV6: true}.Validate("10.0.0.0/8")
+	assert.EqualError(t, err, "IPv4 not allowed")
+	assert.Nil(t, v)
+	v, err = CIDR{AllowV4: true}.Validate("2001:db8::/32")
+	assert.EqualError(t, err, "IPv6 not allowed")
+	assert.Nil(t, v)
+	v, err = CIDR{StoreBinary: true}.Validate("192.168.1.0/24")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{192, 168, 1, 0, 24}, v)
+}
+
+func TestCIDRValidatorSerialize(t *testing.T) {
+	v, err := CIDR{}.Serialize("10.0.0.0/8")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", v)
+	v, err = CIDR{StoreBinary: true}.Serialize([]byte{192, 168, 1, 0, 24})
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.0/24", v)
+	v, err = CIDR{StoreBinary: true}.Serialize([]byte{0x20, 0x1, 0xd, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 32})
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:db8::/32", v)
+	v, err = CIDR{StoreBinary: true}.Serialize([]byte{1, 2, 3, 4})
+	assert.EqualError(t, err, "invalid size")
+	assert.Nil(t, v)
+	v, err = CIDR{StoreBinary: true}.Serialize("10.0.0.0/8")
+	assert.EqualError(t, err, "invalid type")
+	assert.Nil(t, v)
+}
+
+func TestIPValidatorCIDR(t *testing.T) {
+	v, err := IP{CIDR: true}.Validate("10.1.2.3/8")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", v)
+	v, err = IP{CIDR: true}.Validate("2001:DB8::1/32")
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:db8::/32", v)
+	v, err = IP{CIDR: true}.Validate("10.1.2.3")
+	assert.EqualError(t, err, "invalid CIDR format")
+	assert.Nil(t, v)
+	v, err = IP{CIDR: true, AllowV6: true}.Validate("10.0.0.0/8")
+	assert.EqualError(t, err, "IPv4 not allowed")
+	assert.Nil(t, v)
+	v, err = IP{CIDR: true, StoreBinary: true}.Validate("192.168.1.0/24")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{192, 168, 1, 0, 24}, v)
+	v, err = IP{CIDR: true, StoreBinary: true}.Serialize(v)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.0/24", v)
+}
+
+func TestIPValidatorInvalidMasks(t *testing.T) {
+	for _, s := range []string{"10.0.0.0/33", "10.0.0.0/-1", "10.0.0.0/", "10.0.0.0/255.0.0.0", "2001:db8::/129"} {
+		v, err := IP{CIDR: true}.Validate(s)
+		assert.EqualError(t, err, "invalid CIDR format", s)
+		assert.Nil(t, v)
+	}
+}
+
+func TestIPValidatorLeadingZeros(t *testing.T) {
+	for _, s := range []string{"01.2.3.4", "1.2.3.004", "010.0.0.1"} {
+		v, err := IP{}.Validate(s)
+		assert.EqualError(t, err, "invalid IP format", s)
+		assert.Nil(t, v)
+		v, err = IP{CIDR: true}.Validate(s + "/8")
+		assert.EqualError(t, err, "invalid CIDR format", s)
+		assert.Nil(t, v)
+	}
+}
+
+func TestIPValidatorV4Mapped(t *testing.T) {
+	// IPv4-mapped IPv6 addresses are normalized to their IPv4 form.
+	v, err := IP{}.Validate("::ffff:1.2.3.4")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3.4", v)
+	v, err = IP{}.Validate("::FFFF:0102:0304")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3.4", v)
+	v, err = IP{AllowV4: true}.Validate("::ffff:1.2.3.4")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3.4", v)
+	v, err = IP{AllowV6: true}.Validate("::ffff:1.2.3.4")
+	assert.EqualError(t, err, "IPv4 not allowed")
+	assert.Nil(t, v)
+	v, err = IP{StoreBinary: true}.Validate("::ffff:1.2.3.4")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4}, v)
+}
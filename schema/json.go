@@ -0,0 +1,81 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSON validates opaque JSON values which are stored as is, without
+// validating their structure against a schema (i.e.: metadata objects).
+type JSON struct {
+	// AllowObject and AllowArray restrict the accepted values to JSON objects
+	// and/or arrays. When none is set, any JSON value is accepted.
+	AllowObject bool
+	AllowArray  bool
+	// MaxBytes defines the maximum size of the value once marshaled to JSON
+	// (default no limit).
+	MaxBytes int
+	// MaxDepth defines the maximum nesting level of objects and arrays
+	// (default no limit). A flat object or array has a depth of 1.
+	MaxDepth int
+}
+
+// Validate implements FieldValidator.
+func (v JSON) Validate(value interface{}) (interface{}, error) {
+	if v.AllowObject || v.AllowArray {
+		_, isObject := value.(map[string]interface{})
+		_, isArray := value.([]interface{})
+		switch {
+		case v.AllowObject && v.AllowArray:
+			if !isObject && !isArray {
+				return nil, errors.New("not an object or array")
+			}
+		case v.AllowObject && !isObject:
+			return nil, errors.New("not an object")
+		case v.AllowArray && !isArray:
+			return nil, errors.New("not an array")
+		}
+	}
+	if v.MaxDepth > 0 && jsonDepth(value, v.MaxDepth) > v.MaxDepth {
+		return nil, fmt.Errorf("exceeds maximum depth of %d", v.MaxDepth)
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.New("not a JSON value")
+	}
+	if v.MaxBytes > 0 && len(b) > v.MaxBytes {
+		return nil, fmt.Errorf("is larger than %d bytes", v.MaxBytes)
+	}
+	return value, nil
+}
+
+// jsonDepth returns the nesting level of objects and arrays in value. The
+// exploration stops as soon as max is exceeded.
+func jsonDepth(value interface{}, max int) int {
+	var children []interface{}
+	switch t := value.(type) {
+	case map[string]interface{}:
+		for _, c := range t {
+			children = append(children, c)
+		}
+	case []interface{}:
+		children = t
+	default:
+		return 0
+	}
+	depth := 1
+	if max == 0 {
+		// Limit already reached.
+		return depth
+	}
+	for _, c := range children {
+		if d := 1 + jsonDepth(c, max-1); d > depth {
+			depth = d
+			if depth > max {
+				break
+			}
+		}
+	}
+	return depth
+}
@@ -0,0 +1,112 @@
+package schema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+// nestedJSON returns an object nested depth times.
+func nestedJSON(depth int) interface{} {
+	var v interface{} = "leaf"
+	for i := 0; i < depth; i++ {
+		v = map[string]interface{}{"a": v}
+	}
+	return v
+}
+
+func TestJSONValidate(t *testing.T) {
+	obj := map[string]interface{}{"a": []interface{}{1.0, "b", nil, true}}
+	cases := []fieldValidatorTestCase{
+		{Name: `Validate(object)`, Validator: &schema.JSON{}, Input: obj, Expect: obj},
+		{Name: `Validate(array)`, Validator: &schema.JSON{}, Input: []interface{}{"a"}, Expect: []interface{}{"a"}},
+		{Name: `Validate(string)`, Validator: &schema.JSON{}, Input: "a", Expect: "a"},
+		{Name: `Validate(number)`, Validator: &schema.JSON{}, Input: 1.5, Expect: 1.5},
+		{Name: `Validate(func)`, Validator: &schema.JSON{}, Input: func() {}, Error: "not a JSON value"},
+		{
+			Name:      `{AllowObject:true}.Validate(object)`,
+			Validator: &schema.JSON{AllowObject: true},
+			Input:     obj,
+			Expect:    obj,
+		},
+		{
+			Name:      `{AllowObject:true}.Validate(array)`,
+			Validator: &schema.JSON{AllowObject: true},
+			Input:     []interface{}{"a"},
+			Error:     "not an object",
+		},
+		{
+			Name:      `{AllowArray:true}.Validate(object)`,
+			Validator: &schema.JSON{AllowArray: true},
+			Input:     obj,
+			Error:     "not an array",
+		},
+		{
+			Name:      `{AllowObject:true,AllowArray:true}.Validate(array)`,
+			Validator: &schema.JSON{AllowObject: true, AllowArray: true},
+			Input:     []interface{}{"a"},
+			Expect:    []interface{}{"a"},
+		},
+		{
+			Name:      `{AllowObject:true,AllowArray:true}.Validate(string)`,
+			Validator: &schema.JSON{AllowObject: true, AllowArray: true},
+			Input:     "a",
+			Error:     "not an object or array",
+		},
+		{
+			Name:      `{MaxBytes:10}.Validate(short)`,
+			Validator: &schema.JSON{MaxBytes: 10},
+			Input:     map[string]interface{}{"a": "bc"},
+			Expect:    map[string]interface{}{"a": "bc"},
+		},
+		{
+			Name:      `{MaxBytes:10}.Validate(long)`,
+			Validator: &schema.JSON{MaxBytes: 10},
+			Input:     map[string]interface{}{"a": "bcd"},
+			Error:     "is larger than 10 bytes",
+		},
+		{
+			Name:      `{MaxDepth:3}.Validate(depth 3)`,
+			Validator: &schema.JSON{MaxDepth: 3},
+			Input:     nestedJSON(3),
+			Expect:    nestedJSON(3),
+		},
+		{
+			Name:      `{MaxDepth:3}.Validate(depth 4)`,
+			Validator: &schema.JSON{MaxDepth: 3},
+			Input:     nestedJSON(4),
+			Error:     "exceeds maximum depth of 3",
+		},
+		{
+			Name:      `{MaxDepth:3}.Validate(mixed depth 4)`,
+			Validator: &schema.JSON{MaxDepth: 3},
+			Input:     []interface{}{"a", map[string]interface{}{"b": []interface{}{[]interface{}{1.0}}}},
+			Error:     "exceeds maximum depth of 3",
+		},
+		{
+			Name:      `{MaxDepth:1}.Validate(scalar)`,
+			Validator: &schema.JSON{MaxDepth: 1},
+			Input:     "a",
+			Expect:    "a",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestJSONValidateDeep(t *testing.T) {
+	// Deeply nested payloads are rejected without exploring them entirely.
+	v := schema.JSON{MaxDepth: 32}
+	_, err := v.Validate(nestedJSON(10000))
+	if err == nil || err.Error() != "exceeds maximum depth of 32" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// Large payloads are rejected.
+	v = schema.JSON{MaxBytes: 1024}
+	_, err = v.Validate(strings.Repeat("a", 2048))
+	if err == nil || err.Error() != "is larger than 1024 bytes" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// LanguageTag validates BCP 47 (RFC 5646) language tags such as "en",
+// "en-US" or "zh-Hant-TW" using the golang.org/x/text/language package.
+// Subtags must be registered in the IANA registry ("xx-QQ" is rejected), and
+// valid tags are normalized to their canonical form (i.e.: "EN-us" is stored
+// as "en-US", "i-klingon" as "tlh" and "en-BU" as "en-MM").
+type LanguageTag struct {
+	// Supported restricts the accepted tags to the ones matching a tag of
+	// the given list with a high confidence, as defined by a
+	// language.Matcher (i.e.: "en" accepts "en-US" and "en-GB", and "zh-Hant"
+	// accepts "zh-TW" but not "zh-CN").
+	Supported []string
+	// Base reduces the tag to its primary language subtag (i.e.: "en-US" is
+	// stored as "en").
+	Base bool
+
+	matcher language.Matcher
+}
+
+// Compile implements the Compiler interface.
+func (v *LanguageTag) Compile(rc ReferenceChecker) error {
+	v.matcher = nil
+	if len(v.Supported) == 0 {
+		return nil
+	}
+	tags := make([]language.Tag, 0, len(v.Supported))
+	for _, s := range v.Supported {
+		tag, err := language.Parse(s)
+		if err != nil {
+			return fmt.Errorf("invalid supported language tag: %s", s)
+		}
+		tags = append(tags, tag)
+	}
+	v.matcher = language.NewMatcher(tags)
+	return nil
+}
+
+// Validate validates and normalizes language tag values.
+func (v LanguageTag) Validate(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("not a string")
+	}
+	tag, err := language.Parse(s)
+	if err != nil {
+		return nil, errors.New("invalid language tag")
+	}
+	if v.Base {
+		if base, conf := tag.Base(); conf == language.Exact {
+			tag = language.Make(base.String())
+		}
+	}
+	if v.matcher != nil {
+		if _, _, conf := v.matcher.Match(tag); conf < language.High {
+			return nil, fmt.Errorf("unsupported language tag %s", tag)
+		}
+	}
+	return tag.String(), nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestLanguageTagCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{
+			Name:     `{Supported:["en","fr-CA"]}`,
+			Compiler: &schema.LanguageTag{Supported: []string{"en", "fr-CA"}},
+		},
+		{
+			Name:     `{Supported:["en_"]}`,
+			Compiler: &schema.LanguageTag{Supported: []string{"en_"}},
+			Error:    "invalid supported language tag: en_",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestLanguageTagValidate(t *testing.T) {
+	cases := []fieldValidatorTestCase{
+		{Name: `Validate("en")`, Validator: &schema.LanguageTag{}, Input: "en", Expect: "en"},
+		{Name: `Validate("EN-us")`, Validator: &schema.LanguageTag{}, Input: "EN-us", Expect: "en-US"},
+		{Name: `Validate("en_US")`, Validator: &schema.LanguageTag{}, Input: "en_US", Expect: "en-US"},
+		{Name: `Validate("ZH-hant-tw")`, Validator: &schema.LanguageTag{}, Input: "ZH-hant-tw", Expect: "zh-Hant-TW"},
+		{Name: `Validate("es-419")`, Validator: &schema.LanguageTag{}, Input: "es-419", Expect: "es-419"},
+		{Name: `Validate("sl-rozaj-biske")`, Validator: &schema.LanguageTag{}, Input: "sl-rozaj-biske", Expect: "sl-rozaj-biske"},
+		{Name: `Validate("de-CH-1901")`, Validator: &schema.LanguageTag{}, Input: "de-CH-1901", Expect: "de-CH-1901"},
+		{Name: `Validate("zh-yue-HK")`, Validator: &schema.LanguageTag{}, Input: "zh-yue-HK", Expect: "yue-HK"},
+		{Name: `Validate("en-US-u-ca-gregory")`, Validator: &schema.LanguageTag{}, Input: "en-US-u-ca-gregory", Expect: "en-US-u-ca-gregory"},
+		{Name: `Validate("en-x-Private")`, Validator: &schema.LanguageTag{}, Input: "en-x-Private", Expect: "en-x-private"},
+		{Name: `Validate("x-whatever")`, Validator: &schema.LanguageTag{}, Input: "x-whatever", Expect: "x-whatever"},
+		{Name: `Validate("iw")`, Validator: &schema.LanguageTag{}, Input: "iw", Expect: "he"},
+		{Name: `Validate("en-BU")`, Validator: &schema.LanguageTag{}, Input: "en-BU", Expect: "en-MM"},
+		// Grandfathered tags.
+		{Name: `Validate("i-klingon")`, Validator: &schema.LanguageTag{}, Input: "i-klingon", Expect: "tlh"},
+		{Name: `Validate("en-GB-oed")`, Validator: &schema.LanguageTag{}, Input: "en-GB-oed", Expect: "en-GB-oxendict"},
+		{Name: `Validate("zh-min-nan")`, Validator: &schema.LanguageTag{}, Input: "zh-min-nan", Expect: "nan"},
+		{Name: `Validate("I-DEFAULT")`, Validator: &schema.LanguageTag{}, Input: "I-DEFAULT", Expect: "en-x-i-default"},
+		// Malformed tags.
+		{Name: `Validate(1)`, Validator: &schema.LanguageTag{}, Input: 1, Error: "not a string"},
+		{Name: `Validate("")`, Validator: &schema.LanguageTag{}, Input: "", Error: "invalid language tag"},
+		{Name: `Validate("e")`, Validator: &schema.LanguageTag{}, Input: "e", Error: "invalid language tag"},
+		{Name: `Validate("en-")`, Validator: &schema.LanguageTag{}, Input: "en-", Error: "invalid language tag"},
+		{Name: `Validate("en--US")`, Validator: &schema.LanguageTag{}, Input: "en--US", Error: "invalid language tag"},
+		{Name: `Validate("1en")`, Validator: &schema.LanguageTag{}, Input: "1en", Error: "invalid language tag"},
+		{Name: `Validate("en-US-US")`, Validator: &schema.LanguageTag{}, Input: "en-US-US", Error: "invalid language tag"},
+		{Name: `Validate("en-u")`, Validator: &schema.LanguageTag{}, Input: "en-u", Error: "invalid language tag"},
+		{Name: `Validate("en-x")`, Validator: &schema.LanguageTag{}, Input: "en-x", Error: "invalid language tag"},
+		{Name: `Validate("toolongtag")`, Validator: &schema.LanguageTag{}, Input: "toolongtag", Error: "invalid language tag"},
+		{Name: `Validate("en-US!")`, Validator: &schema.LanguageTag{}, Input: "en-US!", Error: "invalid language tag"},
+		// Unregistered subtags.
+		{Name: `Validate("xx-QQ")`, Validator: &schema.LanguageTag{}, Input: "xx-QQ", Error: "invalid language tag"},
+		{Name: `Validate("zz")`, Validator: &schema.LanguageTag{}, Input: "zz", Error: "invalid language tag"},
+		// Options.
+		{
+			Name:      `{Base:true}.Validate("EN-us")`,
+			Validator: &schema.LanguageTag{Base: true},
+			Input:     "EN-us",
+			Expect:    "en",
+		},
+		{
+			Name:      `{Supported:["en","fr-CA"]}.Validate("en-GB")`,
+			Validator: &schema.LanguageTag{Supported: []string{"en", "fr-CA"}},
+			Input:     "en-GB",
+			Expect:    "en-GB",
+		},
+		{
+			Name:      `{Supported:["en","fr-CA"]}.Validate("fr-ca")`,
+			Validator: &schema.LanguageTag{Supported: []string{"en", "fr-CA"}},
+			Input:     "fr-ca",
+			Expect:    "fr-CA",
+		},
+		{
+			Name:      `{Supported:["en","fr-CA"]}.Validate("fr")`,
+			Validator: &schema.LanguageTag{Supported: []string{"en", "fr-CA"}},
+			Input:     "fr",
+			Expect:    "fr",
+		},
+		{
+			Name:      `{Supported:["en","fr-CA"]}.Validate("de")`,
+			Validator: &schema.LanguageTag{Supported: []string{"en", "fr-CA"}},
+			Input:     "de",
+			Error:     "unsupported language tag de",
+		},
+		{
+			Name:      `{Supported:["zh-Hant"]}.Validate("zh-TW")`,
+			Validator: &schema.LanguageTag{Supported: []string{"zh-Hant"}},
+			Input:     "zh-TW",
+			Expect:    "zh-TW",
+		},
+		{
+			Name:      `{Supported:["zh-Hant"]}.Validate("zh-CN")`,
+			Validator: &schema.LanguageTag{Supported: []string{"zh-Hant"}},
+			Input:     "zh-CN",
+			Error:     "unsupported language tag zh-CN",
+		},
+		{
+			Name:      `{Supported:["en","fr"],Base:true}.Validate("fr-CA")`,
+			Validator: &schema.LanguageTag{Supported: []string{"en", "fr"}, Base: true},
+			Input:     "fr-CA",
+			Expect:    "fr",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
@@ -0,0 +1,40 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// MACAddress validates IEEE 802 MAC-48 and EUI-64 addresses.
+type MACAddress struct {
+	// Bits restricts the accepted addresses to 48 or 64 bits long addresses
+	// (default both).
+	Bits int
+}
+
+// Compile implements the Compiler interface.
+func (v *MACAddress) Compile(rc ReferenceChecker) error {
+	if v.Bits != 0 && v.Bits != 48 && v.Bits != 64 {
+		return fmt.Errorf("invalid Bits (%d): must be 48 or 64", v.Bits)
+	}
+	return nil
+}
+
+// Validate validates and normalizes MAC addresses to their lowercase colon
+// separated form. The colon (01:23:45:67:89:ab), dash (01-23-45-67-89-ab) and
+// dotted (0123.4567.89ab) notations are accepted.
+func (v MACAddress) Validate(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("invalid type")
+	}
+	hw, err := net.ParseMAC(s)
+	if err != nil || (len(hw) != 6 && len(hw) != 8) {
+		return nil, errors.New("invalid MAC address, use 01:23:45:67:89:ab, 01-23-45-67-89-ab or 0123.4567.89ab notation")
+	}
+	if v.Bits != 0 && len(hw)*8 != v.Bits {
+		return nil, fmt.Errorf("not a %d-bit MAC address", v.Bits)
+	}
+	return hw.String(), nil
+}
@@ -0,0 +1,87 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestMACAddressCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{
+			Name:     "{Bits:48}",
+			Compiler: &schema.MACAddress{Bits: 48},
+		},
+		{
+			Name:     "{Bits:32}",
+			Compiler: &schema.MACAddress{Bits: 32},
+			Error:    "invalid Bits (32): must be 48 or 64",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestMACAddressValidate(t *testing.T) {
+	const invalid = "invalid MAC address, use 01:23:45:67:89:ab, 01-23-45-67-89-ab or 0123.4567.89ab notation"
+	cases := []fieldValidatorTestCase{
+		{
+			Name:      `Validate(colon)`,
+			Validator: &schema.MACAddress{},
+			Input:     "01:23:45:67:89:AB",
+			Expect:    "01:23:45:67:89:ab",
+		},
+		{
+			Name:      `Validate(dash)`,
+			Validator: &schema.MACAddress{},
+			Input:     "01-23-45-67-89-ab",
+			Expect:    "01:23:45:67:89:ab",
+		},
+		{
+			Name:      `Validate(dotted)`,
+			Validator: &schema.MACAddress{},
+			Input:     "0123.4567.89AB",
+			Expect:    "01:23:45:67:89:ab",
+		},
+		{
+			Name:      `Validate(EUI-64)`,
+			Validator: &schema.MACAddress{},
+			Input:     "01:23:45:67:89:ab:cd:ef",
+			Expect:    "01:23:45:67:89:ab:cd:ef",
+		},
+		{
+			Name:      `Validate(InfiniBand)`,
+			Validator: &schema.MACAddress{},
+			Input:     "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01",
+			Error:     invalid,
+		},
+		{
+			Name:      `Validate(invalid)`,
+			Validator: &schema.MACAddress{},
+			Input:     "01:23:45:67:89",
+			Error:     invalid,
+		},
+		{
+			Name:      `Validate(1)`,
+			Validator: &schema.MACAddress{},
+			Input:     1,
+			Error:     "invalid type",
+		},
+		{
+			Name:      `{Bits:48}.Validate(EUI-64)`,
+			Validator: &schema.MACAddress{Bits: 48},
+			Input:     "01:23:45:67:89:ab:cd:ef",
+			Error:     "not a 48-bit MAC address",
+		},
+		{
+			Name:      `{Bits:64}.Validate(EUI-64)`,
+			Validator: &schema.MACAddress{Bits: 64},
+			Input:     "0123.4567.89ab.cdef",
+			Expect:    "01:23:45:67:89:ab:cd:ef",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
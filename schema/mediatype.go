@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// MediaType validates MIME media types such as "image/png" or
+// "text/plain; charset=utf-8". Valid values are normalized to their lowercase
+// form with parameters sorted by name.
+type MediaType struct {
+	// AllowParams accepts media types with parameters (i.e.: charset).
+	AllowParams bool
+	// Allowed restricts the accepted media types. Entries are either full
+	// types (i.e.: "application/pdf") or type families (i.e.: "image/*").
+	Allowed []string
+
+	allowed []string
+}
+
+// Compile implements the Compiler interface.
+func (v *MediaType) Compile(rc ReferenceChecker) error {
+	v.allowed = make([]string, 0, len(v.Allowed))
+	for _, a := range v.Allowed {
+		mt, params, err := parseMediaType(a)
+		if err != nil || len(params) > 0 || mt == "*/*" {
+			return fmt.Errorf("invalid allowed media type: %s", a)
+		}
+		v.allowed = append(v.allowed, mt)
+	}
+	return nil
+}
+
+// Validate validates and normalizes media type values.
+func (v MediaType) Validate(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("not a string")
+	}
+	mt, params, err := parseMediaType(s)
+	if err != nil || strings.HasSuffix(mt, "/*") {
+		return nil, errors.New("invalid media type")
+	}
+	if len(params) > 0 && !v.AllowParams {
+		return nil, errors.New("parameters not allowed")
+	}
+	if len(v.allowed) > 0 && !v.isAllowed(mt) {
+		return nil, fmt.Errorf("media type %s not allowed", mt)
+	}
+	return mime.FormatMediaType(mt, params), nil
+}
+
+func (v MediaType) isAllowed(mt string) bool {
+	for _, a := range v.allowed {
+		if a == mt || (strings.HasSuffix(a, "/*") && strings.HasPrefix(mt, a[:len(a)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMediaType parses s with mime.ParseMediaType and checks the result is in
+// the type/subtype form.
+func parseMediaType(s string) (string, map[string]string, error) {
+	mt, params, err := mime.ParseMediaType(s)
+	if err != nil {
+		return "", nil, err
+	}
+	if i := strings.IndexByte(mt, '/'); i <= 0 || i == len(mt)-1 {
+		return "", nil, errors.New("missing subtype")
+	}
+	return mt, params, nil
+}
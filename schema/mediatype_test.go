@@ -0,0 +1,94 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestMediaTypeCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{
+			Name:     `{Allowed:["image/*","application/pdf"]}`,
+			Compiler: &schema.MediaType{Allowed: []string{"image/*", "application/pdf"}},
+		},
+		{
+			Name:     `{Allowed:["image"]}`,
+			Compiler: &schema.MediaType{Allowed: []string{"image"}},
+			Error:    "invalid allowed media type: image",
+		},
+		{
+			Name:     `{Allowed:["text/plain; charset=utf-8"]}`,
+			Compiler: &schema.MediaType{Allowed: []string{"text/plain; charset=utf-8"}},
+			Error:    "invalid allowed media type: text/plain; charset=utf-8",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestMediaTypeValidate(t *testing.T) {
+	cases := []fieldValidatorTestCase{
+		{Name: `Validate("image/png")`, Validator: &schema.MediaType{}, Input: "image/png", Expect: "image/png"},
+		{Name: `Validate("Image/PNG")`, Validator: &schema.MediaType{}, Input: "Image/PNG", Expect: "image/png"},
+		{Name: `Validate("text/html;")`, Validator: &schema.MediaType{}, Input: "text/html;", Expect: "text/html"},
+		{Name: `Validate(1)`, Validator: &schema.MediaType{}, Input: 1, Error: "not a string"},
+		{Name: `Validate("")`, Validator: &schema.MediaType{}, Input: "", Error: "invalid media type"},
+		{Name: `Validate("text")`, Validator: &schema.MediaType{}, Input: "text", Error: "invalid media type"},
+		{Name: `Validate("image/")`, Validator: &schema.MediaType{}, Input: "image/", Error: "invalid media type"},
+		{Name: `Validate("a/b/c")`, Validator: &schema.MediaType{}, Input: "a/b/c", Error: "invalid media type"},
+		{Name: `Validate("image/*")`, Validator: &schema.MediaType{}, Input: "image/*", Error: "invalid media type"},
+		{
+			Name:      `Validate("text/plain; charset=utf-8")`,
+			Validator: &schema.MediaType{},
+			Input:     "text/plain; charset=utf-8",
+			Error:     "parameters not allowed",
+		},
+		{
+			Name:      `{AllowParams:true}.Validate("Text/HTML; Charset=UTF-8;  level=1")`,
+			Validator: &schema.MediaType{AllowParams: true},
+			Input:     "Text/HTML; Charset=UTF-8;  level=1",
+			Expect:    "text/html; charset=UTF-8; level=1",
+		},
+		{
+			Name:      `{AllowParams:true}.Validate("text/plain; name=\"a b\"")`,
+			Validator: &schema.MediaType{AllowParams: true},
+			Input:     `text/plain; name="a b"`,
+			Expect:    `text/plain; name="a b"`,
+		},
+		{
+			Name:      `{AllowParams:true}.Validate("text/html; charset")`,
+			Validator: &schema.MediaType{AllowParams: true},
+			Input:     "text/html; charset",
+			Error:     "invalid media type",
+		},
+		{
+			Name:      `{Allowed:["image/*","application/pdf"]}.Validate("image/jpeg")`,
+			Validator: &schema.MediaType{Allowed: []string{"image/*", "application/pdf"}},
+			Input:     "image/jpeg",
+			Expect:    "image/jpeg",
+		},
+		{
+			Name:      `{Allowed:["image/*","application/pdf"]}.Validate("Application/PDF")`,
+			Validator: &schema.MediaType{Allowed: []string{"image/*", "application/pdf"}},
+			Input:     "Application/PDF",
+			Expect:    "application/pdf",
+		},
+		{
+			Name:      `{Allowed:["image/*","application/pdf"]}.Validate("application/json")`,
+			Validator: &schema.MediaType{Allowed: []string{"image/*", "application/pdf"}},
+			Input:     "application/json",
+			Error:     "media type application/json not allowed",
+		},
+		{
+			Name:      `{Allowed:["image/*"]}.Validate("imagex/png")`,
+			Validator: &schema.MediaType{Allowed: []string{"image/*"}},
+			Input:     "imagex/png",
+			Error:     "media type imagex/png not allowed",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
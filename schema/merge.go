@@ -0,0 +1,154 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Merge returns a new schema containing the union of the fields of s and
+// other. Fields defined in both schemas must be deeply equal, hook and
+// validator functions being compared by identity, or an error is returned.
+//
+// The Description, MinLen, MaxLen, MaxDepth, ParallelValidation,
+// UnknownFields, PreValidate and PostValidate settings of s win unless unset, and the Conditions of both
+// schemas are kept. Fields and sub-schemas are copied so later changes to s or
+// other do not affect the returned schema, while validators are shared.
+func (s Schema) Merge(other Schema) (Schema, error) {
+	m := s.Clone()
+	if m.Fields == nil {
+		m.Fields = make(Fields, len(other.Fields))
+	}
+	for name, def := range other.Fields {
+		if cur, found := m.Fields[name]; found {
+			if !deepEqual(reflect.ValueOf(cur), reflect.ValueOf(def), map[[2]uintptr]bool{}) {
+				return Schema{}, fmt.Errorf("conflicting definitions for field %s", name)
+			}
+			continue
+		}
+		m.Fields[name] = def.Clone()
+	}
+	if m.Description == "" {
+		m.Description = other.Description
+	}
+	if m.MinLen == 0 {
+		m.MinLen = other.MinLen
+	}
+	if m.MaxLen == 0 {
+		m.MaxLen = other.MaxLen
+	}
+	if m.MaxDepth == 0 {
+		m.MaxDepth = other.MaxDepth
+	}
+	m.Conditions = append(m.Conditions, other.Conditions...)
+	if m.PreValidate == nil {
+		m.PreValidate = other.PreValidate
+	}
+	if m.PostValidate == nil {
+		m.PostValidate = other.PostValidate
+	}
+	if m.UnknownFields == InheritUnknownFields {
+		m.UnknownFields = other.UnknownFields
+	}
+	if !m.ParallelValidation {
+		m.ParallelValidation = other.ParallelValidation
+	}
+	return m, nil
+}
+
+// MergeSchemas merges all the given schemas in order using Schema.Merge.
+func MergeSchemas(schemas ...Schema) (Schema, error) {
+	var m Schema
+	for i, s := range schemas {
+		if i == 0 {
+			m = s.Clone()
+			continue
+		}
+		var err error
+		if m, err = m.Merge(s); err != nil {
+			return Schema{}, err
+		}
+	}
+	return m, nil
+}
+
+// deepEqual is like reflect.DeepEqual except that functions are equal when
+// they point to the same code, so fields sharing hooks compare as equal.
+func deepEqual(a, b reflect.Value, visited map[[2]uintptr]bool) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Func:
+		return a.Pointer() == b.Pointer()
+	case reflect.Ptr:
+		if a.Pointer() == b.Pointer() {
+			return true
+		}
+		if a.IsNil() || b.IsNil() {
+			return false
+		}
+		key := [2]uintptr{a.Pointer(), b.Pointer()}
+		if visited[key] {
+			return true
+		}
+		visited[key] = true
+		return deepEqual(a.Elem(), b.Elem(), visited)
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return deepEqual(a.Elem(), b.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !deepEqual(a.Field(i), b.Field(i), visited) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && a.IsNil() != b.IsNil() {
+			return false
+		}
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !deepEqual(a.Index(i), b.Index(i), visited) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		for _, k := range a.MapKeys() {
+			bv := b.MapIndex(k)
+			if !bv.IsValid() || !deepEqual(a.MapIndex(k), bv, visited) {
+				return false
+			}
+		}
+		return true
+	}
+	// Basic types; unexported fields can't be read with Interface so compare
+	// them through their kind specific accessors.
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	}
+	// Channels and unsafe pointers.
+	return a.Pointer() == b.Pointer()
+}
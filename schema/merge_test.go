@@ -0,0 +1,163 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+	"github.com/rs/rest-layer/schema/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaMerge(t *testing.T) {
+	common := schema.Schema{
+		Description: "common",
+		Fields: schema.Fields{
+			"id":      schema.IDField,
+			"created": schema.CreatedField,
+			"updated": schema.UpdatedField,
+		},
+	}
+	user := schema.Schema{
+		Description: "user",
+		MaxLen:      10,
+		Fields: schema.Fields{
+			"id":   schema.IDField,
+			"name": {Required: true, Validator: &schema.String{MaxLen: 150}},
+		},
+	}
+
+	m, err := common.Merge(user)
+	assert.NoError(t, err)
+	assert.Equal(t, "common", m.Description)
+	assert.Equal(t, 10, m.MaxLen)
+	assert.Len(t, m.Fields, 4)
+	for _, name := range []string{"id", "created", "updated", "name"} {
+		assert.Contains(t, m.Fields, name)
+	}
+
+	// The empty description is taken from the other schema.
+	m, err = schema.Schema{Fields: common.Fields}.Merge(user)
+	assert.NoError(t, err)
+	assert.Equal(t, "user", m.Description)
+
+	// Inputs are not modified and later changes to them don't leak.
+	assert.Len(t, common.Fields, 3)
+	assert.Len(t, user.Fields, 2)
+	user.Fields["email"] = schema.Field{}
+	assert.NotContains(t, m.Fields, "email")
+}
+
+func TestSchemaMergeConflict(t *testing.T) {
+	a := schema.Schema{Fields: schema.Fields{
+		"name": {Required: true, Validator: &schema.String{MaxLen: 150}},
+	}}
+
+	// Identical definitions are merged.
+	_, err := a.Merge(schema.Schema{Fields: schema.Fields{
+		"name": {Required: true, Validator: &schema.String{MaxLen: 150}},
+	}})
+	assert.NoError(t, err)
+
+	// Different definitions conflict.
+	_, err = a.Merge(schema.Schema{Fields: schema.Fields{
+		"name": {Required: true, Validator: &schema.String{MaxLen: 100}},
+	}})
+	assert.EqualError(t, err, "conflicting definitions for field name")
+	_, err = a.Merge(schema.Schema{Fields: schema.Fields{
+		"name": {Validator: &schema.String{MaxLen: 150}},
+	}})
+	assert.EqualError(t, err, "conflicting definitions for field name")
+
+	// Hooks are compared by identity.
+	_, err = schema.Schema{Fields: schema.Fields{"updated": schema.UpdatedField}}.Merge(
+		schema.Schema{Fields: schema.Fields{"updated": schema.CreatedField}})
+	assert.EqualError(t, err, "conflicting definitions for field updated")
+}
+
+func TestSchemaMergeSubSchemaCopy(t *testing.T) {
+	address := &schema.Schema{Fields: schema.Fields{"city": {}}}
+	m, err := schema.Schema{}.Merge(schema.Schema{Fields: schema.Fields{
+		"address": {Schema: address},
+	}})
+	assert.NoError(t, err)
+	address.Fields["zip"] = schema.Field{}
+	assert.NotContains(t, m.Fields["address"].Schema.Fields, "zip")
+}
+
+func TestMergeSchemas(t *testing.T) {
+	s, err := schema.MergeSchemas(
+		schema.Schema{Fields: schema.Fields{"name": {}}},
+		schema.Schema{Fields: schema.Fields{
+			"type": {Filterable: true, Validator: &schema.String{Allowed: []string{"basic", "pro"}}},
+		}},
+		schema.Schema{Fields: schema.Fields{
+			"discount": {
+				Dependency: query.MustParsePredicate(`{type: "pro"}`),
+				Validator:  &schema.Integer{},
+			},
+		}},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, s.Fields, 3)
+	assert.NoError(t, s.Compile(nil))
+
+	// The dependency is preserved and resolved against the merged schema.
+	doc := map[string]interface{}{"name": "John", "type": "basic", "discount": 10}
+	_, errs := s.Validate(doc, map[string]interface{}{})
+	if assert.Len(t, errs["discount"], 1) {
+		assert.Equal(t, schema.CodeDependency, errs["discount"][0].(schema.ValidationError).Code)
+	}
+	doc["type"] = "pro"
+	_, errs = s.Validate(doc, map[string]interface{}{})
+	assert.Len(t, errs, 0)
+
+	_, err = schema.MergeSchemas(
+		schema.Schema{Fields: schema.Fields{"id": schema.IDField}},
+		schema.Schema{Fields: schema.Fields{"id": {}}},
+	)
+	assert.EqualError(t, err, "conflicting definitions for field id")
+
+	s, err = schema.MergeSchemas()
+	assert.NoError(t, err)
+	assert.Len(t, s.Fields, 0)
+}
+
+func TestSchemaClone(t *testing.T) {
+	validator := &schema.String{MaxLen: 150}
+	s := schema.Schema{
+		Description: "user",
+		Fields: schema.Fields{
+			"name": {Required: true, Validator: validator},
+			"address": {
+				Excludes: []string{"name"},
+				Params: schema.Params{
+					"full": {Description: "full address"},
+				},
+				Schema: &schema.Schema{Fields: schema.Fields{
+					"city": {Validator: &schema.String{}},
+				}},
+			},
+		},
+	}
+
+	c := s.Clone()
+	assert.Equal(t, s, c)
+
+	// Changes to the clone don't affect the original.
+	c.Description = "clone"
+	c.Fields["email"] = schema.Field{}
+	addr := c.Fields["address"]
+	addr.Excludes[0] = "email"
+	addr.Params["short"] = schema.Param{}
+	addr.Schema.Fields["zip"] = schema.Field{}
+	addr.Schema.Fields["city"] = schema.Field{Required: true}
+	assert.Equal(t, "user", s.Description)
+	assert.NotContains(t, s.Fields, "email")
+	assert.Equal(t, []string{"name"}, s.Fields["address"].Excludes)
+	assert.NotContains(t, s.Fields["address"].Params, "short")
+	assert.Len(t, s.Fields["address"].Schema.Fields, 1)
+	assert.False(t, s.Fields["address"].Schema.Fields["city"].Required)
+
+	// Validators are shared.
+	assert.True(t, c.Fields["name"].Validator == validator)
+}
@@ -0,0 +1,159 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Money validates monetary amounts given as an {"amount": amount, "currency":
+// code} object, where code is an ISO 4217 currency code listed in
+// CurrencyMinorUnits.
+//
+// The amount is either a number of minor units of the currency (i.e.: 1250
+// for 12.50 EUR) or a decimal string in major units (i.e.: "12.50"). Decimal
+// strings with more decimal places than the currency supports are rounded
+// half away from zero. The amount is stored as an int64 number of minor
+// units so no precision is lost on computations or storage.
+//
+// Money implements FieldSerializer to present the amount as a decimal string
+// in major units.
+type Money struct {
+	// Currencies lists the accepted currency codes (default any code listed
+	// in CurrencyMinorUnits).
+	Currencies []string
+	// AllowNegative accepts amounts lower than zero.
+	AllowNegative bool
+}
+
+// Compile implements the Compiler interface.
+func (v *Money) Compile(rc ReferenceChecker) error {
+	seen := map[string]bool{}
+	for _, c := range v.Currencies {
+		if _, found := CurrencyMinorUnits[c]; !found {
+			return fmt.Errorf("invalid ISO 4217 currency code: %s", c)
+		}
+		if seen[c] {
+			return fmt.Errorf("duplicate currency: %s", c)
+		}
+		seen[c] = true
+	}
+	return nil
+}
+
+// Validate validates and normalizes monetary amounts.
+func (v Money) Validate(value interface{}) (interface{}, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("not an object")
+	}
+	if err := checkObjectKeys(obj, "amount", "currency"); err != nil {
+		return nil, err
+	}
+	currency, err := v.currency(obj["currency"])
+	if err != nil {
+		return nil, err
+	}
+	amount, err := parseMoneyAmount(obj["amount"], CurrencyMinorUnits[currency])
+	if err != nil {
+		return nil, err
+	}
+	if amount < 0 && !v.AllowNegative {
+		return nil, errors.New("amount must not be negative")
+	}
+	return map[string]interface{}{"amount": amount, "currency": currency}, nil
+}
+
+// Serialize implements FieldSerializer.
+func (v Money) Serialize(value interface{}) (interface{}, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("not an object")
+	}
+	currency, _ := obj["currency"].(string)
+	units, found := CurrencyMinorUnits[currency]
+	if !found {
+		return nil, fmt.Errorf("invalid ISO 4217 currency code: %s", currency)
+	}
+	amount, err := parseMoneyMinorUnits(obj["amount"])
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"amount":   formatMoneyAmount(amount, units),
+		"currency": currency,
+	}, nil
+}
+
+// currency validates the currency code of an amount.
+func (v Money) currency(value interface{}) (string, error) {
+	c, ok := value.(string)
+	if !ok {
+		return "", errors.New("currency is not a string")
+	}
+	c = strings.ToUpper(c)
+	if _, found := CurrencyMinorUnits[c]; !found {
+		return "", errors.New("currency is not a valid ISO 4217 code")
+	}
+	if len(v.Currencies) > 0 && !isIn(v.Currencies, c) {
+		return "", fmt.Errorf("currency is not one of [%s]", strings.Join(v.Currencies, ", "))
+	}
+	return c, nil
+}
+
+// parseMoneyAmount returns the number of minor units of an amount given as a
+// number of minor units or as a decimal string in major units, units being
+// the number of minor units of the currency.
+func parseMoneyAmount(value interface{}, units int) (int64, error) {
+	s, ok := value.(string)
+	if !ok {
+		return parseMoneyMinorUnits(value)
+	}
+	if _, _, ok := splitDecimal(s); !ok {
+		return 0, errors.New("amount is not a decimal")
+	}
+	r, _ := new(big.Rat).SetString(s)
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(units)), nil)))
+	// Round half away from zero.
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if m.Abs(m).Lsh(m, 1).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(r.Sign())))
+	}
+	if !q.IsInt64() {
+		return 0, errors.New("amount is out of range")
+	}
+	return q.Int64(), nil
+}
+
+// parseMoneyMinorUnits returns the number of minor units of an amount given
+// as an integer number.
+func parseMoneyMinorUnits(value interface{}) (int64, error) {
+	switch t := value.(type) {
+	case int:
+		return int64(t), nil
+	case int64:
+		return t, nil
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+	case float64:
+		if t == math.Trunc(t) && math.Abs(t) < 1<<53 {
+			return int64(t), nil
+		}
+	}
+	return 0, errors.New("amount is not an integer number of minor units or a decimal string")
+}
+
+// formatMoneyAmount formats a number of minor units as a decimal string in
+// major units.
+func formatMoneyAmount(amount int64, units int) string {
+	if units == 0 {
+		return fmt.Sprintf("%d", amount)
+	}
+	r := new(big.Rat).SetFrac(big.NewInt(amount), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(units)), nil))
+	return r.FloatString(units)
+}
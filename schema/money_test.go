@@ -0,0 +1,201 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestMoneyCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{
+			Name:     "{Currencies:[EUR,JPY]}",
+			Compiler: &schema.Money{Currencies: []string{"EUR", "JPY"}},
+		},
+		{
+			Name:     "{Currencies:[EUR,XYZ]}",
+			Compiler: &schema.Money{Currencies: []string{"EUR", "XYZ"}},
+			Error:    "invalid ISO 4217 currency code: XYZ",
+		},
+		{
+			Name:     "{Currencies:[eur]}",
+			Compiler: &schema.Money{Currencies: []string{"eur"}},
+			Error:    "invalid ISO 4217 currency code: eur",
+		},
+		{
+			Name:     "{Currencies:[EUR,EUR]}",
+			Compiler: &schema.Money{Currencies: []string{"EUR", "EUR"}},
+			Error:    "duplicate currency: EUR",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestMoneyValidate(t *testing.T) {
+	money := func(amount interface{}, currency string) map[string]interface{} {
+		return map[string]interface{}{"amount": amount, "currency": currency}
+	}
+	cases := []fieldValidatorTestCase{
+		{
+			Name:      `Validate(int)`,
+			Validator: &schema.Money{},
+			Input:     money(1250, "EUR"),
+			Expect:    money(int64(1250), "EUR"),
+		},
+		{
+			Name:      `Validate(float64)`,
+			Validator: &schema.Money{},
+			Input:     money(1250.0, "EUR"),
+			Expect:    money(int64(1250), "EUR"),
+		},
+		{
+			Name:      `Validate(json.Number)`,
+			Validator: &schema.Money{},
+			Input:     money(json.Number("1250"), "EUR"),
+			Expect:    money(int64(1250), "EUR"),
+		},
+		{
+			Name:      `Validate("12.5")`,
+			Validator: &schema.Money{},
+			Input:     money("12.5", "eur"),
+			Expect:    money(int64(1250), "EUR"),
+		},
+		{
+			Name:      `Validate("12.345")`,
+			Validator: &schema.Money{},
+			Input:     money("12.345", "EUR"),
+			Expect:    money(int64(1235), "EUR"),
+		},
+		{
+			Name:      `Validate("12.344")`,
+			Validator: &schema.Money{},
+			Input:     money("12.344", "EUR"),
+			Expect:    money(int64(1234), "EUR"),
+		},
+		{
+			Name:      `{AllowNegative:true}.Validate("-12.345")`,
+			Validator: &schema.Money{AllowNegative: true},
+			Input:     money("-12.345", "EUR"),
+			Expect:    money(int64(-1235), "EUR"),
+		},
+		{
+			Name:      `Validate("1250.5" JPY)`,
+			Validator: &schema.Money{},
+			Input:     money("1250.5", "JPY"),
+			Expect:    money(int64(1251), "JPY"),
+		},
+		{
+			Name:      `Validate("1.2345" KWD)`,
+			Validator: &schema.Money{},
+			Input:     money("1.2345", "KWD"),
+			Expect:    money(int64(1235), "KWD"),
+		},
+		{
+			Name:      `Validate(-1)`,
+			Validator: &schema.Money{},
+			Input:     money(-1, "EUR"),
+			Error:     "amount must not be negative",
+		},
+		{
+			Name:      `Validate("-0.001")`,
+			Validator: &schema.Money{},
+			Input:     money("-0.001", "EUR"),
+			Expect:    money(int64(0), "EUR"),
+		},
+		{
+			Name:      `Validate(12.5)`,
+			Validator: &schema.Money{},
+			Input:     money(12.5, "EUR"),
+			Error:     "amount is not an integer number of minor units or a decimal string",
+		},
+		{
+			Name:      `Validate("1e3")`,
+			Validator: &schema.Money{},
+			Input:     money("1e3", "EUR"),
+			Error:     "amount is not a decimal",
+		},
+		{
+			Name:      `Validate("99999999999999999999")`,
+			Validator: &schema.Money{},
+			Input:     money("99999999999999999999", "EUR"),
+			Error:     "amount is out of range",
+		},
+		{
+			Name:      `Validate(XYZ)`,
+			Validator: &schema.Money{},
+			Input:     money(1, "XYZ"),
+			Error:     "currency is not a valid ISO 4217 code",
+		},
+		{
+			Name:      `{Currencies:[EUR,USD]}.Validate(GBP)`,
+			Validator: &schema.Money{Currencies: []string{"EUR", "USD"}},
+			Input:     money(1, "GBP"),
+			Error:     "currency is not one of [EUR, USD]",
+		},
+		{
+			Name:      `Validate(missing currency)`,
+			Validator: &schema.Money{},
+			Input:     map[string]interface{}{"amount": 1},
+			Error:     "currency is not a string",
+		},
+		{
+			Name:      `Validate(extra keys)`,
+			Validator: &schema.Money{},
+			Input:     map[string]interface{}{"amount": 1, "currency": "EUR", "rate": 1},
+			Error:     "invalid key: rate",
+		},
+		{
+			Name:      `Validate(string)`,
+			Validator: &schema.Money{},
+			Input:     "12.50 EUR",
+			Error:     "not an object",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestMoneySerialize(t *testing.T) {
+	money := func(amount interface{}, currency string) map[string]interface{} {
+		return map[string]interface{}{"amount": amount, "currency": currency}
+	}
+	cases := []fieldSerializerTestCase{
+		{
+			Name:       `Serialize(EUR)`,
+			Serializer: &schema.Money{},
+			Input:      money(int64(1250), "EUR"),
+			Expect:     money("12.50", "EUR"),
+		},
+		{
+			Name:       `Serialize(negative)`,
+			Serializer: &schema.Money{},
+			Input:      money(int64(-5), "EUR"),
+			Expect:     money("-0.05", "EUR"),
+		},
+		{
+			Name:       `Serialize(JPY)`,
+			Serializer: &schema.Money{},
+			Input:      money(1250.0, "JPY"),
+			Expect:     money("1250", "JPY"),
+		},
+		{
+			Name:       `Serialize(KWD)`,
+			Serializer: &schema.Money{},
+			Input:      money(1250, "KWD"),
+			Expect:     money("1.250", "KWD"),
+		},
+		{
+			Name:       `Serialize(XYZ)`,
+			Serializer: &schema.Money{},
+			Input:      money(1, "XYZ"),
+			Error:      "invalid ISO 4217 currency code: XYZ",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
@@ -0,0 +1,126 @@
+package schema
+
+import "encoding/json"
+
+// decodeNumbers returns value, to be validated by f, with the json.Number
+// values it holds (as decoded by a json.Decoder with UseNumber) replaced by
+// float64 values, like json.Unmarshal would have decoded them. The numbers of
+// the fields whose validator implements FieldNumberValidator and accepts them
+// are kept as is. Maps and slices are copied when changed, and changed is true
+// if any value has been replaced.
+func decodeNumbers(value interface{}, f Field) (res interface{}, changed bool) {
+	if f.Schema != nil {
+		if m, ok := value.(map[string]interface{}); ok {
+			return f.Schema.decodeNumbers(m)
+		}
+		return decodeAnyNumbers(value)
+	}
+	switch v := f.Validator.(type) {
+	case *Object:
+		if m, ok := value.(map[string]interface{}); ok && v.Schema != nil {
+			return v.Schema.decodeNumbers(m)
+		}
+	case *Discriminated:
+		if m, ok := value.(map[string]interface{}); ok {
+			key, _ := m[v.PropertyName].(string)
+			if s := v.Mapping[key]; s != nil {
+				return s.decodeNumbers(m)
+			}
+		}
+	case *Array:
+		if items, ok := value.([]interface{}); ok {
+			return decodeSliceNumbers(items, v.Values)
+		}
+	case *Dict:
+		if m, ok := value.(map[string]interface{}); ok {
+			return decodeMapNumbers(m, func(string) Field { return v.Values })
+		}
+	}
+	if acceptsJSONNumber(f.Validator) {
+		return value, false
+	}
+	return decodeAnyNumbers(value)
+}
+
+// decodeNumbers applies decodeNumbers to the fields of payload.
+func (s Schema) decodeNumbers(payload map[string]interface{}) (map[string]interface{}, bool) {
+	return decodeMapNumbers(payload, func(name string) Field { return s.Fields[name] })
+}
+
+// acceptsJSONNumber returns true if the validator of f, or of a field of its
+// sub-schema up to depth nesting levels, accepts json.Number values.
+func (f Field) acceptsJSONNumber(depth int) bool {
+	if f.Schema != nil {
+		return f.Schema.acceptsJSONNumber(depth - 1)
+	}
+	return acceptsJSONNumber(f.Validator)
+}
+
+// acceptsJSONNumber returns true if a field of s, up to depth nesting levels,
+// accepts json.Number values.
+func (s Schema) acceptsJSONNumber(depth int) bool {
+	if depth <= 0 {
+		return false
+	}
+	for _, def := range s.Fields {
+		if def.acceptsJSONNumber(depth) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeAnyNumbers replaces all the json.Number values held by value with
+// float64 values.
+func decodeAnyNumbers(value interface{}) (interface{}, bool) {
+	switch t := value.(type) {
+	case json.Number:
+		if f, err := t.Float64(); err == nil {
+			return f, true
+		}
+	case map[string]interface{}:
+		return decodeMapNumbers(t, func(string) Field { return Field{} })
+	case []interface{}:
+		return decodeSliceNumbers(t, Field{})
+	}
+	return value, false
+}
+
+func decodeMapNumbers(m map[string]interface{}, field func(string) Field) (map[string]interface{}, bool) {
+	var res map[string]interface{}
+	for k, v := range m {
+		d, changed := decodeNumbers(v, field(k))
+		if !changed {
+			continue
+		}
+		if res == nil {
+			res = make(map[string]interface{}, len(m))
+			for k, v := range m {
+				res[k] = v
+			}
+		}
+		res[k] = d
+	}
+	if res == nil {
+		return m, false
+	}
+	return res, true
+}
+
+func decodeSliceNumbers(s []interface{}, f Field) ([]interface{}, bool) {
+	var res []interface{}
+	for i, v := range s {
+		d, changed := decodeNumbers(v, f)
+		if !changed {
+			continue
+		}
+		if res == nil {
+			res = append([]interface{}(nil), s...)
+		}
+		res[i] = d
+	}
+	if res == nil {
+		return s, false
+	}
+	return res, true
+}
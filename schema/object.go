@@ -1,10 +1,17 @@
 package schema
 
 import (
+	"context"
 	"errors"
 )
 
-// Object validates objects which are defined by Schemas.
+// Object validates objects which are defined by Schemas. It can be used where a
+// FieldValidator is expected, for instance as the Values of an Array, to
+// validate a list of sub-documents.
+//
+// The object is validated like a new document of the Schema: defaults and
+// OnInit hooks are applied by Prepare before Validate is run, so read-only
+// fields are rejected.
 type Object struct {
 	Schema *Schema
 }
@@ -14,19 +21,31 @@ func (v *Object) Compile(rc ReferenceChecker) error {
 	if v.Schema == nil {
 		return errors.New("no schema defined")
 	}
-	if err := compileDependencies(*v.Schema, v.Schema); err != nil {
-		return err
-	}
 	return v.Schema.Compile(rc)
 }
 
+// Describe implements the Describer interface.
+func (v Object) Describe() map[string]interface{} {
+	m := map[string]interface{}{"type": "object"}
+	if v.Schema != nil {
+		m["schema"] = v.Schema.Describe()
+	}
+	return m
+}
+
 // Validate implements FieldValidator interface.
 func (v Object) Validate(value interface{}) (interface{}, error) {
+	return v.ValidateCtx(context.Background(), value)
+}
+
+// ValidateCtx implements FieldValidatorCtx interface.
+func (v Object) ValidateCtx(ctx context.Context, value interface{}) (interface{}, error) {
 	obj, ok := value.(map[string]interface{})
 	if !ok {
 		return nil, errors.New("not an object")
 	}
-	dest, errs := v.Schema.Validate(nil, obj)
+	changes, base := v.Schema.Prepare(ctx, obj, nil, false)
+	dest, errs := v.Schema.ValidateCtx(ctx, changes, base)
 	if len(errs) > 0 {
 		// Currently, tests expect FieldValidators to always return a nil value
 		// on validation errors.
@@ -35,6 +54,11 @@ func (v Object) Validate(value interface{}) (interface{}, error) {
 	return dest, nil
 }
 
+// AcceptsJSONNumber implements the FieldNumberValidator interface.
+func (v Object) AcceptsJSONNumber() bool {
+	return v.Schema != nil && v.Schema.acceptsJSONNumber(v.Schema.maxDepth())
+}
+
 // GetField implements the FieldGetter interface.
 func (v Object) GetField(name string) *Field {
 	return v.Schema.GetField(name)
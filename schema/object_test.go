@@ -1,6 +1,8 @@
 package schema_test
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/rs/rest-layer/schema"
@@ -75,6 +77,36 @@ func TestObjectValidate(t *testing.T) {
 			Input: map[string]interface{}{"test": 1, "count": "hello"},
 			Error: "count is [not an integer], test is [not a string]",
 		},
+		{
+			Name: `{Schema:{"foo":String,"bar":Default}}.Validate(valid)`,
+			Validator: &schema.Object{Schema: &schema.Schema{Fields: schema.Fields{
+				"foo": {Validator: &schema.String{}},
+				"bar": {Default: "baz", Validator: &schema.String{}},
+			}}},
+			Input:  map[string]interface{}{"foo": "hello"},
+			Expect: map[string]interface{}{"foo": "hello", "bar": "baz"},
+		},
+		{
+			Name: `{Schema:{"foo":OnInit}}.Validate(valid)`,
+			Validator: &schema.Object{Schema: &schema.Schema{Fields: schema.Fields{
+				"foo": {
+					OnInit: func(ctx context.Context, value interface{}) interface{} {
+						return strings.ToUpper(value.(string))
+					},
+					Validator: &schema.String{},
+				},
+			}}},
+			Input:  map[string]interface{}{"foo": "hello"},
+			Expect: map[string]interface{}{"foo": "HELLO"},
+		},
+		{
+			Name: `{Schema:{"foo":ReadOnly}}.Validate(invalid)`,
+			Validator: &schema.Object{Schema: &schema.Schema{Fields: schema.Fields{
+				"foo": {ReadOnly: true},
+			}}},
+			Input: map[string]interface{}{"foo": "hello"},
+			Error: "foo is [read-only]",
+		},
 		{
 			Name: `{Schema:{"foo":Reference{Path:valid}}}.Validate(valid)`,
 			Validator: &schema.Object{Schema: &schema.Schema{Fields: schema.Fields{
@@ -111,3 +143,19 @@ func TestObjectValidatorErrorType(t *testing.T) {
 	_, err := v.Validate(obj)
 	assert.IsType(t, schema.ErrorMap{}, err, "Unexpected error type")
 }
+
+func TestObjectDescribe(t *testing.T) {
+	v := schema.Object{Schema: &schema.Schema{
+		Fields: schema.Fields{
+			"name": {Required: true, Validator: &schema.String{}},
+		},
+	}}
+	assert.Equal(t, map[string]interface{}{
+		"type": "object",
+		"schema": map[string]interface{}{
+			"fields": map[string]interface{}{
+				"name": map[string]interface{}{"type": "string", "required": true},
+			},
+		},
+	}, v.Describe())
+}
@@ -0,0 +1,88 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OneOf validates if exactly one of the sub field validators validates. If
+// any of the sub field validators implements the FieldSerializer interface,
+// the *first* implementation which does not error will be used.
+type OneOf []FieldValidator
+
+// Compile implements the Compiler interface.
+func (v OneOf) Compile(rc ReferenceChecker) error {
+	return AnyOf(v).Compile(rc)
+}
+
+// ValidateQuery implements schema.FieldQueryValidator interface.
+func (v OneOf) ValidateQuery(value interface{}) (interface{}, error) {
+	return v.validate(value, true)
+}
+
+// Validate ensures that exactly one sub-validator validates, and returns the
+// result of this validator. All the sub-validators are run: when more than one
+// validates, the error lists their one based positions (i.e.: "matches more
+// than one validator: #1, #3").
+func (v OneOf) Validate(value interface{}) (interface{}, error) {
+	return v.validate(value, false)
+}
+
+// AcceptsJSONNumber implements the FieldNumberValidator interface.
+func (v OneOf) AcceptsJSONNumber() bool {
+	for _, validator := range v {
+		if acceptsJSONNumber(validator) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v OneOf) validate(value interface{}, query bool) (interface{}, error) {
+	var errs ErrorSlice
+	var result interface{}
+	var matches []string
+
+	for i, validator := range v {
+		var err error
+		var val interface{}
+		if validatorQuery, ok := validator.(FieldQueryValidator); ok && query {
+			val, err = validatorQuery.ValidateQuery(value)
+		} else {
+			val, err = validator.Validate(value)
+		}
+		if err != nil {
+			errs = errs.Append(err)
+			continue
+		}
+		matches = append(matches, fmt.Sprintf("#%d", i+1))
+		result = val
+	}
+
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("matches more than one validator: %s", strings.Join(matches, ", "))
+	}
+	if len(matches) == 0 && len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
+
+// Serialize attempts to serialize the value using the first available
+// FieldSerializer which does not return an error. If no appropriate serializer
+// is found, the input value is returned.
+func (v OneOf) Serialize(value interface{}) (interface{}, error) {
+	return AnyOf(v).Serialize(value)
+}
+
+// LessFunc implements the FieldComparator interface, and returns the first
+// non-nil LessFunc or nil.
+func (v OneOf) LessFunc() LessFunc {
+	return AnyOf(v).LessFunc()
+}
+
+// GetField implements the FieldGetter interface. Note that it will return the
+// first matching field only.
+func (v OneOf) GetField(name string) *Field {
+	return AnyOf(v).GetField(name)
+}
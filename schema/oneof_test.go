@@ -0,0 +1,90 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestOneOfCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{
+			Name:             "{String}",
+			Compiler:         &schema.OneOf{&schema.String{}},
+			ReferenceChecker: fakeReferenceChecker{},
+		},
+		{
+			Name:             "{String{Regexp:invalid}}",
+			Compiler:         &schema.OneOf{&schema.String{Regexp: "[invalid re"}},
+			ReferenceChecker: fakeReferenceChecker{},
+			Error:            "invalid regexp: error parsing regexp: missing closing ]: `[invalid re`",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestOneOfValidate(t *testing.T) {
+	cases := []fieldValidatorTestCase{
+		{
+			Name:      "{Bool,String}.Validate(true)",
+			Validator: schema.OneOf{&schema.Bool{}, &schema.String{}},
+			Input:     true,
+			Expect:    true,
+		},
+		{
+			Name:      "{Bool,String}.Validate(42)",
+			Validator: schema.OneOf{&schema.Bool{}, &schema.String{}},
+			Input:     42,
+			Error:     "not a Boolean, not a string",
+		},
+		{
+			Name:      `{Bool,Bool}.Validate(true)`,
+			Validator: schema.OneOf{&schema.Bool{}, &schema.Bool{}},
+			Input:     true,
+			Error:     "matches more than one validator: #1, #2",
+		},
+		{
+			Name:      `{Bool,String,Bool}.Validate(true)`,
+			Validator: schema.OneOf{&schema.Bool{}, &schema.String{}, &schema.Bool{}},
+			Input:     true,
+			Error:     "matches more than one validator: #1, #3",
+		},
+		{
+			Name:      `{URL,Email}.Validate("john@example.com")`,
+			Validator: schema.OneOf{&schema.URL{}, &schema.Email{}},
+			Input:     "John@Example.com",
+			Expect:    "john@example.com",
+		},
+		{
+			Name:      `{Integer,String{MaxLen:2}}.Validate("foo")`,
+			Validator: schema.OneOf{&schema.Integer{}, &schema.String{MaxLen: 2}},
+			Input:     "foo",
+			Error:     "not an integer, is longer than 2 characters (got 3)",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestOneOfQueryValidate(t *testing.T) {
+	cases := []fieldQueryValidatorTestCase{
+		{
+			Name:      "{Bool,String}.ValidateQuery(true)",
+			Validator: schema.OneOf{&schema.Bool{}, &schema.String{}},
+			Input:     true,
+			Expect:    true,
+		},
+		{
+			Name:      `{Bool,String}.ValidateQuery(42)`,
+			Validator: schema.OneOf{&schema.Bool{}, &schema.String{}},
+			Input:     42,
+			Error:     "not a Boolean, not a string",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
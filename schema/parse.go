@@ -0,0 +1,185 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// fieldDescription is the JSON description of a field parsed by ParseSchema.
+type fieldDescription struct {
+	Type     string                      `json:"type"`
+	Required bool                        `json:"required"`
+	Min      *float64                    `json:"min"`
+	Max      *float64                    `json:"max"`
+	Pattern  string                      `json:"pattern"`
+	Enum     []interface{}               `json:"enum"`
+	Default  interface{}                 `json:"default"`
+	Fields   map[string]fieldDescription `json:"fields"`
+}
+
+// ParseSchema builds a compiled Schema from a JSON description, so simple
+// schemas can be defined in configuration files. The description is an object
+// associating each field name to an object with the following properties:
+//
+//   - type: "string", "integer", "number", "boolean" or "object" (required);
+//   - required: true if the field is required;
+//   - min, max: the boundaries of an integer or a number, or the length
+//     boundaries of a string;
+//   - pattern: a regular expression the value of a string must match;
+//   - enum: the list of the allowed values of a string, an integer or a number;
+//   - default: the default value of the field;
+//   - fields: the fields of an object, described the same way.
+//
+// For instance:
+//
+//	{
+//	    "name": {"type": "string", "required": true, "max": 150},
+//	    "age": {"type": "integer", "min": 0},
+//	    "address": {"type": "object", "fields": {"city": {"type": "string"}}}
+//	}
+func ParseSchema(data []byte) (Schema, error) {
+	var desc map[string]fieldDescription
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&desc); err != nil {
+		return Schema{}, err
+	}
+	s, err := parseFields(desc)
+	if err != nil {
+		return Schema{}, err
+	}
+	if err := s.Compile(nil); err != nil {
+		return Schema{}, err
+	}
+	return s, nil
+}
+
+// parseFields returns the schema described by desc.
+func parseFields(desc map[string]fieldDescription) (Schema, error) {
+	names := make([]string, 0, len(desc))
+	for name := range desc {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	s := Schema{Fields: make(Fields, len(desc))}
+	for _, name := range names {
+		f, err := desc[name].field()
+		if err != nil {
+			return Schema{}, newCompileError(name, err)
+		}
+		s.Fields[name] = f
+	}
+	return s, nil
+}
+
+// field returns the field described by d.
+func (d fieldDescription) field() (Field, error) {
+	f := Field{Required: d.Required}
+	if d.Type != "object" && d.Fields != nil {
+		return Field{}, fmt.Errorf("fields not supported by type %s", d.Type)
+	}
+	if d.Type != "string" && d.Pattern != "" {
+		return Field{}, fmt.Errorf("pattern not supported by type %s", d.Type)
+	}
+	switch d.Type {
+	case "string":
+		v := &String{Regexp: d.Pattern}
+		if d.Min != nil {
+			if v.MinLen = int(*d.Min); float64(v.MinLen) != *d.Min || v.MinLen < 0 {
+				return Field{}, errors.New("min is not a positive integer")
+			}
+		}
+		if d.Max != nil {
+			if v.MaxLen = int(*d.Max); float64(v.MaxLen) != *d.Max || v.MaxLen < 0 {
+				return Field{}, errors.New("max is not a positive integer")
+			}
+		}
+		for _, e := range d.Enum {
+			str, ok := e.(string)
+			if !ok {
+				return Field{}, fmt.Errorf("enum value %v is not a string", e)
+			}
+			v.Allowed = append(v.Allowed, str)
+		}
+		f.Validator = v
+	case "integer":
+		v := &Integer{Boundaries: d.boundaries()}
+		for _, e := range d.Enum {
+			n, _ := e.(json.Number)
+			i, err := n.Int64()
+			if err != nil {
+				return Field{}, fmt.Errorf("enum value %v is not an integer", e)
+			}
+			v.Allowed = append(v.Allowed, int(i))
+		}
+		f.Validator = v
+	case "number":
+		v := &Float{Boundaries: d.boundaries()}
+		for _, e := range d.Enum {
+			n, _ := e.(json.Number)
+			num, err := n.Float64()
+			if err != nil {
+				return Field{}, fmt.Errorf("enum value %v is not a number", e)
+			}
+			v.Allowed = append(v.Allowed, num)
+		}
+		f.Validator = v
+	case "boolean":
+		if d.Min != nil || d.Max != nil || d.Enum != nil {
+			return Field{}, errors.New("min, max and enum not supported by type boolean")
+		}
+		f.Validator = &Bool{}
+	case "object":
+		if d.Min != nil || d.Max != nil || d.Enum != nil {
+			return Field{}, errors.New("min, max and enum not supported by type object")
+		}
+		s, err := parseFields(d.Fields)
+		if err != nil {
+			return Field{}, err
+		}
+		f.Schema = &s
+	case "":
+		return Field{}, errors.New("no type defined")
+	default:
+		return Field{}, fmt.Errorf("unknown type: %s", d.Type)
+	}
+	if d.Default != nil {
+		if f.Validator == nil {
+			return Field{}, errors.New("default not supported by type object")
+		}
+		if c, ok := f.Validator.(Compiler); ok {
+			// Compile the validator (i.e.: the pattern of a string) first so
+			// the default is fully checked.
+			if err := c.Compile(nil); err != nil {
+				return Field{}, err
+			}
+		}
+		def, err := f.Validator.Validate(d.Default)
+		if err != nil {
+			return Field{}, fmt.Errorf("invalid default: %v", err)
+		}
+		f.Default = def
+	}
+	return f, nil
+}
+
+// boundaries returns the boundaries described by the min and max properties
+// of d, or nil if none is set.
+func (d fieldDescription) boundaries() *Boundaries {
+	if d.Min == nil && d.Max == nil {
+		return nil
+	}
+	b := &Boundaries{Min: math.NaN(), Max: math.NaN()}
+	if d.Min != nil {
+		b.Min = *d.Min
+	}
+	if d.Max != nil {
+		b.Max = *d.Max
+	}
+	return b
+}
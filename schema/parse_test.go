@@ -0,0 +1,116 @@
+package schema_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+	"github.com/rs/rest-layer/schema/encoding/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+const parseSchemaTestData = `{
+	"name": {"type": "string", "required": true, "min": 1, "max": 10, "pattern": "^[a-z]+$"},
+	"kind": {"type": "string", "enum": ["person", "company"], "default": "person"},
+	"age": {"type": "integer", "min": 0, "max": 150},
+	"score": {"type": "number", "min": 0},
+	"level": {"type": "integer", "enum": [1, 2, 3], "default": 1},
+	"active": {"type": "boolean"},
+	"address": {"type": "object", "fields": {
+		"city": {"type": "string", "required": true},
+		"zip": {"type": "string", "pattern": "^[0-9]{5}$"}
+	}}
+}`
+
+func TestParseSchemaJSONSchema(t *testing.T) {
+	s, err := schema.ParseSchema([]byte(parseSchemaTestData))
+	if !assert.NoError(t, err) {
+		return
+	}
+	b := new(bytes.Buffer)
+	assert.NoError(t, jsonschema.NewEncoder(b).Encode(&s))
+	assert.JSONEq(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "maxLength": 10, "pattern": "^[a-z]+$"},
+			"kind": {"type": "string", "enum": ["person", "company"], "default": "person"},
+			"age": {"type": "integer", "minimum": 0, "maximum": 150},
+			"score": {"type": "number", "minimum": 0},
+			"level": {"type": "integer", "enum": [1, 2, 3], "default": 1},
+			"active": {"type": "boolean"},
+			"address": {
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"city": {"type": "string"},
+					"zip": {"type": "string", "pattern": "^[0-9]{5}$"}
+				},
+				"required": ["city"]
+			}
+		},
+		"required": ["name"]
+	}`, b.String())
+}
+
+func TestParseSchemaValidate(t *testing.T) {
+	s, err := schema.ParseSchema([]byte(parseSchemaTestData))
+	if !assert.NoError(t, err) {
+		return
+	}
+	t.Run("Valid", func(t *testing.T) {
+		var payload map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(`{"name": "john", "age": 42, "score": 1.5, "address": {"city": "Paris"}}`), &payload))
+		changes, base := s.Prepare(context.Background(), payload, nil, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, map[string]interface{}{
+			"name":    "john",
+			"kind":    "person",
+			"age":     42,
+			"score":   1.5,
+			"level":   1,
+			"address": map[string]interface{}{"city": "Paris"},
+		}, doc)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		var payload map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(`{"name": "John", "age": 151, "level": 4, "kind": "robot", "address": {"zip": "abc"}}`), &payload))
+		changes, base := s.Prepare(context.Background(), payload, nil, false)
+		_, errs := s.Validate(changes, base)
+		fields := make([]string, 0, len(errs))
+		for field := range errs {
+			fields = append(fields, field)
+		}
+		assert.ElementsMatch(t, []string{"name", "age", "level", "kind", "address"}, fields)
+	})
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name, data, err string
+	}{
+		{"UnknownType", `{"foo": {"type": "date"}}`, "foo: unknown type: date"},
+		{"NoType", `{"foo": {}}`, "foo: no type defined"},
+		{"UnknownProperty", `{"foo": {"type": "string", "format": "email"}}`, `json: unknown field "format"`},
+		{"Nested", `{"foo": {"type": "object", "fields": {"bar": {"type": "uuid"}}}}`, "foo.bar: unknown type: uuid"},
+		{"Pattern", `{"foo": {"type": "integer", "pattern": "^1"}}`, "foo: pattern not supported by type integer"},
+		{"InvalidPattern", `{"foo": {"type": "string", "pattern": "("}}`, "foo: invalid regexp: error parsing regexp: missing closing ): `(`"},
+		{"StringMin", `{"foo": {"type": "string", "min": 1.5}}`, "foo: min is not a positive integer"},
+		{"IntegerEnum", `{"foo": {"type": "integer", "enum": [1.5]}}`, "foo: enum value 1.5 is not an integer"},
+		{"StringEnum", `{"foo": {"type": "string", "enum": [1]}}`, "foo: enum value 1 is not a string"},
+		{"BooleanMin", `{"foo": {"type": "boolean", "min": 1}}`, "foo: min, max and enum not supported by type boolean"},
+		{"Fields", `{"foo": {"type": "string", "fields": {}}}`, "foo: fields not supported by type string"},
+		{"InvalidDefault", `{"foo": {"type": "integer", "default": "abc"}}`, "foo: invalid default: not an integer"},
+		{"DefaultPattern", `{"foo": {"type": "string", "pattern": "^a", "default": "b"}}`, "foo: invalid default: does not match ^a"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := schema.ParseSchema([]byte(tc.data))
+			if assert.Error(t, err) {
+				assert.Equal(t, tc.err, err.Error())
+			}
+		})
+	}
+}
@@ -3,20 +3,71 @@ package schema
 import (
 	"errors"
 	"fmt"
+	"unicode"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-// Password crypts a field password using bcrypt algorithm.
+// PasswordHasher is the interface implemented by password hashing algorithms
+// used by the Password validator.
+type PasswordHasher interface {
+	// Hash returns the hash of password.
+	Hash(password []byte) ([]byte, error)
+	// Compare returns nil if hash is the hash of password.
+	Compare(hash, password []byte) error
+	// IsHash returns true if b is a hash produced by this hasher.
+	IsHash(b []byte) bool
+}
+
+// BcryptHasher is a PasswordHasher using the bcrypt algorithm.
+type BcryptHasher struct {
+	// Cost sets a custom bcrypt hashing cost (default bcrypt.DefaultCost).
+	Cost int
+}
+
+// Hash implements PasswordHasher.
+func (h BcryptHasher) Hash(password []byte) ([]byte, error) {
+	return bcrypt.GenerateFromPassword(password, h.Cost)
+}
+
+// Compare implements PasswordHasher.
+func (h BcryptHasher) Compare(hash, password []byte) error {
+	return bcrypt.CompareHashAndPassword(hash, password)
+}
+
+// IsHash implements PasswordHasher.
+func (h BcryptHasher) IsHash(b []byte) bool {
+	_, err := bcrypt.Cost(b)
+	return err == nil
+}
+
+// Password crypts a field password using bcrypt algorithm, or the algorithm
+// provided by Hasher.
+//
+// Hashes are stored as []byte. A []byte value recognized as a hash by the
+// hasher is stored as is, so an unchanged password is not hashed again on
+// update.
 type Password struct {
 	// MinLen defines the minimum password length (default 0).
 	MinLen int
 	// MaxLen defines the maximum password length (default no limit).
 	MaxLen int
-	// Cost sets a custom bcrypt hashing cost.
+	// Cost sets a custom bcrypt hashing cost. It is ignored when Hasher is
+	// set.
 	Cost int
+	// Hasher sets a custom hashing algorithm (default BcryptHasher).
+	Hasher PasswordHasher
+	// RequireUpper, RequireLower, RequireDigit and RequireSymbol enforce the
+	// presence of at least one character of the corresponding class.
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
 }
 
+// passwordMask is the value a password hash is serialized to.
+const passwordMask = "********"
+
 var (
 	// PasswordField is a common schema field for passwords. It encrypt the
 	// password using bcrypt before storage and hide the value so the hash can't
@@ -35,7 +86,7 @@ func (v Password) Validate(value interface{}) (interface{}, error) {
 	if !ok {
 		if b, ok := value.([]byte); ok {
 			// Maybe it's an already encoded version of the password.
-			if _, err := bcrypt.Cost(b); err == nil {
+			if v.hasher().IsHash(b) {
 				return b, nil
 			}
 		}
@@ -48,15 +99,69 @@ func (v Password) Validate(value interface{}) (interface{}, error) {
 	if v.MaxLen > 0 && l > v.MaxLen {
 		return nil, fmt.Errorf("is longer than %d", v.MaxLen)
 	}
-	b, err := bcrypt.GenerateFromPassword([]byte(s), v.Cost)
+	if err := v.checkComplexity(s); err != nil {
+		return nil, err
+	}
+	b, err := v.hasher().Hash([]byte(s))
 	if err != nil {
 		return nil, err
 	}
 	return b, nil
 }
 
+// Compare returns true if hash, as stored by Validate, is the hash of the
+// plaintext password.
+func (v Password) Compare(hash, plaintext string) bool {
+	return v.hasher().Compare([]byte(hash), []byte(plaintext)) == nil
+}
+
+func (v Password) hasher() PasswordHasher {
+	if v.Hasher != nil {
+		return v.Hasher
+	}
+	return BcryptHasher{Cost: v.Cost}
+}
+
+// Serialize implements FieldSerializer. The hash is never exposed and is
+// serialized as an opaque mask. Use the Hidden field flag to omit the field
+// entirely.
+func (v Password) Serialize(value interface{}) (interface{}, error) {
+	return passwordMask, nil
+}
+
+func (v Password) checkComplexity(s string) error {
+	var upper, lower, digit, symbol bool
+	for _, r := range s {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	switch {
+	case v.RequireUpper && !upper:
+		return errors.New("must contain an uppercase letter")
+	case v.RequireLower && !lower:
+		return errors.New("must contain a lowercase letter")
+	case v.RequireDigit && !digit:
+		return errors.New("must contain a digit")
+	case v.RequireSymbol && !symbol:
+		return errors.New("must contain a symbol")
+	}
+	return nil
+}
+
 // VerifyPassword compare a field of an item payload containing a hashed
 // password with a clear text password and return true if they match.
+//
+// Deprecated: VerifyPassword only supports bcrypt hashes and returns false
+// for hashes produced by a custom Password.Hasher. Use the Compare method of
+// the field's Password validator instead.
 func VerifyPassword(hash interface{}, password []byte) bool {
 	h, ok := hash.([]byte)
 	if !ok {
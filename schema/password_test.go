@@ -1,6 +1,9 @@
 package schema
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"testing"
 
 	"golang.org/x/crypto/bcrypt"
@@ -27,9 +30,105 @@ func TestPasswordValidate(t *testing.T) {
 	assert.Nil(t, v)
 }
 
+func TestPasswordValidateComplexity(t *testing.T) {
+	v, err := Password{RequireUpper: true}.Validate("secret")
+	assert.EqualError(t, err, "must contain an uppercase letter")
+	assert.Nil(t, v)
+	v, err = Password{RequireLower: true}.Validate("SECRET")
+	assert.EqualError(t, err, "must contain a lowercase letter")
+	assert.Nil(t, v)
+	v, err = Password{RequireDigit: true}.Validate("Secret")
+	assert.EqualError(t, err, "must contain a digit")
+	assert.Nil(t, v)
+	v, err = Password{RequireSymbol: true}.Validate("Secret1")
+	assert.EqualError(t, err, "must contain a symbol")
+	assert.Nil(t, v)
+	v, err = Password{RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}.Validate("Secret1!")
+	assert.NoError(t, err)
+	assert.True(t, VerifyPassword(v, []byte("Secret1!")))
+	// Already hashed passwords are not checked nor hashed again.
+	h, _ := bcrypt.GenerateFromPassword([]byte("secret"), 0)
+	v, err = Password{RequireUpper: true}.Validate(h)
+	assert.NoError(t, err)
+	assert.Equal(t, h, v)
+}
+
+func TestPasswordSerialize(t *testing.T) {
+	h, _ := bcrypt.GenerateFromPassword([]byte("secret"), 0)
+	v, err := Password{}.Serialize(h)
+	assert.NoError(t, err)
+	assert.Equal(t, "********", v)
+}
+
 func TestVerifyPassword(t *testing.T) {
 	h, _ := bcrypt.GenerateFromPassword([]byte("secret"), 0)
 	assert.True(t, VerifyPassword(h, []byte("secret")))
 	assert.False(t, VerifyPassword(h, []byte("wrong password")))
 	assert.False(t, VerifyPassword("secret", []byte("secret")))
 }
+
+func TestPasswordPrepareUnchangedHash(t *testing.T) {
+	s := Schema{Fields: Fields{"password": PasswordField}}
+	assert.NoError(t, s.Compile(nil))
+	h, _ := bcrypt.GenerateFromPassword([]byte("secret"), 0)
+	original := map[string]interface{}{"password": h}
+	changes, base := s.Prepare(context.Background(), map[string]interface{}{"password": h}, &original, false)
+	assert.Len(t, changes, 0)
+	assert.Equal(t, h, base["password"])
+}
+
+// reverseHasher is a trivial PasswordHasher used to test custom algorithms.
+type reverseHasher struct{}
+
+func (reverseHasher) Hash(password []byte) ([]byte, error) {
+	h := []byte("rev:")
+	for i := len(password) - 1; i >= 0; i-- {
+		h = append(h, password[i])
+	}
+	return h, nil
+}
+
+func (h reverseHasher) Compare(hash, password []byte) error {
+	if expected, _ := h.Hash(password); string(expected) != string(hash) {
+		return errors.New("mismatch")
+	}
+	return nil
+}
+
+func (reverseHasher) IsHash(b []byte) bool {
+	return bytes.HasPrefix(b, []byte("rev:"))
+}
+
+func TestPasswordHasher(t *testing.T) {
+	p := Password{Hasher: reverseHasher{}}
+	v, err := p.Validate("secret")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("rev:terces"), v)
+	assert.True(t, p.Compare("rev:terces", "secret"))
+	assert.False(t, p.Compare("rev:terces", "other"))
+
+	// Unchanged hashes are not hashed again.
+	v, err = p.Validate([]byte("rev:terces"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("rev:terces"), v)
+
+	// Hashes from another algorithm are rejected.
+	h, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	_, err = p.Validate(h)
+	assert.EqualError(t, err, "not a string")
+}
+
+func TestPasswordCompare(t *testing.T) {
+	p := Password{Cost: bcrypt.MinCost}
+	v, err := p.Validate("secret")
+	assert.NoError(t, err)
+	hash := string(v.([]byte))
+	assert.True(t, p.Compare(hash, "secret"))
+	assert.False(t, p.Compare(hash, "Secret"))
+	assert.False(t, p.Compare("not a hash", "secret"))
+
+	// Unchanged hashes are not hashed again.
+	v2, err := p.Validate(v)
+	assert.NoError(t, err)
+	assert.Equal(t, v, v2)
+}
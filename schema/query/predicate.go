@@ -54,11 +54,58 @@ func (e Predicate) String() string {
 	return "{" + strings.Join(s, ", ") + "}"
 }
 
-// Prepare implements Expression interface.
+// Prepare implements Expression interface. All the fields referenced by the
+// predicate which are not filterable are reported at once.
 func (e Predicate) Prepare(validator schema.Validator) error {
+	if err := checkFlag(e.Fields(), validator, "filterable", func(f *schema.Field) bool { return f.Filterable }); err != nil {
+		return err
+	}
 	return prepareExpressions(e, validator)
 }
 
+// Fields returns the names of the fields referenced by the predicate, in order
+// of appearance. The fields referenced by an $elemMatch expression are relative
+// to the array items and are not returned.
+func (e Predicate) Fields() []string {
+	return appendFields(nil, e)
+}
+
+func appendFields(fields []string, exps []Expression) []string {
+	for _, exp := range exps {
+		switch exp := exp.(type) {
+		case *And:
+			fields = appendFields(fields, *exp)
+		case *Or:
+			fields = appendFields(fields, *exp)
+		case *In:
+			fields = append(fields, exp.Field)
+		case *NotIn:
+			fields = append(fields, exp.Field)
+		case *Equal:
+			fields = append(fields, exp.Field)
+		case *NotEqual:
+			fields = append(fields, exp.Field)
+		case *Exist:
+			fields = append(fields, exp.Field)
+		case *NotExist:
+			fields = append(fields, exp.Field)
+		case *GreaterThan:
+			fields = append(fields, exp.Field)
+		case *GreaterOrEqual:
+			fields = append(fields, exp.Field)
+		case *LowerThan:
+			fields = append(fields, exp.Field)
+		case *LowerOrEqual:
+			fields = append(fields, exp.Field)
+		case *Regex:
+			fields = append(fields, exp.Field)
+		case *ElemMatch:
+			fields = append(fields, exp.Field)
+		}
+	}
+	return fields
+}
+
 // Expression is a query or query component that can be matched against a payload.
 type Expression interface {
 	Match(payload map[string]interface{}) bool
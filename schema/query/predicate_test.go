@@ -1,6 +1,7 @@
 package query
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
@@ -251,3 +252,21 @@ func TestString(t *testing.T) {
 		}
 	}
 }
+
+func TestPredicateFields(t *testing.T) {
+	tests := map[string][]string{
+		`{}`:                                    nil,
+		`{"foo": "bar", "bar.baz": {"$gt": 1}}`: {"foo", "bar.baz"},
+		`{"$or": [{"foo": {"$exists": true}}, {"bar": 1}]}`:           {"foo", "bar"},
+		`{"$and": [{"foo": {"$in": [1]}}, {"bar": {"$regex": "a"}}]}`: {"foo", "bar"},
+		`{"foo": {"$elemMatch": {"bar": 1}}}`:                         {"foo"},
+	}
+	for query, want := range tests {
+		t.Run(query, func(t *testing.T) {
+			got := MustParsePredicate(query).Fields()
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Fields() = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/rs/rest-layer/schema"
 )
@@ -15,6 +16,30 @@ func prepareExpressions(exps []Expression, validator schema.Validator) error {
 	return nil
 }
 
+// checkFlag returns an error listing, in order of appearance, the known fields
+// for which flag returns false (i.e.: the fields which are not filterable).
+// Unknown fields are left to the validation of the expressions.
+func checkFlag(fields []string, validator schema.Validator, name string, flag func(f *schema.Field) bool) error {
+	var invalid []string
+	seen := map[string]bool{}
+	for _, field := range fields {
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
+		if f := validator.GetField(field); f != nil && !flag(f) {
+			invalid = append(invalid, field)
+		}
+	}
+	switch len(invalid) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("%s: field is not %s", invalid[0], name)
+	}
+	return fmt.Errorf("%s: fields are not %s", strings.Join(invalid, ", "), name)
+}
+
 func getValidatorField(field string, validator schema.Validator) (f *schema.Field, err error) {
 	f = validator.GetField(field)
 	if f == nil {
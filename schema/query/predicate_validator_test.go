@@ -180,3 +180,41 @@ func TestPrepareErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestPrepareUnfilterable(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"foo": {Filterable: true},
+			"bar": {},
+			"baz": {},
+			"sub": {Schema: &schema.Schema{Fields: schema.Fields{
+				"indexed": {Filterable: true},
+				"other":   {},
+			}}},
+		},
+	}
+	tests := []struct {
+		query string
+		err   error
+	}{
+		{`{"foo": 1, "sub.indexed": 2}`, nil},
+		{`{"bar": 1}`, errors.New("bar: field is not filterable")},
+		{`{"sub.other": 1}`, errors.New("sub.other: field is not filterable")},
+		{`{"foo": 1, "$or": [{"bar": 1}, {"$and": [{"sub.other": 1}, {"bar": 2}]}]}`, errors.New("bar, sub.other: fields are not filterable")},
+		{`{"$and": [{"baz": 1}, {"sub.indexed": 1}], "bar": {"$exists": true}}`, errors.New("baz, bar: fields are not filterable")},
+		// Unknown fields are reported once the fields are filterable.
+		{`{"unknown": 1, "foo": 1}`, errors.New("unknown: unknown query field")},
+		{`{"unknown": 1, "bar": 1}`, errors.New("bar: field is not filterable")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParsePredicate(tt.query)
+			if err != nil {
+				t.Fatalf("Unexpected parse error: %v", err)
+			}
+			if err = q.Prepare(s); !reflect.DeepEqual(err, tt.err) {
+				t.Errorf("unexpected error:\ngot:  %v\nwant: %v", err, tt.err)
+			}
+		})
+	}
+}
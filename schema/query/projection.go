@@ -42,6 +42,30 @@ func (p Projection) Validate(fg schema.FieldGetter) error {
 	return nil
 }
 
+// OmittedFields returns the sorted top-level fields of s excluded from the
+// default projection (see schema.Field.NoDefaultProjection) which are not
+// explicitly named in the projection.
+func (p Projection) OmittedFields(s schema.Schema) []string {
+	var omitted []string
+	for name, def := range s.Fields {
+		if !def.NoDefaultProjection {
+			continue
+		}
+		named := false
+		for _, pf := range p {
+			if pf.Name == name {
+				named = true
+				break
+			}
+		}
+		if !named {
+			omitted = append(omitted, name)
+		}
+	}
+	sort.Strings(omitted)
+	return omitted
+}
+
 // String output the projection in its DSL form.
 func (p Projection) String() string {
 	ps := make([]string, 0, len(p))
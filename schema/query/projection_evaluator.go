@@ -43,13 +43,16 @@ func (p Projection) Eval(ctx context.Context, payload map[string]interface{}, rs
 	return payload, err
 }
 
-func prepareProjection(p Projection, payload map[string]interface{}) (Projection, error) {
+func prepareProjection(p Projection, payload map[string]interface{}, fg schema.FieldGetter) (Projection, error) {
 	var proj Projection
 	if len(p) == 0 {
 		// When the Projection is empty, it's like saying "all fields".
 		// This allows notations like id,user{} to embed all fields of the user
 		// sub-resource.
 		for fn := range payload {
+			if !isDefaultProjected(fg, fn) {
+				continue
+			}
 			proj = append(proj, ProjectionField{Name: fn})
 		}
 		return proj, nil
@@ -76,7 +79,7 @@ func prepareProjection(p Projection, payload map[string]interface{}) (Projection
 					exists = true
 				}
 			}
-			if !exists {
+			if !exists && isDefaultProjected(fg, fn) {
 				proj = append(proj, ProjectionField{Name: fn, Children: starChildren})
 			}
 		}
@@ -84,6 +87,50 @@ func prepareProjection(p Projection, payload map[string]interface{}) (Projection
 	return proj, nil
 }
 
+// isDefaultProjected returns false if the field name of fg must be explicitly
+// named in a projection to be selected.
+func isDefaultProjected(fg schema.FieldGetter, name string) bool {
+	def := fg.GetField(name)
+	return def == nil || !def.NoDefaultProjection
+}
+
+// omitNonDefaultFields returns payload without the fields of s, and of its
+// sub-schemas, which are not selected by default. The payload is copied only
+// if a field is removed, changed is then true.
+func omitNonDefaultFields(payload map[string]interface{}, s *schema.Schema) (res map[string]interface{}, changed bool) {
+	res = payload
+	for name, def := range s.Fields {
+		val, found := payload[name]
+		if !found {
+			continue
+		}
+		var subval map[string]interface{}
+		if def.NoDefaultProjection {
+			// Removed below.
+		} else if m, ok := val.(map[string]interface{}); ok && def.Schema != nil {
+			var subChanged bool
+			if subval, subChanged = omitNonDefaultFields(m, def.Schema); !subChanged {
+				continue
+			}
+		} else {
+			continue
+		}
+		if !changed {
+			res = make(map[string]interface{}, len(payload))
+			for k, v := range payload {
+				res[k] = v
+			}
+			changed = true
+		}
+		if subval != nil {
+			res[name] = subval
+		} else {
+			delete(res, name)
+		}
+	}
+	return res, changed
+}
+
 func evalProjectionArray(ctx context.Context, pf ProjectionField, payload []interface{}, def *schema.Field, rbr *referenceBatchResolver, rsc Resource) (*[]interface{}, error) {
 	res := make([]interface{}, 0, len(payload))
 	// Return pointer to res, because it may be populated after this function ends, by referenceBatchResolver
@@ -174,7 +221,12 @@ func evalProjection(ctx context.Context, p Projection, payload map[string]interf
 	res := map[string]interface{}{}
 	resMu := sync.Mutex{}
 	var err error
-	p, err = prepareProjection(p, payload)
+	if c, ok := fg.(schema.FieldComputer); ok {
+		if payload, err = c.Compute(ctx, payload); err != nil {
+			return nil, err
+		}
+	}
+	p, err = prepareProjection(p, payload, fg)
 	if err != nil {
 		return nil, err
 	}
@@ -187,10 +239,13 @@ func evalProjection(ctx context.Context, p Projection, payload map[string]interf
 		}
 		def := fg.GetField(pf.Name)
 		// Skip hidden fields
-		if def != nil && def.Hidden {
+		if def != nil && def.IsHidden(ctx) {
 			continue
 		}
 		if val, found := payload[pf.Name]; found {
+			if def != nil && def.OnRead != nil {
+				val = def.OnRead(ctx, val)
+			}
 			// Handle sub field selection (if field has a value)
 			if len(pf.Children) > 0 && val != nil {
 				if def != nil && def.Schema != nil {
@@ -260,6 +315,11 @@ func evalProjection(ctx context.Context, p Projection, payload map[string]interf
 					return nil, fmt.Errorf("%s: field has no children", pf.Name)
 				}
 			} else {
+				if m, ok := val.(map[string]interface{}); ok && def != nil && def.Schema != nil {
+					// Sub-documents selected as a whole don't include their
+					// fields excluded from the default projection.
+					val, _ = omitNonDefaultFields(m, def.Schema)
+				}
 				var err error
 				if res[name], err = resolveFieldHandler(ctx, pf, def, val); err != nil {
 					return nil, err
@@ -353,11 +413,9 @@ func resolveFieldHandler(ctx context.Context, pf ProjectionField, def *schema.Fi
 			return nil, fmt.Errorf("%s: %v", pf.Name, err)
 		}
 	}
-	if s, ok := def.Validator.(schema.FieldSerializer); ok {
-		val, err = s.Serialize(val)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %v", pf.Name, err)
-		}
+	val, err = schema.SerializeField(ctx, def.Validator, val)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", pf.Name, err)
 	}
 	return val, nil
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/rs/rest-layer/internal/testutil"
@@ -564,3 +565,174 @@ func TestProjectionEval(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectionEvalCompute(t *testing.T) {
+	r := resource{
+		validator: schema.Schema{Fields: schema.Fields{
+			"first": {},
+			"last":  {},
+			"full_name": {
+				Compute: func(ctx context.Context, doc map[string]interface{}) (interface{}, error) {
+					return fmt.Sprintf("%v %v", doc["first"], doc["last"]), nil
+				},
+			},
+		}},
+	}
+	cases := []struct {
+		projection string
+		want       string
+	}{
+		{``, `{"first":"John","last":"Doe","full_name":"John Doe"}`},
+		{`full_name`, `{"full_name":"John Doe"}`},
+		{`name:full_name`, `{"name":"John Doe"}`},
+		{`first`, `{"first":"John"}`},
+	}
+	for i := range cases {
+		tc := cases[i]
+		t.Run(tc.projection, func(t *testing.T) {
+			pr, err := ParseProjection(tc.projection)
+			if err != nil {
+				t.Fatalf("ParseProjection unexpected error: %v", err)
+			}
+			if err = pr.Validate(r.validator); err != nil {
+				t.Fatalf("Projection.Validate unexpected error: %v", err)
+			}
+			payload := map[string]interface{}{"first": "John", "last": "Doe"}
+			payload, err = pr.Eval(context.Background(), payload, r)
+			if err != nil {
+				t.Fatalf("Eval unexpected error: %v", err)
+			}
+			got, _ := json.Marshal(payload)
+			testutil.JSONEq(t, []byte(tc.want), []byte(got))
+		})
+	}
+}
+
+func TestProjectionEvalOnRead(t *testing.T) {
+	r := resource{
+		validator: schema.Schema{Fields: schema.Fields{
+			"phone": {
+				OnRead: func(ctx context.Context, value interface{}) interface{} {
+					return strings.Replace(value.(string), " ", "", -1)
+				},
+			},
+			"secret": {
+				Hidden: true,
+				OnRead: func(ctx context.Context, value interface{}) interface{} {
+					t.Error("OnRead called on hidden field")
+					return value
+				},
+			},
+		}},
+	}
+	pr, err := ParseProjection(``)
+	if err != nil {
+		t.Fatalf("ParseProjection unexpected error: %v", err)
+	}
+	stored := map[string]interface{}{"phone": "+1 555 010 0000", "secret": "s"}
+	payload, err := pr.Eval(context.Background(), stored, r)
+	if err != nil {
+		t.Fatalf("Eval unexpected error: %v", err)
+	}
+	got, _ := json.Marshal(payload)
+	testutil.JSONEq(t, []byte(`{"phone":"+15550100000"}`), got)
+	if stored["phone"] != "+1 555 010 0000" {
+		t.Errorf("stored value changed: %v", stored["phone"])
+	}
+}
+
+type adminKey struct{}
+
+func TestProjectionEvalVisibleIf(t *testing.T) {
+	isAdmin := func(ctx context.Context) bool {
+		return ctx.Value(adminKey{}) != nil
+	}
+	r := resource{
+		validator: schema.Schema{Fields: schema.Fields{
+			"name":   {},
+			"notes":  {Hidden: true, VisibleIf: isAdmin},
+			"secret": {Hidden: true},
+		}},
+	}
+	stored := map[string]interface{}{"name": "John", "notes": "n", "secret": "s"}
+	cases := []struct {
+		projection string
+		ctx        context.Context
+		want       string
+	}{
+		{``, context.Background(), `{"name":"John"}`},
+		{``, context.WithValue(context.Background(), adminKey{}, true), `{"name":"John","notes":"n"}`},
+		{`name,notes`, context.Background(), `{"name":"John"}`},
+		{`name,notes`, context.WithValue(context.Background(), adminKey{}, true), `{"name":"John","notes":"n"}`},
+	}
+	for _, tc := range cases {
+		pr, err := ParseProjection(tc.projection)
+		if err != nil {
+			t.Fatalf("ParseProjection unexpected error: %v", err)
+		}
+		if err = pr.Validate(r.validator); err != nil {
+			t.Fatalf("Validate unexpected error: %v", err)
+		}
+		payload, err := pr.Eval(tc.ctx, stored, r)
+		if err != nil {
+			t.Fatalf("Eval unexpected error: %v", err)
+		}
+		got, _ := json.Marshal(payload)
+		testutil.JSONEq(t, []byte(tc.want), got)
+	}
+	if pr, _ := ParseProjection(`secret`); pr.Validate(r.validator) == nil {
+		t.Error("Validate: expected error on hidden field")
+	}
+}
+
+func TestProjectionEvalNoDefaultProjection(t *testing.T) {
+	s := schema.Schema{Fields: schema.Fields{
+		"name":    {},
+		"content": {NoDefaultProjection: true},
+		"meta": {Schema: &schema.Schema{Fields: schema.Fields{
+			"size": {},
+			"raw":  {NoDefaultProjection: true},
+		}}},
+	}}
+	r := resource{validator: s}
+	stored := map[string]interface{}{
+		"name":    "doc",
+		"content": "large",
+		"meta":    map[string]interface{}{"size": 5, "raw": "large"},
+	}
+	cases := []struct {
+		projection string
+		want       string
+		omitted    []string
+	}{
+		{``, `{"name":"doc","meta":{"size":5}}`, []string{"content"}},
+		{`*`, `{"name":"doc","meta":{"size":5}}`, []string{"content"}},
+		{`name,content`, `{"name":"doc","content":"large"}`, nil},
+		{`*,content`, `{"name":"doc","content":"large","meta":{"size":5}}`, nil},
+		{`meta`, `{"meta":{"size":5}}`, []string{"content"}},
+		{`meta{*}`, `{"meta":{"size":5}}`, []string{"content"}},
+		{`meta{size,raw}`, `{"meta":{"size":5,"raw":"large"}}`, []string{"content"}},
+	}
+	for _, tc := range cases {
+		pr, err := ParseProjection(tc.projection)
+		if err != nil {
+			t.Fatalf("ParseProjection(%q) unexpected error: %v", tc.projection, err)
+		}
+		if err = pr.Validate(s); err != nil {
+			t.Fatalf("Validate(%q) unexpected error: %v", tc.projection, err)
+		}
+		payload, err := pr.Eval(context.Background(), stored, r)
+		if err != nil {
+			t.Fatalf("Eval(%q) unexpected error: %v", tc.projection, err)
+		}
+		got, _ := json.Marshal(payload)
+		testutil.JSONEq(t, []byte(tc.want), got)
+		if omitted := pr.OmittedFields(s); !reflect.DeepEqual(omitted, tc.omitted) {
+			t.Errorf("OmittedFields(%q) = %v, want %v", tc.projection, omitted, tc.omitted)
+		}
+	}
+	// The stored payload must not be changed.
+	if _, found := stored["meta"].(map[string]interface{})["raw"]; !found {
+		t.Error("Eval: stored sub-document changed")
+	}
+}
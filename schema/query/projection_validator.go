@@ -19,8 +19,8 @@ func (pf ProjectionField) Validate(fg schema.FieldGetter) error {
 	if def == nil {
 		return fmt.Errorf("%s: unknown field", pf.Name)
 	}
-	if def.Hidden {
-		// Hidden fields can't be selected
+	if def.Hidden && def.VisibleIf == nil {
+		// Hidden fields can't be selected, unless they may be revealed
 		return fmt.Errorf("%s: hidden field", pf.Name)
 	}
 	if len(pf.Children) > 0 {
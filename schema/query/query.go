@@ -48,6 +48,11 @@ type Query struct {
 	// Window defines result set windowing using an offset and a limit. When
 	// nil, the full result-set should be returned.
 	Window *Window
+
+	// Omit lists top-level fields not needed by the query response, as they
+	// are excluded from the projection (see Projection.OmittedFields). Unlike
+	// Projection, storers may use it to avoid fetching those fields.
+	Omit []string
 }
 
 // New creates a query from a projection, predicate and sort queries using
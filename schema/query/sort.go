@@ -52,17 +52,16 @@ func ParseSort(sort string) (Sort, error) {
 	return s, nil
 }
 
-// Validate validates the sort against the provided validator.
+// Validate validates the sort against the provided validator. All the fields
+// which are not sortable are reported at once.
 func (s Sort) Validate(validator schema.Validator) error {
+	fields := make([]string, 0, len(s))
 	for _, sf := range s {
 		// Make sure the field exists.
-		f := validator.GetField(sf.Name)
-		if f == nil {
+		if f := validator.GetField(sf.Name); f == nil {
 			return fmt.Errorf("%s: unknown sort field", sf.Name)
 		}
-		if !f.Sortable {
-			return fmt.Errorf("%s: field is not sortable", sf.Name)
-		}
+		fields = append(fields, sf.Name)
 	}
-	return nil
+	return checkFlag(fields, validator, "sortable", func(f *schema.Field) bool { return f.Sortable })
 }
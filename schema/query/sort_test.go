@@ -54,6 +54,7 @@ func TestSortValidate(t *testing.T) {
 	s := schema.Schema{Fields: schema.Fields{
 		"foo": {Sortable: false},
 		"bar": {Sortable: true},
+		"qux": {},
 	}}
 	tests := []struct {
 		sort string
@@ -62,6 +63,8 @@ func TestSortValidate(t *testing.T) {
 		{"foo", errors.New("foo: field is not sortable")},
 		{"bar", nil},
 		{"baz", errors.New("baz: unknown sort field")},
+		{"foo,bar,-qux", errors.New("foo, qux: fields are not sortable")},
+		{"foo,baz", errors.New("baz: unknown sort field")},
 	}
 	for i := range tests {
 		tt := tests[i]
@@ -1,11 +1,17 @@
 package schema
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
 
-// Reference validates the ID of a linked resource.
+// Reference validates the ID of a linked resource. The ID is validated, and
+// its existence checked, using the FieldValidator returned for Path by the
+// ReferenceChecker passed to Compile. The context passed to ValidateCtx is
+// forwarded to this validator when it implements FieldValidatorCtx, so the
+// lookup can be cancelled. When this validator implements FieldBatchValidator,
+// the IDs of an Array of references are checked in a single batch.
 type Reference struct {
 	Path            string
 	validator       FieldValidator
@@ -29,11 +35,47 @@ func (r *Reference) Compile(rc ReferenceChecker) error {
 
 // Validate validates and sanitizes IDs against the reference path.
 func (r Reference) Validate(value interface{}) (interface{}, error) {
+	return r.ValidateCtx(context.Background(), value)
+}
+
+// ValidateCtx implements the FieldValidatorCtx interface.
+func (r Reference) ValidateCtx(ctx context.Context, value interface{}) (interface{}, error) {
 	if r.validator == nil {
 		return nil, errors.New("not successfully compiled")
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return ValidateField(ctx, r.validator, value)
+}
 
-	return r.validator.Validate(value)
+// AcceptsJSONNumber implements the FieldNumberValidator interface, for
+// resources whose IDs are strict integers.
+func (r Reference) AcceptsJSONNumber() bool {
+	return acceptsJSONNumber(r.validator)
+}
+
+// ValidateBatch implements the FieldBatchValidator interface.
+func (r Reference) ValidateBatch(ctx context.Context, values []interface{}) ([]interface{}, []error) {
+	errs := make([]error, len(values))
+	if r.validator == nil || ctx.Err() != nil {
+		err := ctx.Err()
+		if r.validator == nil {
+			err = errors.New("not successfully compiled")
+		}
+		for i := range errs {
+			errs[i] = err
+		}
+		return make([]interface{}, len(values)), errs
+	}
+	if bv, ok := r.validator.(FieldBatchValidator); ok {
+		return bv.ValidateBatch(ctx, values)
+	}
+	res := make([]interface{}, len(values))
+	for i, value := range values {
+		res[i], errs[i] = ValidateField(ctx, r.validator, value)
+	}
+	return res, errs
 }
 
 // GetField implements the FieldGetter interface.
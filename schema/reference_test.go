@@ -1,6 +1,9 @@
 package schema_test
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/rs/rest-layer/schema"
@@ -31,3 +34,173 @@ func TestReferenceValidate(t *testing.T) {
 		cases[i].Run(t)
 	}
 }
+
+// ctxReferenceChecker is a ReferenceChecker returning a context aware
+// validator which looks up IDs using a fake resolver.
+type ctxReferenceChecker map[string]func(ctx context.Context, id interface{}) error
+
+func (rc ctxReferenceChecker) ReferenceChecker(path string) (schema.FieldValidator, schema.Validator) {
+	lookup, ok := rc[path]
+	if !ok {
+		return nil, nil
+	}
+	return schema.FieldValidatorCtxFunc(func(ctx context.Context, value interface{}) (interface{}, error) {
+		if err := lookup(ctx, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}), &schema.Schema{}
+}
+
+func TestReferenceValidateCtx(t *testing.T) {
+	errLookup := errors.New("lookup failure")
+	type ctxKey struct{}
+	rc := ctxReferenceChecker{
+		"users": func(ctx context.Context, id interface{}) error {
+			if ctx.Value(ctxKey{}) != "test" {
+				return errors.New("context not forwarded")
+			}
+			switch id {
+			case "a", "b":
+				return nil
+			case "fail":
+				return errLookup
+			}
+			return errors.New("not found")
+		},
+	}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"user":  {Validator: &schema.Reference{Path: "users"}},
+			"users": {Validator: &schema.Array{Values: schema.Field{Validator: &schema.Reference{Path: "users"}}}},
+		},
+	}
+	if err := s.Compile(rc); err != nil {
+		t.Fatalf("Compile unexpected error: %v", err)
+	}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "test")
+
+	cases := []struct {
+		name    string
+		ctx     context.Context
+		payload map[string]interface{}
+		errs    map[string][]interface{}
+	}{
+		{
+			name:    "Existing",
+			ctx:     ctx,
+			payload: map[string]interface{}{"user": "a", "users": []interface{}{"a", "b"}},
+		},
+		{
+			name:    "Missing",
+			ctx:     ctx,
+			payload: map[string]interface{}{"user": "c"},
+			errs: map[string][]interface{}{
+				"user": {schema.ValidationError{Code: schema.CodeValidator, Message: "not found", Field: "user"}},
+			},
+		},
+		{
+			name:    "MissingInArray",
+			ctx:     ctx,
+			payload: map[string]interface{}{"users": []interface{}{"a", "c"}},
+			errs: map[string][]interface{}{
+				"users": {
+					schema.ValidationError{Code: schema.CodeValidator, Message: "invalid value at #2: not found", Field: "users"},
+					map[string][]interface{}{
+						"1": {schema.ValidationError{Code: schema.CodeValidator, Message: "not found", Field: "1"}},
+					},
+				},
+			},
+		},
+		{
+			name:    "LookupError",
+			ctx:     ctx,
+			payload: map[string]interface{}{"user": "fail"},
+			errs: map[string][]interface{}{
+				"user": {schema.ValidationError{Code: schema.CodeValidator, Message: "lookup failure", Field: "user"}},
+			},
+		},
+	}
+	for i := range cases {
+		tc := cases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			changes, base := s.Prepare(tc.ctx, tc.payload, nil, false)
+			_, errs := s.ValidateCtx(tc.ctx, changes, base)
+			if len(tc.errs) == 0 && len(errs) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(errs, tc.errs) {
+				t.Errorf("ValidateCtx() errs = %#v, want %#v", errs, tc.errs)
+			}
+		})
+	}
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := s.Fields["user"].Validator.(schema.FieldValidatorCtx).ValidateCtx(ctx, "a")
+		if err != context.Canceled {
+			t.Errorf("ValidateCtx() err = %v, want %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestReferencePrepareUpdate(t *testing.T) {
+	type ctxKey struct{}
+	var lookups []interface{}
+	rc := ctxReferenceChecker{
+		"users": func(ctx context.Context, id interface{}) error {
+			if ctx.Value(ctxKey{}) != "test" {
+				return errors.New("context not forwarded")
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			lookups = append(lookups, id)
+			return nil
+		},
+	}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"user":  {Validator: &schema.Reference{Path: "users"}},
+			"owner": {Validator: &schema.Reference{Path: "users"}, ReadOnly: true},
+			"users": {Validator: &schema.Array{Values: schema.Field{Validator: &schema.Reference{Path: "users"}}}},
+		},
+	}
+	if err := s.Compile(rc); err != nil {
+		t.Fatalf("Compile unexpected error: %v", err)
+	}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "test")
+	original := map[string]interface{}{"user": "a", "owner": "a", "users": []interface{}{"a"}}
+
+	// References are only looked up by Validate, once per changed value,
+	// with the request context: the unchanged owner is not looked up again.
+	payload := map[string]interface{}{"user": "b", "owner": "a", "users": []interface{}{"a", "b"}}
+	changes, base := s.Prepare(ctx, payload, &original, true)
+	if len(lookups) > 0 {
+		t.Errorf("Prepare() lookups = %v, want none", lookups)
+	}
+	doc, errs := s.ValidateCtx(ctx, changes, base)
+	if len(errs) > 0 {
+		t.Errorf("ValidateCtx() unexpected errs: %v", errs)
+	}
+	if len(lookups) != 3 {
+		t.Errorf("ValidateCtx() lookups = %v, want 3", lookups)
+	}
+	if want := map[string]interface{}{"user": "b", "owner": "a", "users": []interface{}{"a", "b"}}; !reflect.DeepEqual(doc, want) {
+		t.Errorf("ValidateCtx() doc = %v, want %v", doc, want)
+	}
+
+	// No lookup is made once the request is canceled.
+	lookups = nil
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	changes, base = s.Prepare(ctx, payload, &original, true)
+	_, errs = s.ValidateCtx(ctx, changes, base)
+	if len(lookups) > 0 {
+		t.Errorf("lookups = %v, want none", lookups)
+	}
+	if len(errs) == 0 {
+		t.Error("ValidateCtx() expected errors")
+	}
+}
@@ -2,9 +2,13 @@ package schema
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"reflect"
+	"runtime"
+	"sort"
+	"sync"
 )
 
 type internal struct{}
@@ -12,6 +16,41 @@ type internal struct{}
 // Tombstone is used to mark a field for removal.
 var Tombstone = internal{}
 
+// ErrReplaceWithoutOriginal is returned by PrepareE when replace is requested
+// without an original document.
+var ErrReplaceWithoutOriginal = errors.New("cannot use replace without original")
+
+// deleteError is stored in place of a Tombstone when the OnDelete hook of the
+// field returned an error.
+type deleteError struct {
+	err error
+}
+
+// hookError is stored in the change map in place of the value of a field when
+// its OnInitErr or OnUpdateErr hook returned an error.
+type hookError struct {
+	err error
+}
+
+// immutableError is stored in the change map in place of the value of a
+// CreateOnly field changed by the client on update.
+type immutableError struct{}
+
+// forbiddenError is stored in the change map in place of the value of a field
+// changed by the client while its WriteIf function returns false.
+type forbiddenError struct{}
+
+// aliasConflict is stored in the change map in place of the value of an alias
+// set in the payload together with its field.
+type aliasConflict struct {
+	field string
+}
+
+func isHookError(value interface{}) bool {
+	_, ok := value.(hookError)
+	return ok
+}
+
 // Validator is an interface used to validate schema against actual data.
 type Validator interface {
 	GetField(name string) *Field
@@ -19,6 +58,25 @@ type Validator interface {
 	Validate(changes map[string]interface{}, base map[string]interface{}) (doc map[string]interface{}, errs map[string][]interface{})
 }
 
+// ValidatorCtx is implemented by Validators able to pass a context to the
+// FieldValidatorCtx implementations of their fields.
+type ValidatorCtx interface {
+	ValidateCtx(ctx context.Context, changes map[string]interface{}, base map[string]interface{}) (doc map[string]interface{}, errs map[string][]interface{})
+}
+
+// ValidatorWarnings is implemented by Validators reporting non-fatal
+// warnings, such as changes on deprecated fields, along with the errors.
+type ValidatorWarnings interface {
+	ValidateWithWarningsCtx(ctx context.Context, changes map[string]interface{}, base map[string]interface{}) (doc map[string]interface{}, errs map[string][]interface{}, warnings map[string][]interface{})
+}
+
+// FieldComputer is implemented by validators able to populate the computed
+// fields of a document (see Field.Compute).
+type FieldComputer interface {
+	// Compute returns payload with the values of the computed fields set.
+	Compute(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error)
+}
+
 // Schema defines fields for a document.
 type Schema struct {
 	// Description of the object described by this schema.
@@ -29,6 +87,78 @@ type Schema struct {
 	MinLen int
 	// MaxLen defines the maximum number of fields (default no limit).
 	MaxLen int
+	// ParallelValidation runs the field validators of the schema concurrently
+	// using a pool of GOMAXPROCS workers. It may speed up the validation of
+	// large documents using expensive validators. Validators must be safe for
+	// concurrent use.
+	ParallelValidation bool
+	// MaxDepth defines the maximum nesting level of sub-documents handled by
+	// sub-schemas (default DefaultMaxDepth), the root document having a depth
+	// of 1. Deeper sub-documents are rejected with a "max depth exceeded"
+	// error instead of being processed, protecting self-referencing schemas
+	// against deeply nested payloads. Only the setting of the root schema is
+	// used.
+	MaxDepth int
+	// Conditions defines constraints applied to the document when it matches
+	// a predicate (see Condition). They are evaluated once the fields of the
+	// document are validated.
+	Conditions []Condition
+	// PreValidate is called by Validate on the root document, once the
+	// changes are applied to the base, before the fields are validated. An
+	// error rejects the document without validating its fields; it is
+	// reported on the document, or by field if it is an ErrorMap.
+	PreValidate func(ctx context.Context, doc map[string]interface{}) error
+	// PostValidate is called by Validate on the validated root document with
+	// the errors by field (the document errors being stored under the ""
+	// key). It returns the errors to report, so errors can be added or
+	// removed.
+	PostValidate func(ctx context.Context, doc map[string]interface{}, errs map[string][]interface{}) map[string][]interface{}
+	// OnDelete is called by ValidateDelete when a document of the schema is
+	// deleted, after the OnDelete hooks of its fields succeeded, i.e. to
+	// clean up resources referenced by several fields. An error aborts the
+	// deletion; it is reported on the document, or by field if it is an
+	// ErrorMap.
+	OnDelete func(ctx context.Context, doc map[string]interface{}) error
+	// UnknownFields defines how the fields of the document which are not
+	// defined by the schema are handled (see UnknownFieldsPolicy). Sub-schemas
+	// not setting it use the policy of their parent schema.
+	UnknownFields UnknownFieldsPolicy
+}
+
+// UnknownFieldsPolicy defines how Validate handles the fields of a document
+// which are not defined by its schema.
+type UnknownFieldsPolicy int
+
+const (
+	// InheritUnknownFields uses the policy of the parent schema, or
+	// RejectUnknownFields for a root schema.
+	InheritUnknownFields UnknownFieldsPolicy = iota
+	// RejectUnknownFields reports an "invalid field" error on unknown fields.
+	RejectUnknownFields
+	// StripUnknownFields silently removes unknown fields from the document.
+	StripUnknownFields
+	// AllowUnknownFields keeps unknown fields in the document as is.
+	AllowUnknownFields
+)
+
+// unknownFields returns the UnknownFields policy of s, or inherited if s
+// doesn't set it.
+func (s Schema) unknownFields(inherited UnknownFieldsPolicy) UnknownFieldsPolicy {
+	if s.UnknownFields != InheritUnknownFields {
+		return s.UnknownFields
+	}
+	return inherited
+}
+
+// DefaultMaxDepth is the MaxDepth used by schemas not setting it.
+const DefaultMaxDepth = 32
+
+// maxDepth returns the MaxDepth of s or its default value.
+func (s Schema) maxDepth() int {
+	if s.MaxDepth > 0 {
+		return s.MaxDepth
+	}
+	return DefaultMaxDepth
 }
 
 // Compile implements the ReferenceCompiler interface and call the same function
@@ -37,16 +167,107 @@ type Schema struct {
 // or Validate on a Schema instance, otherwise FieldValidator instances may not
 // be initialized correctly.
 func (s Schema) Compile(rc ReferenceChecker) error {
-	if err := compileDependencies(s, s); err != nil {
-		return err
+	return s.compile(rc, true)
+}
+
+// compile compiles the fields of s. Dependencies are only compiled from the
+// root schema as they can refer to the fields of parent schemas.
+func (s Schema) compile(rc ReferenceChecker, isRoot bool) error {
+	if isRoot {
+		if err := compileDependencies(s, s, ""); err != nil {
+			return err
+		}
 	}
 	for field, def := range s.Fields {
 		// Compile each field.
-		if err := def.Compile(rc); err != nil {
-			return fmt.Errorf("%s%v", field, err)
+		if err := def.compile(rc); err != nil {
+			return newCompileError(field, err)
+		}
+		for _, name := range def.Excludes {
+			excluded, found := s.Fields[name]
+			if !found {
+				return newCompileError(field, fmt.Errorf("excluded field %s not found", name))
+			}
+			if excluded.Required {
+				return newCompileError(field, fmt.Errorf("excluded field %s is required", name))
+			}
+		}
+		for _, alias := range def.Aliases {
+			if _, found := s.Fields[alias]; found {
+				return newCompileError(field, fmt.Errorf("alias %s is a field name", alias))
+			}
+			for other, otherDef := range s.Fields {
+				if other == field {
+					continue
+				}
+				for _, a := range otherDef.Aliases {
+					if a == alias {
+						return newCompileError(field, fmt.Errorf("alias %s is already used by %s", alias, other))
+					}
+				}
+			}
 		}
 	}
-	return nil
+	return compileConditions(s, rc)
+}
+
+// compileError is a compilation error on the field at path, the fields of
+// nested schemas being joined with dots (i.e.: "address.zip").
+type compileError struct {
+	path string
+	err  error
+}
+
+// newCompileError returns err prefixed with field, or with field and a dot if
+// err is a compileError of a sub-schema field.
+func newCompileError(field string, err error) error {
+	if ce, ok := err.(compileError); ok {
+		return compileError{field + "." + ce.path, ce.err}
+	}
+	return compileError{field, err}
+}
+
+func (e compileError) Error() string {
+	return e.path + ": " + e.err.Error()
+}
+
+// Clone returns a deep copy of s: the Fields map and the fields are copied
+// (see Field.Clone), so the clone can be modified without affecting s.
+func (s Schema) Clone() Schema {
+	c := s
+	if s.Conditions != nil {
+		c.Conditions = append([]Condition(nil), s.Conditions...)
+	}
+	if s.Fields != nil {
+		c.Fields = make(Fields, len(s.Fields))
+		for name, def := range s.Fields {
+			c.Fields[name] = def.Clone()
+		}
+	}
+	return c
+}
+
+// Describe returns a JSON serializable description of the schema with the
+// description of each field under the "fields" key (see Field.Describe).
+func (s Schema) Describe() map[string]interface{} {
+	fields := make(map[string]interface{}, len(s.Fields))
+	for name, def := range s.Fields {
+		if def.Hidden {
+			continue
+		}
+		fields[name] = def.Describe()
+	}
+	m := map[string]interface{}{"fields": fields}
+	if s.Description != "" {
+		m["description"] = s.Description
+	}
+	if s.MinLen > 0 {
+		m["minLen"] = s.MinLen
+	}
+	if s.MaxLen > 0 {
+		m["maxLen"] = s.MaxLen
+	}
+	return m
 }
 
 // GetField implements the FieldGetter interface.
@@ -56,8 +277,11 @@ func (s Schema) GetField(name string) *Field {
 	field, found := s.Fields[name]
 
 	if !found {
-		// invalid name.
-		return nil
+		if name, found = s.aliasOf(name); !found {
+			// invalid name.
+			return nil
+		}
+		field = s.Fields[name]
 	}
 
 	if !wasSplit {
@@ -79,6 +303,112 @@ func (s Schema) GetField(name string) *Field {
 	return nil
 }
 
+// aliasOf returns the name of the field having alias in its Aliases.
+func (s Schema) aliasOf(alias string) (string, bool) {
+	for name, def := range s.Fields {
+		for _, a := range def.Aliases {
+			if a == alias {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// resolveAliases returns payload with the value of the aliases stored under
+// their field name. An alias set together with its field is replaced by an
+// aliasConflict so Validate() can report it. The payload is copied when
+// changed.
+func (s Schema) resolveAliases(payload map[string]interface{}) map[string]interface{} {
+	copied := false
+	for name, def := range s.Fields {
+		for _, alias := range def.Aliases {
+			value, found := payload[alias]
+			if !found {
+				continue
+			}
+			if !copied {
+				p := make(map[string]interface{}, len(payload))
+				for k, v := range payload {
+					p[k] = v
+				}
+				payload, copied = p, true
+			}
+			if _, found := payload[name]; found {
+				payload[alias] = aliasConflict{name}
+				continue
+			}
+			delete(payload, alias)
+			payload[name] = value
+		}
+	}
+	return payload
+}
+
+// IsFilterable returns true if the field at path (i.e.: "address.city") exists
+// and can be used with the `filter` parameter.
+func (s Schema) IsFilterable(path string) bool {
+	f := s.GetField(path)
+	return f != nil && f.Filterable
+}
+
+// IsSortable returns true if the field at path (i.e.: "address.city") exists
+// and can be used with the `sort` parameter.
+func (s Schema) IsSortable(path string) bool {
+	f := s.GetField(path)
+	return f != nil && f.Sortable
+}
+
+// FieldPaths returns the sorted dotted paths of all the fields of the schema,
+// including the fields of sub-schemas and Object validators (i.e.: "user",
+// "user.address" and "user.address.zip"). The items of Array and the values of
+// Dict validators are listed with a "*" marker (i.e.: "tags.*" or
+// "tags.*.name" for an array of objects). Hidden fields are included, see
+// VisibleFieldPaths.
+func (s Schema) FieldPaths() []string {
+	paths := s.appendFieldPaths(nil, "", true)
+	sort.Strings(paths)
+	return paths
+}
+
+// VisibleFieldPaths is like FieldPaths but omits the hidden fields and the
+// fields nested in them.
+func (s Schema) VisibleFieldPaths() []string {
+	paths := s.appendFieldPaths(nil, "", false)
+	sort.Strings(paths)
+	return paths
+}
+
+func (s Schema) appendFieldPaths(paths []string, prefix string, hidden bool) []string {
+	for name, def := range s.Fields {
+		if def.Hidden && !hidden {
+			continue
+		}
+		paths = def.appendFieldPaths(paths, prefix+name, hidden)
+	}
+	return paths
+}
+
+// appendFieldPaths appends path and the paths of the fields nested in f to
+// paths.
+func (f Field) appendFieldPaths(paths []string, path string, hidden bool) []string {
+	paths = append(paths, path)
+	if f.Schema != nil {
+		return f.Schema.appendFieldPaths(paths, path+".", hidden)
+	}
+	switch v := f.Validator.(type) {
+	case *Object:
+		if v.Schema != nil {
+			return v.Schema.appendFieldPaths(paths, path+".", hidden)
+		}
+	case *Array:
+		return v.Values.appendFieldPaths(paths, path+".*", hidden)
+	case *Dict:
+		return v.Values.appendFieldPaths(paths, path+".*", hidden)
+	}
+	return paths
+}
+
 // Prepare takes a payload with an optional original payout when updating an
 // existing item and return two maps, one containing changes operated by the
 // user and another defining either existing data (from the current item) or
@@ -99,20 +429,262 @@ func (s Schema) GetField(name string) *Field {
 // being absent). This instruct the validator that the field has been edited, so
 // ReadOnly flag can throw an error and the field will be removed from the
 // output document. The OnInit is also called instead of the OnUpdate.
+//
+// Hooks are not called anymore once ctx is done: the context error is then
+// reported by Validate as a hook error.
+//
+// Prepare panics if replace is true while original is nil; use PrepareE to get
+// an error instead.
 func (s Schema) Prepare(ctx context.Context, payload map[string]interface{}, original *map[string]interface{}, replace bool) (changes map[string]interface{}, base map[string]interface{}) {
+	return s.prepareRoot(ctx, payload, original, replace, false)
+}
+
+// PrepareE is like Prepare but returns ErrReplaceWithoutOriginal instead of
+// panicking when replace is true while original is nil.
+func (s Schema) PrepareE(ctx context.Context, payload map[string]interface{}, original *map[string]interface{}, replace bool) (changes map[string]interface{}, base map[string]interface{}, err error) {
+	if replace && original == nil {
+		return nil, nil, ErrReplaceWithoutOriginal
+	}
+	changes, base = s.prepareRoot(ctx, payload, original, replace, false)
+	return changes, base, nil
+}
+
+// PrepareMergePatch is like Prepare with replace set to false, but follows the
+// JSON Merge Patch semantics (RFC 7386): a nil value in the payload removes the
+// corresponding field from the original document, and sub-documents are
+// merged with their original value instead of replacing it. The OnUpdate hook
+// is executed on each field.
+func (s Schema) PrepareMergePatch(ctx context.Context, payload map[string]interface{}, original *map[string]interface{}) (changes map[string]interface{}, base map[string]interface{}) {
+	if original == nil {
+		log.Panic("Cannot use merge patch without original")
+	}
+	return s.prepareRoot(ctx, payload, original, false, true)
+}
+
+// prepareRoot prepares a root document and then calls the OnInitDoc or
+// OnUpdateDoc hooks of its fields.
+func (s Schema) prepareRoot(ctx context.Context, payload map[string]interface{}, original *map[string]interface{}, replace, mergePatch bool) (changes map[string]interface{}, base map[string]interface{}) {
+	changes, base = s.prepare(ctx, payload, original, replace, mergePatch, s.maxDepth())
+	init, depth := original == nil, s.maxDepth()
+	if s.hasWriteIf(depth) {
+		var stored map[string]interface{}
+		if original != nil {
+			stored = *original
+		}
+		s.checkWrites(ctx, changes, base, stored, depth)
+	}
+	if s.hasDocHooks(init, depth) && ctx.Err() == nil {
+		s.callDocHooks(ctx, changes, base, mergePrepared(base, changes), init, depth)
+	}
+	return changes, base
+}
+
+// hasWriteIf returns true if a field of s or of its sub-schemas, up to depth
+// nesting levels, has a WriteIf function.
+func (s Schema) hasWriteIf(depth int) bool {
+	if depth <= 0 {
+		return false
+	}
+	for _, def := range s.Fields {
+		if def.WriteIf != nil || (def.Schema != nil && def.Schema.hasWriteIf(depth-1)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkWrites replaces the changes of the fields whose WriteIf function
+// returns false for ctx by a forbiddenError, stored being the stored document
+// (nil on creation). As sub-documents are prepared without their stored
+// value, the fields omitted from a sub-document keep their stored value
+// instead of being removed.
+func (s Schema) checkWrites(ctx context.Context, changes, base, stored map[string]interface{}, depth int) {
+	for field, def := range s.Fields {
+		value, found := changes[field]
+		sValue, sFound := stored[field]
+		if def.Schema != nil && def.Schema.hasWriteIf(depth-1) {
+			if m, ok := value.(map[string]interface{}); ok {
+				subBase, ok := base[field].(map[string]interface{})
+				if !ok {
+					subBase = map[string]interface{}{}
+				}
+				subStored, _ := sValue.(map[string]interface{})
+				if p, ok := sValue.(*map[string]interface{}); ok {
+					subStored = *p
+				}
+				def.Schema.checkWrites(ctx, m, subBase, subStored, depth-1)
+				if len(subBase) > 0 {
+					base[field] = subBase
+				}
+			}
+		}
+		if def.WriteIf == nil || isPrepareError(value) {
+			continue
+		}
+		changed := false
+		if !found {
+			if _, bFound := base[field]; !bFound && sFound {
+				base[field] = sValue
+			}
+		} else if value == Tombstone {
+			changed = sFound
+		} else if m, ok := value.(map[string]interface{}); ok && def.Schema != nil {
+			changed = def.Schema.subChanged(m, sValue)
+		} else {
+			changed = !sFound || !reflect.DeepEqual(value, sValue)
+		}
+		if changed && !def.WriteIf(ctx) {
+			changes[field] = forbiddenError{}
+			if sFound {
+				base[field] = sValue
+			} else {
+				delete(base, field)
+			}
+		}
+	}
+}
+
+// hasDocHooks returns true if a field of s or of its sub-schemas, up to depth
+// nesting levels, has an OnInitDoc (when init is true) or OnUpdateDoc hook.
+func (s Schema) hasDocHooks(init bool, depth int) bool {
+	if depth <= 0 {
+		return false
+	}
+	for _, def := range s.Fields {
+		if def.docHook(init) != nil || (def.Schema != nil && def.Schema.hasDocHooks(init, depth-1)) {
+			return true
+		}
+	}
+	return false
+}
+
+// callDocHooks calls the OnInitDoc or OnUpdateDoc hooks of the fields of s
+// with doc, storing the returned values in changes or base like the OnInit
+// and OnUpdate hooks.
+func (s Schema) callDocHooks(ctx context.Context, changes, base, doc map[string]interface{}, init bool, depth int) {
+	for field, def := range s.Fields {
+		if def.Schema != nil && def.Schema.hasDocHooks(init, depth-1) {
+			subChanges, cFound := changes[field].(map[string]interface{})
+			subBase, bFound := base[field].(map[string]interface{})
+			if _, found := changes[field]; found && !cFound {
+				// Invalid or removed sub-document.
+				continue
+			}
+			if !cFound {
+				subChanges = map[string]interface{}{}
+			}
+			if !bFound {
+				subBase = map[string]interface{}{}
+			}
+			def.Schema.callDocHooks(ctx, subChanges, subBase, doc, init, depth-1)
+			if !cFound && len(subChanges) > 0 {
+				changes[field] = subChanges
+			}
+			if !bFound && len(subBase) > 0 {
+				base[field] = subBase
+			}
+			continue
+		}
+		hook := def.docHook(init)
+		if hook == nil {
+			continue
+		}
+		if value, found := changes[field]; found {
+			if isPrepareError(value) {
+				continue
+			}
+			if value == Tombstone {
+				delete(changes, field)
+				if v, err := hook(ctx, base[field], doc); err != nil {
+					changes[field] = hookError{err}
+				} else {
+					base[field] = v
+				}
+			} else if v, err := hook(ctx, value, doc); err != nil {
+				changes[field] = hookError{err}
+			} else {
+				changes[field] = v
+			}
+		} else if v, err := hook(ctx, base[field], doc); err != nil {
+			changes[field] = hookError{err}
+		} else {
+			base[field] = v
+		}
+	}
+}
+
+// isPrepareError returns true if value is an error stored by Prepare in the
+// change map, to be reported by Validate.
+func isPrepareError(value interface{}) bool {
+	switch value.(type) {
+	case hookError, deleteError, immutableError, forbiddenError, aliasConflict:
+		return true
+	}
+	return false
+}
+
+// mergePrepared returns a copy of base with the changes returned by Prepare
+// applied, sub-documents being merged recursively. Removed fields and the
+// errors stored in changes are omitted.
+func mergePrepared(base, changes map[string]interface{}) map[string]interface{} {
+	doc := make(map[string]interface{}, len(base)+len(changes))
+	for field, value := range base {
+		doc[field] = value
+	}
+	for field, value := range changes {
+		if value == Tombstone || isPrepareError(value) {
+			delete(doc, field)
+			continue
+		}
+		if m, ok := value.(map[string]interface{}); ok {
+			if bm, ok := doc[field].(map[string]interface{}); ok {
+				value = mergePrepared(bm, m)
+			}
+		}
+		doc[field] = value
+	}
+	return doc
+}
+
+// prepare implements Prepare and PrepareMergePatch; depth is the number of
+// nesting levels left, including the current one.
+func (s Schema) prepare(ctx context.Context, payload map[string]interface{}, original *map[string]interface{}, replace, mergePatch bool, depth int) (changes map[string]interface{}, base map[string]interface{}) {
 	changes = map[string]interface{}{}
 	base = map[string]interface{}{}
+	payload = s.resolveAliases(payload)
+	payload, _ = s.decodeNumbers(payload)
 	for field, def := range s.Fields {
+		if err := ctx.Err(); err != nil {
+			// Stop calling hooks once the request is canceled. The error is
+			// reported by Validate() on the current field.
+			changes[field] = hookError{err}
+			return changes, base
+		}
 		value, found := payload[field]
+		if found && value != nil && len(def.Transform) > 0 {
+			value = def.transform(value)
+		}
+		if def.Compute != nil {
+			// Computed fields are never stored. A value provided by the client
+			// is kept in the change map so Validate() can reject it, unless it
+			// is equal to the value computed from the original document.
+			if found && !def.isComputedValue(ctx, value, original) {
+				changes[field] = value
+			}
+			continue
+		}
 		if original == nil {
 			if replace == true {
 				log.Panic("Cannot use replace=true without original")
 			}
 			// Handle prepare on a new document (no original).
-			if !found || value == nil {
+			if found && value == nil && def.Nullable {
+				// Explicit null, stored as is.
+				changes[field] = nil
+			} else if !found || value == nil {
 				// Add default fields
-				if def.Default != nil {
-					base[field] = def.Default
+				if defValue, ok := def.defaultValue(ctx); ok {
+					base[field] = defValue
 				}
 			} else if found {
 				changes[field] = value
@@ -120,10 +692,29 @@ func (s Schema) Prepare(ctx context.Context, payload map[string]interface{}, ori
 		} else {
 			// Handle prepare on an updated document (original provided).
 			oValue, oFound := (*original)[field]
+			if found && mergePatch && def.Schema == nil {
+				// Merge sub-documents of fields not handled by a sub-schema
+				// (i.e.: Object or Dict validators) with their original value.
+				if m, ok := value.(map[string]interface{}); ok {
+					if om, ok := oValue.(map[string]interface{}); ok {
+						value = applyMergePatch(om, m)
+					}
+				}
+			}
 			// Apply value to change-set only if the field was not identical same in the original doc.
-			if found {
-				if def.Validator != nil {
-					if validated, err := def.Validator.Validate(value); err != nil {
+			if found && mergePatch && value == nil {
+				// With merge patch, a nil value removes the field.
+				if oFound {
+					changes[field] = Tombstone
+				}
+			} else if found && value == nil && def.Nullable {
+				// Explicit null, stored as is.
+				if !oFound || oValue != nil {
+					changes[field] = nil
+				}
+			} else if found {
+				if def.Validator != nil && !needsLookup(def.Validator, depth) {
+					if validated, err := ValidateField(ctx, def.Validator, value); err != nil {
 						// We treat a validation error as a change; the validation
 						// error indicate invalid payload and will be caught
 						// again by schema.Validate().
@@ -132,18 +723,24 @@ func (s Schema) Prepare(ctx context.Context, payload map[string]interface{}, ori
 						changes[field] = validated
 					}
 				} else if !oFound || !reflect.DeepEqual(value, oValue) {
+					// Validators performing lookups (i.e.: Reference) are
+					// only run once, by Validate, on the changed values.
 					changes[field] = value
 				}
+			} else if oFound && replace && (def.CreateOnly || !def.isWritable(ctx)) {
+				// A create-only field, or a field the client is not allowed
+				// to write, omitted from a replacement document keeps its
+				// stored value, set in base below.
 			} else if oFound && replace {
 				// When replace arg is true and a field is not present in the payload but is in the original,
 				// the tombstone value is set on the field in the change map so validator can enforce the
 				// ReadOnly and then the field can be removed from the output document.
-				// One exception to that though: if the field is set to hidden and is not readonly, we use
-				// previous value as the client would have no way to resubmit the stored value.
+				// One exception to that though: if the field is set to hidden and is not readonly, the
+				// stored value is kept in base, as the client would have no way to resubmit it.
 				if def.Hidden && !def.ReadOnly {
-					changes[field] = oValue
-				} else if def.Default != nil {
-					changes[field] = def.Default
+					// Kept as is.
+				} else if defValue, ok := def.defaultValue(ctx); ok {
+					changes[field] = defValue
 				} else {
 					changes[field] = Tombstone
 				}
@@ -162,13 +759,23 @@ func (s Schema) Prepare(ctx context.Context, payload map[string]interface{}, ori
 				subOriginal = &map[string]interface{}{}
 				if su, ok := oValue.(*map[string]interface{}); ok {
 					subOriginal = su
+				} else if su, ok := oValue.(map[string]interface{}); ok && mergePatch {
+					subOriginal = &su
 				}
 			}
-			if found {
+			if found && mergePatch && value == nil {
+				// The sub-document is removed, see above.
+			} else if depth <= 1 {
+				// Max depth reached: a provided sub-document is kept as is so
+				// Validate() can reject it, otherwise recursion stops here.
+				if found {
+					changes[field] = value
+				}
+			} else if found {
 				if subPayload, ok := value.(map[string]interface{}); ok {
 					// If payload contains a sub-document for this field, validate it
 					// using the sub-validator.
-					c, b := def.Schema.Prepare(ctx, subPayload, subOriginal, replace)
+					c, b := def.Schema.prepare(ctx, subPayload, subOriginal, replace, mergePatch, depth-1)
 					changes[field] = c
 					base[field] = b
 				} else {
@@ -176,8 +783,9 @@ func (s Schema) Prepare(ctx context.Context, payload map[string]interface{}, ori
 				}
 			} else {
 				// If the payload doesn't contain a sub-document, perform validation
-				// on an empty one so we don't miss default values.
-				c, b := def.Schema.Prepare(ctx, map[string]interface{}{}, subOriginal, replace)
+				// on an empty one so we don't miss default values. The original
+				// sub-document of a create-only field is kept on replace.
+				c, b := def.Schema.prepare(ctx, map[string]interface{}{}, subOriginal, replace && !def.CreateOnly, mergePatch, depth-1)
 				if len(c) > 0 || len(b) > 0 {
 					// Only apply prepared field if something was added.
 					changes[field] = c
@@ -185,28 +793,56 @@ func (s Schema) Prepare(ctx context.Context, payload map[string]interface{}, ori
 				}
 			}
 		}
+		// Check if a create-only field is changed by the client, before hooks
+		// are applied.
+		createOnlyChanged := false
+		if def.CreateOnly && original != nil {
+			if value, found := changes[field]; found {
+				if m, ok := value.(map[string]interface{}); ok && def.Schema != nil {
+					createOnlyChanged = def.Schema.subChanged(m, (*original)[field])
+				} else {
+					createOnlyChanged = !reflect.DeepEqual(value, (*original)[field])
+				}
+			}
+		}
 		// Call the OnInit or OnUpdate depending on the presence of the original doc and the
 		// state of the replace argument.
-		var hook func(ctx context.Context, value interface{}) interface{}
-		if original == nil {
-			hook = def.OnInit
-		} else {
-			hook = def.OnUpdate
-		}
-		if hook != nil {
+		if hook := def.hook(original == nil); hook != nil {
 			// Get the change value or fallback on the base value.
 			if value, found := changes[field]; found {
 				if value == Tombstone {
 					// If the field has a tombstone, apply the handler on the
 					// base and remove the tombstone so it doesn't appear as a
 					// user generated change.
-					base[field] = hook(ctx, base[field])
 					delete(changes, field)
+					if v, err := hook(ctx, base[field]); err != nil {
+						changes[field] = hookError{err}
+					} else {
+						base[field] = v
+					}
+				} else if v, err := hook(ctx, value); err != nil {
+					changes[field] = hookError{err}
 				} else {
-					changes[field] = hook(ctx, value)
+					changes[field] = v
 				}
+			} else if v, err := hook(ctx, base[field]); err != nil {
+				changes[field] = hookError{err}
 			} else {
-				base[field] = hook(ctx, base[field])
+				base[field] = v
+			}
+		}
+		// Call the OnDelete hook if the field is being removed. An error is
+		// stored in the change map so Validate() can report it.
+		if def.OnDelete != nil && original != nil {
+			if value, found := changes[field]; found && value == Tombstone {
+				if err := def.OnDelete(ctx, (*original)[field]); err != nil {
+					changes[field] = deleteError{err}
+				}
+			}
+		}
+		if createOnlyChanged {
+			if value, found := changes[field]; found && !isHookError(value) {
+				changes[field] = immutableError{}
 			}
 		}
 	}
@@ -220,42 +856,230 @@ func (s Schema) Prepare(ctx context.Context, payload map[string]interface{}, ori
 	return
 }
 
+// subChanged returns true if changes, as prepared by s for a sub-document,
+// differ from the original value of the sub-document.
+func (s Schema) subChanged(changes map[string]interface{}, original interface{}) bool {
+	var o map[string]interface{}
+	switch t := original.(type) {
+	case map[string]interface{}:
+		o = t
+	case *map[string]interface{}:
+		o = *t
+	}
+	for field, value := range changes {
+		if m, ok := value.(map[string]interface{}); ok && s.Fields[field].Schema != nil {
+			if s.Fields[field].Schema.subChanged(m, o[field]) {
+				return true
+			}
+		} else if oValue, found := o[field]; !found || !reflect.DeepEqual(value, oValue) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyMergePatch returns a copy of target with patch applied following the
+// JSON Merge Patch semantics (RFC 7386).
+func applyMergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(target))
+	for k, v := range target {
+		result[k] = v
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(result, k)
+			continue
+		}
+		if m, ok := v.(map[string]interface{}); ok {
+			if tm, ok := result[k].(map[string]interface{}); ok {
+				v = applyMergePatch(tm, m)
+			} else {
+				v = applyMergePatch(map[string]interface{}{}, m)
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
+
 // Validate validates changes applied on a base document in regard to the schema
 // and generate an result document with the changes applied to the base document.
-// All errors in the process are reported in the returned errs value.
+// All errors in the process are reported in the returned errs value. Errors
+// are either ValidationError values or, for sub-schemas, nested errs maps.
 func (s Schema) Validate(changes map[string]interface{}, base map[string]interface{}) (doc map[string]interface{}, errs map[string][]interface{}) {
-	return s.validate(changes, base, true)
+	return s.ValidateCtx(context.Background(), changes, base)
+}
+
+// ValidateCtx is like Validate but passes ctx to the FieldValidatorCtx
+// implementations.
+func (s Schema) ValidateCtx(ctx context.Context, changes map[string]interface{}, base map[string]interface{}) (doc map[string]interface{}, errs map[string][]interface{}) {
+	doc, errs, _ = s.validate(ctx, changes, base, true, s.maxDepth(), RejectUnknownFields, false)
+	return doc, errs
+}
+
+// ValidateWithWarnings is like Validate but also returns non-fatal warnings,
+// structured like errs. A warning is reported for each deprecated field
+// present in changes.
+func (s Schema) ValidateWithWarnings(changes map[string]interface{}, base map[string]interface{}) (doc map[string]interface{}, errs map[string][]interface{}, warnings map[string][]interface{}) {
+	return s.validate(context.Background(), changes, base, true, s.maxDepth(), RejectUnknownFields, false)
+}
+
+// ValidateWithWarningsCtx is like ValidateWithWarnings but passes ctx to the
+// FieldValidatorCtx implementations.
+func (s Schema) ValidateWithWarningsCtx(ctx context.Context, changes map[string]interface{}, base map[string]interface{}) (doc map[string]interface{}, errs map[string][]interface{}, warnings map[string][]interface{}) {
+	return s.validate(ctx, changes, base, true, s.maxDepth(), RejectUnknownFields, false)
+}
+
+// PartialValidate validates the fields present in changes only, as when a
+// client checks a form field by field. The provided fields are checked
+// against their validators, dependencies and conditions, and unknown fields
+// are handled according to UnknownFields, but absent fields are never
+// reported as required, Tombstone values are simply dropped and MinLen is
+// not enforced. Stored computed fields (OnCompute) are not set.
+//
+// As there is no base document, the returned doc only holds the validated
+// fields and must not be stored.
+func (s Schema) PartialValidate(changes map[string]interface{}) (doc map[string]interface{}, errs map[string][]interface{}) {
+	return s.PartialValidateCtx(context.Background(), changes)
+}
+
+// PartialValidateCtx is like PartialValidate but passes ctx to the
+// FieldValidatorCtx implementations.
+func (s Schema) PartialValidateCtx(ctx context.Context, changes map[string]interface{}) (doc map[string]interface{}, errs map[string][]interface{}) {
+	doc, errs, _ = s.validate(ctx, changes, nil, true, s.maxDepth(), RejectUnknownFields, true)
+	return doc, errs
+}
+
+// ValidateDelete calls the delete hooks of the schema for the stored
+// document doc about to be deleted: the OnDelete hook of each field set in
+// doc, sub-schemas included, then the OnDelete hook of the schema. The errors
+// returned by the hooks are reported like the errors of Validate, with the
+// CodeDelete code; the document must not be deleted if errs is not empty.
+func (s Schema) ValidateDelete(ctx context.Context, doc map[string]interface{}) (errs map[string][]interface{}) {
+	errs = map[string][]interface{}{}
+	for field, def := range s.Fields {
+		value, found := doc[field]
+		if !found {
+			continue
+		}
+		if def.OnDelete != nil {
+			if err := def.OnDelete(ctx, value); err != nil {
+				addFieldError(errs, field, ValidationError{CodeDelete, err.Error(), field, nil})
+				continue
+			}
+		}
+		if sub, ok := value.(map[string]interface{}); ok && def.Schema != nil {
+			if subErrs := def.Schema.ValidateDelete(ctx, sub); len(subErrs) > 0 {
+				addFieldError(errs, field, subErrs)
+			}
+		}
+	}
+	if len(errs) == 0 && s.OnDelete != nil {
+		if err := s.OnDelete(ctx, doc); err != nil {
+			if errMap, ok := err.(ErrorMap); ok {
+				mergeFieldErrors(errs, errMap)
+			} else {
+				addFieldError(errs, "", ValidationError{CodeDelete, err.Error(), "", nil})
+			}
+		}
+	}
+	return errs
 }
 
-func (s Schema) validate(changes map[string]interface{}, base map[string]interface{}, isRoot bool) (doc map[string]interface{}, errs map[string][]interface{}) {
+// HasDeleteHooks returns true if the schema, or one of its sub-schemas,
+// defines an OnDelete hook called by ValidateDelete.
+func (s Schema) HasDeleteHooks() bool {
+	if s.OnDelete != nil {
+		return true
+	}
+	for _, def := range s.Fields {
+		if def.OnDelete != nil || (def.Schema != nil && def.Schema.HasDeleteHooks()) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateChanges is like ValidateCtx but also returns the top-level fields
+// whose validated value differs from their value in base, including removed
+// fields. As validators normalize values, a field present in changes may end
+// up identical to its base value and is then not reported. Storage writes
+// and ETag updates can be skipped when no field changed.
+//
+// The base returned by Prepare holds the values set by defaults and hooks, so
+// a field changed by a hook only (i.e.: an OnUpdate timestamp) is not
+// reported. The changed map is nil when errs is not empty.
+func (s Schema) ValidateChanges(ctx context.Context, changes map[string]interface{}, base map[string]interface{}) (doc map[string]interface{}, errs map[string][]interface{}, changed map[string]bool) {
+	doc, errs, _ = s.validate(ctx, changes, base, true, s.maxDepth(), RejectUnknownFields, false)
+	if len(errs) > 0 {
+		return doc, errs, nil
+	}
+	changed = map[string]bool{}
+	for field, value := range doc {
+		if bValue, found := base[field]; !found || !reflect.DeepEqual(value, bValue) {
+			changed[field] = true
+		}
+	}
+	for field := range base {
+		if _, found := doc[field]; !found {
+			changed[field] = true
+		}
+	}
+	return doc, errs, changed
+}
+
+// validate implements Validate; depth is the number of nesting levels left,
+// including the current one, unknown the UnknownFields policy of the parent
+// schema and partial whether the fields absent from changes are ignored (see
+// PartialValidate).
+func (s Schema) validate(ctx context.Context, changes map[string]interface{}, base map[string]interface{}, isRoot bool, depth int, unknown UnknownFieldsPolicy, partial bool) (doc map[string]interface{}, errs map[string][]interface{}, warnings map[string][]interface{}) {
+	unknown = s.unknownFields(unknown)
 	doc = map[string]interface{}{}
 	errs = map[string][]interface{}{}
+	warnings = map[string][]interface{}{}
 	for field, def := range s.Fields {
-		// Check read only fields.
-		if def.ReadOnly {
-			if _, found := changes[field]; found {
-				addFieldError(errs, field, "read-only")
+		// Warn about changes on deprecated fields.
+		if def.Deprecated {
+			if value, found := changes[field]; found && value != Tombstone {
+				msg := "deprecated"
+				if def.DeprecationMessage != "" {
+					msg = def.DeprecationMessage
+				}
+				addFieldError(warnings, field, ValidationError{CodeDeprecated, msg, field, nil})
+			}
+		}
+		// Check read only, computed and connection fields.
+		if _, conn := def.Validator.(*Connection); def.ReadOnly || def.Compute != nil || conn {
+			if value, found := changes[field]; found && !isHookError(value) {
+				addFieldError(errs, field, ValidationError{CodeReadOnly, "read-only", field, nil})
+			}
+		}
+		if def.OnCompute != nil {
+			if value, found := changes[field]; found && value != Tombstone {
+				addFieldError(errs, field, ValidationError{CodeReadOnly, "read-only", field, nil})
 			}
 		}
 		// Check required fields.
 		if def.Required {
 			if value, found := changes[field]; !found || value == nil || value == Tombstone {
-				if found {
+				if found && (value == nil || !partial) {
 					// If explicitly set to null, raise the required error.
-					addFieldError(errs, field, "required")
+					addFieldError(errs, field, ValidationError{CodeRequired, "required", field, nil})
+				} else if partial {
+					// Omitted and removed fields are ignored.
 				} else if value, found = base[field]; !found || value == nil {
 					// If field was omitted and isn't set by a Default of a hook, raise.
-					addFieldError(errs, field, "required")
+					addFieldError(errs, field, ValidationError{CodeRequired, "required", field, nil})
 				}
 			}
 		}
 		// Validate sub-schema on non provided fields in order to enforce
 		// required.
-		if def.Schema != nil {
+		if def.Schema != nil && depth > 1 && !partial {
 			if _, found := changes[field]; !found {
 				if _, found := base[field]; !found {
 					empty := map[string]interface{}{}
-					if _, subErrs := def.Schema.validate(empty, empty, false); len(subErrs) > 0 {
+					if _, subErrs, _ := def.Schema.validate(ctx, empty, empty, false, depth-1, unknown, false); len(subErrs) > 0 {
 						addFieldError(errs, field, subErrs)
 					}
 				}
@@ -270,34 +1094,99 @@ func (s Schema) validate(changes map[string]interface{}, base map[string]interfa
 		if value == Tombstone {
 			// If the value is set for removal, remove it from the doc.
 			delete(doc, field)
+		} else if de, ok := value.(deleteError); ok {
+			// The removal of the field was rejected by the OnDelete hook.
+			addFieldError(errs, field, ValidationError{CodeDelete, de.err.Error(), field, nil})
+			delete(doc, field)
+		} else if he, ok := value.(hookError); ok {
+			// The OnInitErr or OnUpdateErr hook of the field failed.
+			addFieldError(errs, field, ValidationError{CodeHook, he.err.Error(), field, nil})
+			delete(doc, field)
+		} else if _, ok := value.(immutableError); ok {
+			// A create-only field was changed on update, keep the stored value.
+			addFieldError(errs, field, ValidationError{CodeImmutable, "immutable", field, nil})
+		} else if _, ok := value.(forbiddenError); ok {
+			// The client is not allowed to write the field, keep the stored
+			// value.
+			addFieldError(errs, field, ValidationError{CodeForbidden, "forbidden field", field, nil})
+		} else if ac, ok := value.(aliasConflict); ok {
+			// Both the alias and its field are set in the payload.
+			msg := fmt.Sprintf("alias of %s, which is also set", ac.field)
+			addFieldError(errs, field, ValidationError{CodeConflict, msg, field, nil})
 		} else {
 			doc[field] = value
 		}
 	}
+	// Computed fields are never stored.
+	for field, def := range s.Fields {
+		if def.Compute != nil {
+			delete(doc, field)
+		}
+	}
+	// Check conditionally required fields against the final document.
+	for field, def := range s.Fields {
+		if def.Required || def.RequiredWhen == nil || !def.RequiredWhen(doc) {
+			continue
+		}
+		if value, found := doc[field]; (!found && !partial) || (found && value == nil) {
+			addFieldError(errs, field, ValidationError{CodeRequired, "required", field, nil})
+		}
+	}
+	// Check mutually exclusive fields.
+	mergeFieldErrors(errs, s.validateExclusions(changes, doc))
 	// Validate all dependency from the root schema only as dependencies can
 	// refers to parent schemas.
 	if isRoot {
 		mergeErrs := s.validateDependencies(changes, doc, "")
 		mergeFieldErrors(errs, mergeErrs)
 	}
+	if isRoot && s.PreValidate != nil {
+		if err := s.PreValidate(ctx, doc); err != nil {
+			if errMap, ok := err.(ErrorMap); ok {
+				mergeFieldErrors(errs, errMap)
+			} else {
+				addFieldError(errs, "", ValidationError{CodeValidator, err.Error(), "", nil})
+			}
+			return nil, errs, warnings
+		}
+	}
+	var validations []fieldValidation
 	for field, value := range doc {
 		// Check invalid field (fields provided in the payload by not present in
 		// the schema).
 		def, found := s.Fields[field]
 		if !found {
-			addFieldError(errs, field, "invalid field")
+			switch unknown {
+			case StripUnknownFields:
+				delete(doc, field)
+			case AllowUnknownFields:
+				// Kept as is.
+			default:
+				addFieldError(errs, field, ValidationError{CodeInvalidField, "invalid field", field, nil})
+			}
 			continue
 		}
-		if def.Schema != nil {
+		if value == nil && (def.Nullable || def.Schema != nil) {
+			// Null is stored as is on nullable fields, and is never a valid
+			// sub-document. The required error has already been reported.
+			if !def.Nullable && !def.Required {
+				addFieldError(errs, field, ValidationError{CodeNull, errNull.Error(), field, nil})
+			}
+			continue
+		}
+		if def.Schema != nil && depth <= 1 {
+			// Don't recurse any further.
+			addFieldError(errs, field, ValidationError{CodeMaxDepth, "max depth exceeded", field, nil})
+		} else if def.Schema != nil {
 			// Schema defines a sub-schema.
 			subChanges := map[string]interface{}{}
 			subBase := map[string]interface{}{}
 			// Check if changes contains a valid sub-document.
-			if v, found := changes[field]; found {
+			if v, found := changes[field]; found && v != (immutableError{}) && v != (forbiddenError{}) {
 				if m, ok := v.(map[string]interface{}); ok {
 					subChanges = m
 				} else {
-					addFieldError(errs, field, "not a dict")
+					addFieldError(errs, field, ValidationError{CodeValidator, "not a dict", field, nil})
 				}
 			}
 			// Check if base contains a valid sub-document.
@@ -305,45 +1194,318 @@ func (s Schema) validate(changes map[string]interface{}, base map[string]interfa
 				if m, ok := v.(map[string]interface{}); ok {
 					subBase = m
 				} else {
-					addFieldError(errs, field, "not a dict")
+					addFieldError(errs, field, ValidationError{CodeValidator, "not a dict", field, nil})
 				}
 			}
 			// Validate sub document and add the result to the current doc's field.
-			if subDoc, subErrs := def.Schema.validate(subChanges, subBase, false); len(subErrs) > 0 {
+			subDoc, subErrs, subWarnings := def.Schema.validate(ctx, subChanges, subBase, false, depth-1, unknown, partial)
+			if len(subWarnings) > 0 {
+				addFieldError(warnings, field, subWarnings)
+			}
+			if isFatal(subErrs) {
+				return nil, map[string][]interface{}{field: {subErrs}}, warnings
+			}
+			if len(subErrs) > 0 {
 				addFieldError(errs, field, subErrs)
 			} else {
 				doc[field] = subDoc
 			}
+		} else if _, changed := changes[field]; !changed {
+			// Values only present in base are stored or server side values
+			// (i.e.: hook results): they are not validated again so a
+			// transformed value (i.e.: a masked card number) is kept as is.
+			continue
 		} else if def.Validator != nil {
-			// Apply validator if provided.
-			var err error
-			if value, err = def.Validator.Validate(value); err != nil {
-				addFieldError(errs, field, err.Error())
-			} else {
-				// Store the normalized value.
-				doc[field] = value
+			// Queue validator if provided.
+			validations = append(validations, fieldValidation{field: field, validator: def.Validator, value: value})
+		}
+	}
+	if s.ParallelValidation {
+		validateFieldsParallel(ctx, validations)
+	} else {
+		validateFields(ctx, validations)
+	}
+	for _, fv := range validations {
+		if isFatalError(fv.err) {
+			// Abort the validation of the document, discarding other errors.
+			return nil, map[string][]interface{}{
+				fv.field: {ValidationError{CodeFatal, fv.err.Error(), fv.field, nil}},
+			}, warnings
+		}
+	}
+	for _, fv := range validations {
+		if itemErrs, ok := fv.err.(itemErrors); ok {
+			// Report the errors of the array items or dict entries keyed by
+			// index or key, in addition to a summary on the field.
+			addFieldError(errs, fv.field, ValidationError{CodeValidator, fv.err.Error(), fv.field, nil})
+			addFieldError(errs, fv.field, itemErrs.errorMap())
+			if e, ok := itemErrs.(arrayItemErrors); ok {
+				// Keep the valid items normalized.
+				doc[fv.field] = e.items
+			}
+		} else if fv.err != nil && doc[fv.field] == nil {
+			// Null rejected by the validator of a field which is not
+			// nullable. The required error has already been reported.
+			if !s.Fields[fv.field].Required {
+				addFieldError(errs, fv.field, ValidationError{CodeNull, errNull.Error(), fv.field, nil})
 			}
+		} else if fv.err != nil {
+			msg := s.Fields[fv.field].validatorMessage(fv.err)
+			addFieldError(errs, fv.field, ValidationError{CodeValidator, msg, fv.field, errorDetails(fv.err)})
+		} else {
+			// Store the normalized value.
+			doc[fv.field] = fv.value
+		}
+	}
+	if len(s.Conditions) > 0 {
+		mergeFieldErrors(errs, s.validateConditions(ctx, doc, partial))
+	}
+	if len(errs) == 0 && !partial {
+		s.computeStoredFields(ctx, doc, errs)
+	}
+	s.customizeErrors(errs)
+	if isRoot && s.PostValidate != nil {
+		if errs = s.PostValidate(ctx, doc, errs); errs == nil {
+			errs = map[string][]interface{}{}
 		}
 	}
 	l := len(doc)
-	if l < s.MinLen {
-		addFieldError(errs, "", fmt.Sprintf("has fewer properties than %d", s.MinLen))
-		return nil, errs
+	if l < s.MinLen && !partial {
+		addFieldError(errs, "", ValidationError{CodeLength, fmt.Sprintf("has fewer properties than %d", s.MinLen), "", nil})
+		return nil, errs, warnings
 	}
 	if s.MaxLen > 0 && l > s.MaxLen {
-		addFieldError(errs, "", fmt.Sprintf("has more properties than %d", s.MaxLen))
-		return nil, errs
+		addFieldError(errs, "", ValidationError{CodeLength, fmt.Sprintf("has more properties than %d", s.MaxLen), "", nil})
+		return nil, errs, warnings
 	}
-	return doc, errs
+	return doc, errs, warnings
+}
+
+// customizeErrors replaces the messages of the ValidationError values of errs
+// with the ErrorMessages of their field. Validator errors are customized when
+// reported.
+func (s Schema) customizeErrors(errs map[string][]interface{}) {
+	for field, values := range errs {
+		def, found := s.Fields[field]
+		if !found || len(def.ErrorMessages) == 0 {
+			continue
+		}
+		for i, v := range values {
+			if ve, ok := v.(ValidationError); ok && ve.Code != CodeValidator {
+				ve.Message = def.errorMessage(ve.Code, ve.Message, nil)
+				values[i] = ve
+			}
+		}
+	}
+}
+
+// computeStoredFields sets the values of the fields with an OnCompute hook in
+// the validated doc, reporting the hook errors in errs.
+func (s Schema) computeStoredFields(ctx context.Context, doc map[string]interface{}, errs map[string][]interface{}) {
+	for field, def := range s.Fields {
+		if def.OnCompute == nil {
+			continue
+		}
+		value, err := def.OnCompute(ctx, doc)
+		if err != nil {
+			addFieldError(errs, field, ValidationError{CodeHook, err.Error(), field, nil})
+			continue
+		}
+		doc[field] = value
+	}
+}
+
+// Serialize prepares payload, as stored, for representation: hidden fields are
+// removed and values of fields with a FieldSerializer are serialized. The
+// payload is modified in place.
+func (s Schema) Serialize(payload map[string]interface{}) error {
+	return s.SerializeCtx(context.Background(), payload)
+}
+
+// SerializeCtx is like Serialize but passes ctx to the FieldSerializerCtx
+// implementations and to the VisibleIf functions of the hidden fields. The
+// serialization is aborted with the context's error if ctx is done.
+func (s Schema) SerializeCtx(ctx context.Context, payload map[string]interface{}) error {
+	computed, err := s.computeFields(ctx, payload)
+	if err != nil {
+		return err
+	}
+	for field, value := range computed {
+		payload[field] = value
+	}
+	for field, value := range payload {
+		def, found := s.Fields[field]
+		if !found {
+			continue
+		}
+		if def.IsHidden(ctx) {
+			delete(payload, field)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if def.OnRead != nil {
+			value = def.OnRead(ctx, value)
+			payload[field] = value
+		}
+		if def.Schema != nil {
+			if subPayload, ok := value.(map[string]interface{}); ok {
+				if err := def.Schema.SerializeCtx(ctx, subPayload); err != nil {
+					return fmt.Errorf("%s.%v", field, err)
+				}
+			}
+			continue
+		}
+		v, err := SerializeField(ctx, def.Validator, value)
+		if err != nil {
+			return fmt.Errorf("%s: %v", field, err)
+		}
+		payload[field] = v
+	}
+	return nil
+}
+
+// Compute implements the FieldComputer interface. It returns a copy of
+// payload with the values of its computed fields, and those of its
+// sub-schemas, set. Payload is returned as is if the schema has no computed
+// fields.
+func (s Schema) Compute(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+	computed, err := s.computeFields(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	for field, def := range s.Fields {
+		if def.Schema == nil || def.Compute != nil || !def.Schema.hasComputedFields() {
+			continue
+		}
+		subPayload, ok := payload[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		subComputed, err := def.Schema.Compute(ctx, subPayload)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%v", field, err)
+		}
+		if computed == nil {
+			computed = map[string]interface{}{}
+		}
+		computed[field] = subComputed
+	}
+	if len(computed) == 0 {
+		return payload, nil
+	}
+	res := make(map[string]interface{}, len(payload)+len(computed))
+	for field, value := range payload {
+		res[field] = value
+	}
+	for field, value := range computed {
+		res[field] = value
+	}
+	return res, nil
+}
+
+// computeFields returns the values of the computed fields of s, not
+// including those of sub-schemas, for payload.
+func (s Schema) computeFields(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+	var computed map[string]interface{}
+	for field, def := range s.Fields {
+		if def.Compute == nil {
+			continue
+		}
+		value, err := def.Compute(ctx, payload)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", field, err)
+		}
+		if computed == nil {
+			computed = map[string]interface{}{}
+		}
+		computed[field] = value
+	}
+	return computed, nil
+}
+
+// hasComputedFields returns true if s or one of its sub-schemas has computed
+// fields.
+func (s Schema) hasComputedFields() bool {
+	for _, def := range s.Fields {
+		if def.Compute != nil || (def.Schema != nil && def.Schema.hasComputedFields()) {
+			return true
+		}
+	}
+	return false
 }
 
 func addFieldError(errs map[string][]interface{}, field string, err interface{}) {
+	if subErrs, ok := err.(map[string][]interface{}); ok {
+		// If the field already holds nested errors, merge the new ones into it
+		// so a single error tree is reported for the sub-document.
+		for _, e := range errs[field] {
+			if m, ok := e.(map[string][]interface{}); ok {
+				mergeFieldErrors(m, subErrs)
+				return
+			}
+		}
+	}
 	errs[field] = append(errs[field], err)
 }
 
+// mergeFieldErrors recursively merges mergeErrs into errs. Nested error maps
+// found on the same field on both sides are merged key by key while flat
+// errors are appended.
 func mergeFieldErrors(errs map[string][]interface{}, mergeErrs map[string][]interface{}) {
-	// TODO recursive merge
 	for field, values := range mergeErrs {
-		errs[field] = append(errs[field], values...)
+		for _, value := range values {
+			addFieldError(errs, field, value)
+		}
+	}
+}
+
+// fieldValidation holds the input and the result of a field validator call.
+type fieldValidation struct {
+	field     string
+	validator FieldValidator
+	value     interface{}
+	err       error
+}
+
+// validateFields runs the validations in sequence, storing the normalized
+// value or the error in place. It stops on the first FatalError.
+func validateFields(ctx context.Context, validations []fieldValidation) {
+	for i := range validations {
+		fv := &validations[i]
+		fv.value, fv.err = ValidateField(ctx, fv.validator, fv.value)
+		if isFatalError(fv.err) {
+			return
+		}
+	}
+}
+
+// validateFieldsParallel runs the validations using a bounded pool of
+// workers, storing the normalized value or the error in place.
+func validateFieldsParallel(ctx context.Context, validations []fieldValidation) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(validations) {
+		workers = len(validations)
+	}
+	if workers <= 1 {
+		validateFields(ctx, validations)
+		return
+	}
+	jobs := make(chan *fieldValidation)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for fv := range jobs {
+				fv.value, fv.err = ValidateField(ctx, fv.validator, fv.value)
+			}
+		}()
+	}
+	for i := range validations {
+		jobs <- &validations[i]
 	}
+	close(jobs)
+	wg.Wait()
 }
@@ -0,0 +1,40 @@
+package schema_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func benchmarkSchemaValidate(b *testing.B, parallel bool) {
+	fields := schema.Fields{}
+	changes := map[string]interface{}{}
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("field%d", i)
+		fields[name] = schema.Field{
+			Validator: &schema.String{Regexp: `^[a-z]+-[0-9]+(-[a-z0-9]+)*$`},
+		}
+		changes[name] = fmt.Sprintf("value-%d-abcdefghijklmnopqrstuvwxyz", i)
+	}
+	s := schema.Schema{Fields: fields, ParallelValidation: parallel}
+	if err := s.Compile(nil); err != nil {
+		b.Fatal(err)
+	}
+	base := map[string]interface{}{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, errs := s.Validate(changes, base); len(errs) > 0 {
+			b.Fatal(errs)
+		}
+	}
+}
+
+func BenchmarkSchemaValidateSerial(b *testing.B) {
+	benchmarkSchemaValidate(b, false)
+}
+
+func BenchmarkSchemaValidateParallel(b *testing.B) {
+	benchmarkSchemaValidate(b, true)
+}
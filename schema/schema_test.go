@@ -1,9 +1,18 @@
 package schema_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rs/rest-layer/schema"
+	"github.com/rs/rest-layer/schema/query"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,7 +70,7 @@ func TestSchemaValidator(t *testing.T) {
 			Name:   `MinLen=2,Validate(map[string]interface{}{"foo":true})`,
 			Schema: minLenSchema,
 			Change: map[string]interface{}{"foo": true},
-			Errors: map[string][]interface{}{"": []interface{}{"has fewer properties than 2"}},
+			Errors: map[string][]interface{}{"": []interface{}{schema.ValidationError{Code: schema.CodeLength, Message: "has fewer properties than 2"}}},
 		},
 		{
 			Name:   `MaxLen=2,Validate(map[string]interface{}{"foo":true,"bar":false})`,
@@ -73,7 +82,7 @@ func TestSchemaValidator(t *testing.T) {
 			Name:   `MaxLen=2,Validate(map[string]interface{}{"foo":true,"bar":true,"baz":false})`,
 			Schema: maxLenSchema,
 			Change: map[string]interface{}{"foo": true, "bar": true, "baz": false},
-			Errors: map[string][]interface{}{"": []interface{}{"has more properties than 2"}},
+			Errors: map[string][]interface{}{"": []interface{}{schema.ValidationError{Code: schema.CodeLength, Message: "has more properties than 2"}}},
 		},
 	}
 
@@ -93,3 +102,2575 @@ func TestSchemaValidator(t *testing.T) {
 		})
 	}
 }
+
+// fakePredicate implements schema.Predicate with a fixed match result.
+type fakePredicate bool
+
+func (p fakePredicate) Match(payload map[string]interface{}) bool {
+	return bool(p)
+}
+
+func (p fakePredicate) Prepare(v schema.Validator) error {
+	return nil
+}
+
+func TestSchemaValidateMergeNestedErrors(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"sub": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"a": {Required: true, Validator: &schema.String{}},
+						"b": {Dependency: fakePredicate(false), Validator: &schema.String{}},
+					},
+				},
+			},
+			"c": {Dependency: fakePredicate(false), Validator: &schema.Integer{}},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	_, errs := s.Validate(map[string]interface{}{
+		"sub": map[string]interface{}{"b": "foo"},
+		"c":   "bar",
+	}, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{
+		"sub": {
+			map[string][]interface{}{
+				"a": {schema.ValidationError{Code: schema.CodeRequired, Message: "required", Field: "a"}},
+				"b": {schema.ValidationError{Code: schema.CodeDependency, Message: "does not match dependency: false", Field: "b"}},
+			},
+		},
+		"c": {
+			schema.ValidationError{Code: schema.CodeDependency, Message: "does not match dependency: false", Field: "c"},
+			schema.ValidationError{Code: schema.CodeValidator, Message: "not an integer", Field: "c"},
+		},
+	}, errs)
+}
+
+func TestValidationErrorString(t *testing.T) {
+	err := schema.ValidationError{Code: schema.CodeRequired, Message: "required", Field: "foo"}
+	assert.EqualError(t, err, "required")
+	assert.Equal(t, "required", err.String())
+	b, jerr := json.Marshal(map[string][]interface{}{"foo": {err}})
+	assert.NoError(t, jerr)
+	assert.Equal(t, `{"foo":["required"]}`, string(b))
+}
+
+func TestFlattenErrors(t *testing.T) {
+	contact := &schema.Schema{
+		Fields: schema.Fields{
+			"email": {Validator: &schema.String{MinLen: 3}},
+		},
+	}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"name": {Required: true},
+			"address": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"zip": {Validator: &schema.Integer{}},
+						"geo": {
+							Schema: &schema.Schema{
+								Fields: schema.Fields{
+									"lat": {Validator: &schema.Float{}},
+								},
+							},
+						},
+					},
+				},
+			},
+			"contacts": {
+				Validator: &schema.Array{
+					Values: schema.Field{Validator: &schema.Object{Schema: contact}},
+				},
+			},
+			"tags": {
+				Validator: &schema.Array{
+					Values: schema.Field{Validator: &schema.String{}},
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	_, errs := s.Validate(map[string]interface{}{
+		"address": map[string]interface{}{
+			"zip": "abc",
+			"geo": map[string]interface{}{"lat": "north"},
+		},
+		"contacts": []interface{}{
+			map[string]interface{}{"email": "john@example.com"},
+			map[string]interface{}{"email": "x"},
+		},
+		"tags": []interface{}{"a", 1},
+	}, map[string]interface{}{})
+	flat := schema.FlattenErrors(errs)
+
+	assert.Equal(t, map[string][]interface{}{
+		"name":             {schema.ValidationError{Code: schema.CodeRequired, Message: "required", Field: "name"}},
+		"address.zip":      {schema.ValidationError{Code: schema.CodeValidator, Message: "not an integer", Field: "zip"}},
+		"address.geo.lat":  {schema.ValidationError{Code: schema.CodeValidator, Message: "not a float", Field: "lat"}},
+		"contacts":         {schema.ValidationError{Code: schema.CodeValidator, Message: "invalid value at #2: email is [is shorter than 3 characters (got 1)]", Field: "contacts"}},
+		"contacts.1.email": {schema.ValidationError{Code: schema.CodeValidator, Message: "is shorter than 3 characters (got 1)", Field: "email"}},
+		"tags":             {schema.ValidationError{Code: schema.CodeValidator, Message: "invalid value at #2: not a string", Field: "tags"}},
+		"tags.1":           {schema.ValidationError{Code: schema.CodeValidator, Message: "not a string", Field: "1"}},
+	}, flat)
+
+	// Array item errors are reported keyed by index next to the summary.
+	b, err := json.Marshal(errs["contacts"])
+	assert.NoError(t, err)
+	assert.Equal(t, `["invalid value at #2: email is [is shorter than 3 characters (got 1)]",{"1":[{"email":["is shorter than 3 characters (got 1)"]}]}]`, string(b))
+}
+
+func TestSchemaValidateRequiredWhen(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"type": {
+				Required:   true,
+				Filterable: true,
+				Validator:  &schema.String{Allowed: []string{"company", "person"}},
+			},
+			"vat_number": {
+				RequiredWhen: func(doc map[string]interface{}) bool {
+					return doc["type"] == "company"
+				},
+				Dependency: query.MustParsePredicate(`{type: "company"}`),
+				Validator:  &schema.String{},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	cases := []struct {
+		Name         string
+		Base, Change map[string]interface{}
+		Errors       map[string][]interface{}
+	}{
+		{
+			Name:   "company with vat_number",
+			Change: map[string]interface{}{"type": "company", "vat_number": "FR123"},
+			Errors: map[string][]interface{}{},
+		},
+		{
+			Name:   "company without vat_number",
+			Change: map[string]interface{}{"type": "company"},
+			Errors: map[string][]interface{}{
+				"vat_number": {schema.ValidationError{Code: schema.CodeRequired, Message: "required", Field: "vat_number"}},
+			},
+		},
+		{
+			Name:   "company with vat_number in base",
+			Base:   map[string]interface{}{"vat_number": "FR123"},
+			Change: map[string]interface{}{"type": "company"},
+			Errors: map[string][]interface{}{},
+		},
+		{
+			Name:   "person without vat_number",
+			Change: map[string]interface{}{"type": "person"},
+			Errors: map[string][]interface{}{},
+		},
+		{
+			Name:   "person with vat_number",
+			Change: map[string]interface{}{"type": "person", "vat_number": "FR123"},
+			Errors: map[string][]interface{}{
+				"vat_number": {schema.ValidationError{Code: schema.CodeDependency, Message: `does not match dependency: {type: "company"}`, Field: "vat_number"}},
+			},
+		},
+	}
+	for i := range cases {
+		tc := cases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			_, errs := s.Validate(tc.Change, tc.Base)
+			assert.Equal(t, tc.Errors, errs)
+		})
+	}
+}
+
+func TestSchemaValidateExcludes(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			// email and phone are mutually exclusive.
+			"email": {Excludes: []string{"phone"}, Validator: &schema.String{}},
+			"phone": {Excludes: []string{"email"}, Validator: &schema.String{}},
+			"name":  {},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	cases := []struct {
+		name          string
+		changes, base map[string]interface{}
+		errs          map[string][]interface{}
+	}{
+		{
+			name:    "Email",
+			changes: map[string]interface{}{"email": "a@b.c"},
+			errs:    map[string][]interface{}{},
+		},
+		{
+			name:    "Both",
+			changes: map[string]interface{}{"email": "a@b.c", "phone": "123"},
+			errs: map[string][]interface{}{
+				"email": {schema.ValidationError{Code: schema.CodeDependency, Message: "not allowed with phone", Field: "email"}},
+				"phone": {schema.ValidationError{Code: schema.CodeDependency, Message: "not allowed with email", Field: "phone"}},
+			},
+		},
+		{
+			name:    "AddToExisting",
+			changes: map[string]interface{}{"phone": "123"},
+			base:    map[string]interface{}{"email": "a@b.c"},
+			errs: map[string][]interface{}{
+				"email": {schema.ValidationError{Code: schema.CodeDependency, Message: "not allowed with phone", Field: "email"}},
+				"phone": {schema.ValidationError{Code: schema.CodeDependency, Message: "not allowed with email", Field: "phone"}},
+			},
+		},
+		{
+			name:    "Replace",
+			changes: map[string]interface{}{"phone": "123", "email": schema.Tombstone},
+			base:    map[string]interface{}{"email": "a@b.c"},
+			errs:    map[string][]interface{}{},
+		},
+		{
+			name:    "Unchanged",
+			changes: map[string]interface{}{"name": "John"},
+			base:    map[string]interface{}{"email": "a@b.c", "phone": "123"},
+			errs:    map[string][]interface{}{},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			base := tc.base
+			if base == nil {
+				base = map[string]interface{}{}
+			}
+			_, errs := s.Validate(tc.changes, base)
+			assert.Equal(t, tc.errs, errs)
+		})
+	}
+}
+
+func TestSchemaValidateExcludesValue(t *testing.T) {
+	// A dependency with $exists excludes a field based on another field's
+	// value.
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"type":       {Filterable: true, Validator: &schema.String{}},
+			"vat_number": {Filterable: true, Validator: &schema.String{}},
+			"birth_date": {
+				Dependency: query.MustParsePredicate(`{$or: [{type: {$ne: "company"}}, {vat_number: {$exists: false}}]}`),
+				Validator:  &schema.String{},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	_, errs := s.Validate(map[string]interface{}{"type": "company", "vat_number": "FR1", "birth_date": "2000-01-01"}, map[string]interface{}{})
+	if assert.Len(t, errs["birth_date"], 1) {
+		assert.Equal(t, schema.CodeDependency, errs["birth_date"][0].(schema.ValidationError).Code)
+	}
+	_, errs = s.Validate(map[string]interface{}{"type": "person", "vat_number": "FR1", "birth_date": "2000-01-01"}, map[string]interface{}{})
+	assert.Len(t, errs, 0)
+	_, errs = s.Validate(map[string]interface{}{"type": "company", "birth_date": "2000-01-01"}, map[string]interface{}{})
+	assert.Len(t, errs, 0)
+}
+
+func TestSchemaCompileExcludes(t *testing.T) {
+	s := schema.Schema{Fields: schema.Fields{
+		"a": {Excludes: []string{"b"}},
+	}}
+	assert.EqualError(t, s.Compile(nil), "a: excluded field b not found")
+	s = schema.Schema{Fields: schema.Fields{
+		"a": {Excludes: []string{"b"}},
+		"b": {Required: true},
+	}}
+	assert.EqualError(t, s.Compile(nil), "a: excluded field b is required")
+}
+
+func TestSchemaValidateParallel(t *testing.T) {
+	fields := schema.Fields{}
+	changes := map[string]interface{}{}
+	expect := map[string]interface{}{}
+	expectErrs := map[string][]interface{}{}
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("field%d", i)
+		fields[name] = schema.Field{Validator: &schema.Integer{}}
+		if i%10 == 0 {
+			changes[name] = "invalid"
+			expectErrs[name] = []interface{}{schema.ValidationError{Code: schema.CodeValidator, Message: "not an integer", Field: name}}
+		} else {
+			changes[name] = float64(i)
+			expect[name] = i
+		}
+	}
+	s := schema.Schema{Fields: fields, ParallelValidation: true}
+	assert.NoError(t, s.Compile(nil))
+
+	doc, errs := s.Validate(changes, map[string]interface{}{})
+	assert.Equal(t, expectErrs, errs)
+	for name, value := range expect {
+		assert.Equal(t, value, doc[name], name)
+	}
+}
+
+func TestSchemaPrepareMergePatch(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"name":  {Validator: &schema.String{}},
+			"email": {Validator: &schema.String{}},
+			"updated": {
+				OnUpdate: func(ctx context.Context, value interface{}) interface{} {
+					return "now"
+				},
+			},
+			"address": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"street": {Validator: &schema.String{}},
+						"city":   {Validator: &schema.String{}},
+					},
+				},
+			},
+			"meta": {Validator: &schema.Dict{}},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	original := map[string]interface{}{
+		"name":    "John",
+		"email":   "john@example.com",
+		"updated": "before",
+		"address": map[string]interface{}{"street": "Main St", "city": "Paris"},
+		"meta":    map[string]interface{}{"a": "1", "b": "2"},
+	}
+	payload := map[string]interface{}{
+		"email":   nil,
+		"address": map[string]interface{}{"city": "Lyon", "street": nil},
+		"meta":    map[string]interface{}{"a": nil, "c": "3"},
+	}
+	changes, base := s.PrepareMergePatch(context.Background(), payload, &original)
+	doc, errs := s.Validate(changes, base)
+	assert.Len(t, errs, 0)
+	assert.Equal(t, map[string]interface{}{
+		"name":    "John",
+		"updated": "now",
+		"address": map[string]interface{}{"city": "Lyon"},
+		"meta":    map[string]interface{}{"b": "2", "c": "3"},
+	}, doc)
+}
+
+func TestSchemaValidateWithWarnings(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"name":  {Validator: &schema.String{}},
+			"login": {Deprecated: true, Validator: &schema.String{}},
+			"sub": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"old": {Deprecated: true},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	changes := map[string]interface{}{
+		"name":  "John",
+		"login": "john",
+		"sub":   map[string]interface{}{"old": true},
+	}
+	doc, errs, warnings := s.ValidateWithWarnings(changes, map[string]interface{}{})
+	assert.Len(t, errs, 0)
+	assert.Equal(t, changes, doc)
+	assert.Equal(t, map[string][]interface{}{
+		"login": {schema.ValidationError{Code: schema.CodeDeprecated, Message: "deprecated", Field: "login"}},
+		"sub": {map[string][]interface{}{
+			"old": {schema.ValidationError{Code: schema.CodeDeprecated, Message: "deprecated", Field: "old"}},
+		}},
+	}, warnings)
+
+	// Validate ignores warnings.
+	doc, errs = s.Validate(changes, map[string]interface{}{})
+	assert.Len(t, errs, 0)
+	assert.Equal(t, changes, doc)
+
+	// Deprecated fields present in base only are not reported.
+	_, _, warnings = s.ValidateWithWarnings(map[string]interface{}{"name": "John"}, map[string]interface{}{"login": "john"})
+	assert.Len(t, warnings, 0)
+
+	s.Fields["name"] = schema.Field{Deprecated: true, DeprecationMessage: "use login instead"}
+	_, _, warnings = s.ValidateWithWarningsCtx(context.Background(), map[string]interface{}{"name": "John"}, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{
+		"name": {schema.ValidationError{Code: schema.CodeDeprecated, Message: "use login instead", Field: "name"}},
+	}, warnings)
+}
+
+type tenantKey struct{}
+
+func TestSchemaPrepareDefaultFunc(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"tenant": {
+				DefaultFunc: func(ctx context.Context) interface{} {
+					return ctx.Value(tenantKey{})
+				},
+			},
+			"name": {Default: "anonymous"},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+	t.Run("New", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{}, nil, false)
+		assert.Equal(t, map[string]interface{}{}, changes)
+		assert.Equal(t, map[string]interface{}{"tenant": "acme", "name": "anonymous"}, base)
+	})
+	t.Run("NewWithNil", func(t *testing.T) {
+		_, base := s.Prepare(ctx, map[string]interface{}{"tenant": nil}, nil, false)
+		assert.Equal(t, "acme", base["tenant"])
+	})
+	t.Run("NewWithValue", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"tenant": "other"}, nil, false)
+		assert.Equal(t, map[string]interface{}{"tenant": "other"}, changes)
+		assert.Equal(t, map[string]interface{}{"name": "anonymous"}, base)
+	})
+	t.Run("Replace", func(t *testing.T) {
+		original := map[string]interface{}{"tenant": "old", "name": "John"}
+		changes, _ := s.Prepare(ctx, map[string]interface{}{}, &original, true)
+		assert.Equal(t, map[string]interface{}{"tenant": "acme", "name": "anonymous"}, changes)
+	})
+	t.Run("Update", func(t *testing.T) {
+		original := map[string]interface{}{"tenant": "old"}
+		changes, base := s.Prepare(ctx, map[string]interface{}{}, &original, false)
+		assert.Equal(t, map[string]interface{}{}, changes)
+		assert.Equal(t, map[string]interface{}{"tenant": "old"}, base)
+	})
+}
+
+func TestSchemaPrepareDefaultCopy(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"settings": {Default: map[string]interface{}{"tags": []interface{}{"a"}}},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	_, base := s.Prepare(context.Background(), map[string]interface{}{}, nil, false)
+	settings := base["settings"].(map[string]interface{})
+	settings["tags"].([]interface{})[0] = "b"
+	settings["new"] = true
+
+	_, base = s.Prepare(context.Background(), map[string]interface{}{}, nil, false)
+	assert.Equal(t, map[string]interface{}{"tags": []interface{}{"a"}}, base["settings"])
+}
+
+func TestSchemaCompileDefaultFunc(t *testing.T) {
+	s := schema.Schema{Fields: schema.Fields{
+		"expires": {
+			Default:     "never",
+			DefaultFunc: func(ctx context.Context) interface{} { return time.Now() },
+		},
+	}}
+	assert.EqualError(t, s.Compile(nil), "expires: default and default func can't be both set")
+}
+
+func TestSchemaCompileDefault(t *testing.T) {
+	cases := []struct {
+		name  string
+		field schema.Field
+		err   string
+	}{
+		{"Valid", schema.Field{Default: 42, Validator: &schema.Integer{}}, ""},
+		{"Invalid", schema.Field{Default: "abc", Validator: &schema.Integer{}}, "age: invalid default: not an integer"},
+		{"NoDefault", schema.Field{Validator: &schema.Integer{}}, ""},
+		{"DefaultFunc", schema.Field{DefaultFunc: func(ctx context.Context) interface{} { return "abc" }, Validator: &schema.Integer{}}, ""},
+		{"ValidArray", schema.Field{Default: []interface{}{1, 2}, Validator: &schema.Array{Values: schema.Field{Validator: &schema.Integer{}}}}, ""},
+		{"InvalidArray", schema.Field{Default: []interface{}{"x"}, Validator: &schema.Array{Values: schema.Field{Validator: &schema.Integer{}}}}, "age: invalid default: invalid value at #1: not an integer"},
+		{"InvalidDict", schema.Field{Default: map[string]interface{}{"a": "x"}, Validator: &schema.Dict{Values: schema.Field{Validator: &schema.Integer{}}}}, "age: invalid default: invalid value for key `a': not an integer"},
+		{"InvalidObject", schema.Field{Default: map[string]interface{}{"b": 1}, Validator: &schema.Object{Schema: &schema.Schema{Fields: schema.Fields{"a": {}}}}}, "age: invalid default: b is [invalid field]"},
+		{"EmailCheckMX", schema.Field{Default: "john@domain.invalid", Validator: &schema.Email{CheckMX: true}}, ""},
+		{"InvalidEmail", schema.Field{Default: "john", Validator: &schema.Email{}}, "age: invalid default: malformed email"},
+		{"Reference", schema.Field{Default: []interface{}{"x"}, Validator: &schema.Array{Values: schema.Field{Validator: &schema.Reference{Path: "foo"}}}}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := schema.Schema{Fields: schema.Fields{"age": tc.field}}
+			err := s.Compile(fakeReferenceChecker{"foo": {IDs: []interface{}{"a"}, SchemaValidator: &schema.Schema{}}})
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestSchemaPrepareOnDelete(t *testing.T) {
+	var deleted []interface{}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"name": {},
+			"avatar": {
+				OnDelete: func(ctx context.Context, oldValue interface{}) error {
+					deleted = append(deleted, oldValue)
+					return nil
+				},
+			},
+			"locked": {
+				OnDelete: func(ctx context.Context, oldValue interface{}) error {
+					return errors.New("cannot be removed")
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.Background()
+
+	t.Run("Replace", func(t *testing.T) {
+		deleted = nil
+		original := map[string]interface{}{"name": "John", "avatar": "a.png"}
+		changes, base := s.Prepare(ctx, map[string]interface{}{"name": "John"}, &original, true)
+		assert.Equal(t, []interface{}{"a.png"}, deleted)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, map[string]interface{}{"name": "John"}, doc)
+	})
+	t.Run("ReplaceKept", func(t *testing.T) {
+		deleted = nil
+		original := map[string]interface{}{"name": "John", "avatar": "a.png"}
+		s.Prepare(ctx, map[string]interface{}{"avatar": "b.png"}, &original, true)
+		assert.Len(t, deleted, 0)
+	})
+	t.Run("Update", func(t *testing.T) {
+		deleted = nil
+		original := map[string]interface{}{"name": "John", "avatar": "a.png"}
+		s.Prepare(ctx, map[string]interface{}{"name": "Jane"}, &original, false)
+		assert.Len(t, deleted, 0)
+	})
+	t.Run("MergePatch", func(t *testing.T) {
+		deleted = nil
+		original := map[string]interface{}{"name": "John", "avatar": "a.png"}
+		s.PrepareMergePatch(ctx, map[string]interface{}{"avatar": nil}, &original)
+		assert.Equal(t, []interface{}{"a.png"}, deleted)
+	})
+	t.Run("Error", func(t *testing.T) {
+		original := map[string]interface{}{"name": "John", "locked": true}
+		changes, base := s.Prepare(ctx, map[string]interface{}{"name": "John"}, &original, true)
+		_, errs := s.Validate(changes, base)
+		assert.Equal(t, map[string][]interface{}{
+			"locked": {schema.ValidationError{Code: schema.CodeDelete, Message: "cannot be removed", Field: "locked"}},
+		}, errs)
+	})
+}
+
+// slowSerializer is a FieldSerializerCtx waiting for delay or ctx to be done.
+type slowSerializer struct {
+	schema.String
+	delay time.Duration
+}
+
+func (s slowSerializer) Serialize(value interface{}) (interface{}, error) {
+	return nil, errors.New("Serialize should not be called")
+}
+
+func (s slowSerializer) SerializeCtx(ctx context.Context, value interface{}) (interface{}, error) {
+	select {
+	case <-time.After(s.delay):
+		return fmt.Sprintf("<%v>", value), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestSchemaSerialize(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"name":     {},
+			"password": schema.PasswordField,
+			"display":  {Validator: &slowSerializer{}},
+			"sub": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"secret":  {Hidden: true},
+						"display": {Validator: &slowSerializer{}},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	payload := map[string]interface{}{
+		"name":     "John",
+		"password": []byte("hash"),
+		"display":  "a",
+		"sub":      map[string]interface{}{"secret": "s", "display": "b"},
+		"unknown":  1,
+	}
+	assert.NoError(t, s.Serialize(payload))
+	assert.Equal(t, map[string]interface{}{
+		"name":    "John",
+		"display": "<a>",
+		"sub":     map[string]interface{}{"display": "<b>"},
+		"unknown": 1,
+	}, payload)
+}
+
+type roleKey struct{}
+
+func TestSchemaSerializeVisibleIf(t *testing.T) {
+	hasRole := func(role string) func(ctx context.Context) bool {
+		return func(ctx context.Context) bool {
+			r, _ := ctx.Value(roleKey{}).(string)
+			return r == role || r == "admin"
+		}
+	}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"name":  {},
+			"notes": {Hidden: true, VisibleIf: hasRole("support")},
+			"sub": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"audit":  {Hidden: true, VisibleIf: hasRole("admin")},
+						"secret": {Hidden: true},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	stored := func() map[string]interface{} {
+		return map[string]interface{}{
+			"name":  "John",
+			"notes": "n",
+			"sub":   map[string]interface{}{"audit": "a", "secret": "s"},
+		}
+	}
+
+	payload := stored()
+	assert.NoError(t, s.Serialize(payload))
+	assert.Equal(t, map[string]interface{}{"name": "John", "sub": map[string]interface{}{}}, payload)
+
+	payload = stored()
+	assert.NoError(t, s.SerializeCtx(context.WithValue(context.Background(), roleKey{}, "support"), payload))
+	assert.Equal(t, map[string]interface{}{"name": "John", "notes": "n", "sub": map[string]interface{}{}}, payload)
+
+	payload = stored()
+	assert.NoError(t, s.SerializeCtx(context.WithValue(context.Background(), roleKey{}, "admin"), payload))
+	assert.Equal(t, map[string]interface{}{"name": "John", "notes": "n", "sub": map[string]interface{}{"audit": "a"}}, payload)
+}
+
+func TestSchemaReadWriteIf(t *testing.T) {
+	isAdmin := func(ctx context.Context) bool {
+		return ctx.Value(roleKey{}) == "admin"
+	}
+	isAuthenticated := func(ctx context.Context) bool {
+		return ctx.Value(roleKey{}) != nil
+	}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"name":  {},
+			"email": {ReadIf: isAuthenticated},
+			"role":  {WriteIf: isAdmin, Default: "user"},
+			"account": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"balance":      {},
+						"credit_limit": {WriteIf: isAdmin, ReadIf: isAuthenticated},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	user := context.WithValue(context.Background(), roleKey{}, "user")
+	admin := context.WithValue(context.Background(), roleKey{}, "admin")
+	forbidden := func(field string) schema.ValidationError {
+		return schema.ValidationError{Code: schema.CodeForbidden, Message: "forbidden field", Field: field}
+	}
+
+	t.Run("CreateForbidden", func(t *testing.T) {
+		changes, base := s.Prepare(user, map[string]interface{}{
+			"name":    "John",
+			"role":    "admin",
+			"account": map[string]interface{}{"credit_limit": 1000},
+		}, nil, false)
+		_, errs := s.ValidateCtx(user, changes, base)
+		assert.Equal(t, map[string][]interface{}{
+			"role":    {forbidden("role")},
+			"account": {map[string][]interface{}{"credit_limit": {forbidden("credit_limit")}}},
+		}, errs)
+	})
+	t.Run("CreateDefault", func(t *testing.T) {
+		changes, base := s.Prepare(user, map[string]interface{}{"name": "John"}, nil, false)
+		doc, errs := s.ValidateCtx(user, changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "user", doc["role"])
+	})
+	t.Run("CreateAllowed", func(t *testing.T) {
+		changes, base := s.Prepare(admin, map[string]interface{}{
+			"name":    "John",
+			"role":    "admin",
+			"account": map[string]interface{}{"credit_limit": 1000},
+		}, nil, false)
+		doc, errs := s.ValidateCtx(admin, changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "admin", doc["role"])
+	})
+	original := map[string]interface{}{
+		"name":    "John",
+		"email":   "john@example.com",
+		"role":    "admin",
+		"account": map[string]interface{}{"balance": 10, "credit_limit": 1000},
+	}
+	t.Run("UpdateUnchanged", func(t *testing.T) {
+		changes, base := s.Prepare(user, map[string]interface{}{
+			"name":    "Jane",
+			"role":    "admin",
+			"account": map[string]interface{}{"balance": 20, "credit_limit": 1000},
+		}, &original, false)
+		doc, errs := s.ValidateCtx(user, changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "Jane", doc["name"])
+	})
+	t.Run("UpdateForbidden", func(t *testing.T) {
+		changes, base := s.Prepare(user, map[string]interface{}{"role": "user"}, &original, false)
+		doc, errs := s.ValidateCtx(user, changes, base)
+		assert.Equal(t, map[string][]interface{}{"role": {forbidden("role")}}, errs)
+		assert.Equal(t, "admin", doc["role"])
+	})
+	t.Run("MergePatchRemove", func(t *testing.T) {
+		changes, base := s.PrepareMergePatch(user, map[string]interface{}{"role": nil}, &original)
+		doc, errs := s.ValidateCtx(user, changes, base)
+		assert.Equal(t, map[string][]interface{}{"role": {forbidden("role")}}, errs)
+		assert.Equal(t, "admin", doc["role"])
+	})
+	t.Run("ReplaceOmitted", func(t *testing.T) {
+		changes, base := s.Prepare(user, map[string]interface{}{
+			"name":    "Jane",
+			"email":   "jane@example.com",
+			"account": map[string]interface{}{"balance": 20},
+		}, &original, true)
+		doc, errs := s.ValidateCtx(user, changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, map[string]interface{}{
+			"name":    "Jane",
+			"email":   "jane@example.com",
+			"role":    "admin",
+			"account": map[string]interface{}{"balance": 20, "credit_limit": 1000},
+		}, doc)
+	})
+	t.Run("Serialize", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"name":    "John",
+			"email":   "john@example.com",
+			"account": map[string]interface{}{"balance": 10, "credit_limit": 1000},
+		}
+		assert.NoError(t, s.SerializeCtx(context.Background(), payload))
+		assert.Equal(t, map[string]interface{}{
+			"name":    "John",
+			"account": map[string]interface{}{"balance": 10},
+		}, payload)
+	})
+}
+
+func TestSchemaSerializeOnRead(t *testing.T) {
+	normalizePhone := func(ctx context.Context, value interface{}) interface{} {
+		if s, ok := value.(string); ok {
+			return strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(s)
+		}
+		return value
+	}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"phone": {OnRead: normalizePhone},
+			"secret": {
+				Hidden: true,
+				OnRead: func(ctx context.Context, value interface{}) interface{} {
+					t.Error("OnRead called on hidden field")
+					return value
+				},
+			},
+			"sub": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"phone": {OnRead: normalizePhone, Validator: &slowSerializer{}},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	payload := map[string]interface{}{
+		"phone":  "+1 (555) 010-0000",
+		"secret": "s",
+		"sub":    map[string]interface{}{"phone": "+33 1 23 45 67 89"},
+	}
+	assert.NoError(t, s.Serialize(payload))
+	assert.Equal(t, map[string]interface{}{
+		"phone": "+15550100000",
+		"sub":   map[string]interface{}{"phone": "<+33123456789>"},
+	}, payload)
+}
+
+func TestSchemaSerializeCtxCancel(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"display": {Validator: &slowSerializer{delay: time.Minute}},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := s.SerializeCtx(ctx, map[string]interface{}{"display": "a"})
+	assert.EqualError(t, err, "display: context deadline exceeded")
+	assert.True(t, time.Since(start) < time.Second, "serialization not aborted")
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = s.SerializeCtx(ctx, map[string]interface{}{"display": "a"})
+	assert.EqualError(t, err, "context canceled")
+}
+
+func TestSchemaPrepareHookErr(t *testing.T) {
+	errDown := errors.New("service unavailable")
+	enrich := func(ctx context.Context, value interface{}) (interface{}, error) {
+		if value == "fail" {
+			return nil, errDown
+		}
+		return fmt.Sprintf("%v!", value), nil
+	}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"name": {
+				OnInitErr:   enrich,
+				OnUpdateErr: enrich,
+				// Not called as OnInitErr takes precedence.
+				OnInit: func(ctx context.Context, value interface{}) interface{} {
+					panic("OnInit called")
+				},
+			},
+			"created": {
+				ReadOnly: true,
+				OnInitErr: func(ctx context.Context, value interface{}) (interface{}, error) {
+					return nil, errDown
+				},
+			},
+			"legacy": {
+				OnInit: func(ctx context.Context, value interface{}) interface{} {
+					return "legacy"
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.Background()
+
+	t.Run("Init", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"name": "John"}, nil, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Equal(t, map[string][]interface{}{
+			"created": {schema.ValidationError{Code: schema.CodeHook, Message: "service unavailable", Field: "created"}},
+		}, errs)
+		assert.Equal(t, "John!", doc["name"])
+		assert.Equal(t, "legacy", doc["legacy"])
+	})
+	t.Run("InitError", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"name": "fail"}, nil, false)
+		_, errs := s.Validate(changes, base)
+		assert.Equal(t, []interface{}{
+			schema.ValidationError{Code: schema.CodeHook, Message: "service unavailable", Field: "name"},
+		}, errs["name"])
+	})
+	t.Run("Update", func(t *testing.T) {
+		original := map[string]interface{}{"name": "John!", "created": "now"}
+		changes, base := s.Prepare(ctx, map[string]interface{}{"name": "Jane"}, &original, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "Jane!", doc["name"])
+	})
+	t.Run("UpdateError", func(t *testing.T) {
+		original := map[string]interface{}{"name": "fail", "created": "now"}
+		changes, base := s.Prepare(ctx, map[string]interface{}{}, &original, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Equal(t, map[string][]interface{}{
+			"name": {schema.ValidationError{Code: schema.CodeHook, Message: "service unavailable", Field: "name"}},
+		}, errs)
+		assert.NotContains(t, doc, "name")
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		changes, base := s.Prepare(ctx, map[string]interface{}{"name": "John"}, nil, false)
+		assert.Empty(t, base)
+		_, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 1)
+		for field, values := range errs {
+			assert.Equal(t, []interface{}{
+				schema.ValidationError{Code: schema.CodeHook, Message: context.Canceled.Error(), Field: field},
+			}, values)
+		}
+	})
+}
+
+func TestSchemaPrepareDocHooks(t *testing.T) {
+	displayName := func(ctx context.Context, value interface{}, doc map[string]interface{}) (interface{}, error) {
+		return fmt.Sprintf("%v %v", doc["first_name"], doc["last_name"]), nil
+	}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"first_name": {},
+			"last_name": {
+				OnInit: func(ctx context.Context, value interface{}) interface{} {
+					return strings.ToUpper(value.(string))
+				},
+			},
+			"display_name": {
+				OnInitDoc:   displayName,
+				OnUpdateDoc: displayName,
+			},
+			"meta": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"author": {
+							OnInitDoc: func(ctx context.Context, value interface{}, doc map[string]interface{}) (interface{}, error) {
+								// The snapshot doesn't include the value
+								// returned by the display_name hook.
+								if _, found := doc["display_name"]; found {
+									return nil, errors.New("display_name set")
+								}
+								return doc["first_name"], nil
+							},
+						},
+						"version": {
+							OnUpdateDoc: func(ctx context.Context, value interface{}, doc map[string]interface{}) (interface{}, error) {
+								meta, _ := doc["meta"].(map[string]interface{})
+								if meta["locked"] == true {
+									return nil, errors.New("locked")
+								}
+								return meta["version"].(int) + 1, nil
+							},
+						},
+						"locked": {},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.Background()
+
+	t.Run("Init", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"first_name": "John", "last_name": "Doe"}, nil, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, map[string]interface{}{
+			"first_name":   "John",
+			"last_name":    "DOE",
+			"display_name": "John DOE",
+			"meta":         map[string]interface{}{"author": "John"},
+		}, doc)
+	})
+	t.Run("Update", func(t *testing.T) {
+		original := map[string]interface{}{
+			"first_name":   "John",
+			"last_name":    "Doe",
+			"display_name": "John Doe",
+			"meta":         map[string]interface{}{"author": "John", "version": 1},
+		}
+		changes, base := s.Prepare(ctx, map[string]interface{}{"first_name": "Jane"}, &original, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, map[string]interface{}{
+			"first_name":   "Jane",
+			"last_name":    "Doe",
+			"display_name": "Jane Doe",
+			"meta":         map[string]interface{}{"author": "John", "version": 2},
+		}, doc)
+	})
+	t.Run("UpdateError", func(t *testing.T) {
+		original := map[string]interface{}{
+			"first_name": "John",
+			"meta":       map[string]interface{}{"version": 1},
+		}
+		changes, base := s.Prepare(ctx, map[string]interface{}{"meta": map[string]interface{}{"locked": true}}, &original, false)
+		_, errs := s.Validate(changes, base)
+		assert.Equal(t, map[string][]interface{}{
+			"meta": {map[string][]interface{}{
+				"version": {schema.ValidationError{Code: schema.CodeHook, Message: "locked", Field: "version"}},
+			}},
+		}, errs)
+	})
+}
+
+func TestSchemaCompute(t *testing.T) {
+	fullName := func(ctx context.Context, doc map[string]interface{}) (interface{}, error) {
+		return fmt.Sprintf("%v %v", doc["first"], doc["last"]), nil
+	}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"first":     {},
+			"last":      {},
+			"full_name": {Compute: fullName, Validator: &schema.String{}},
+			"sub": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"first":     {},
+						"last":      {},
+						"full_name": {Compute: fullName},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.Background()
+
+	t.Run("GetField", func(t *testing.T) {
+		assert.NotNil(t, s.GetField("full_name"))
+		assert.NotNil(t, s.GetField("sub.full_name"))
+	})
+	t.Run("Create", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"first": "John", "last": "Doe"}, nil, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.NotContains(t, doc, "full_name")
+	})
+	t.Run("CreateWithValue", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"first": "John", "full_name": "John Doe"}, nil, false)
+		_, errs := s.Validate(changes, base)
+		assert.Equal(t, map[string][]interface{}{
+			"full_name": {schema.ValidationError{Code: schema.CodeReadOnly, Message: "read-only", Field: "full_name"}},
+		}, errs)
+	})
+	t.Run("ReplaceWithComputedValue", func(t *testing.T) {
+		original := map[string]interface{}{"first": "John", "last": "Doe"}
+		payload := map[string]interface{}{"first": "John", "last": "Doe", "full_name": "John Doe"}
+		changes, base := s.Prepare(ctx, payload, &original, true)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.NotContains(t, doc, "full_name")
+	})
+	t.Run("ReplaceWithOtherValue", func(t *testing.T) {
+		original := map[string]interface{}{"first": "John", "last": "Doe"}
+		payload := map[string]interface{}{"first": "John", "last": "Doe", "full_name": "Jane Doe"}
+		changes, base := s.Prepare(ctx, payload, &original, true)
+		_, errs := s.Validate(changes, base)
+		assert.Equal(t, map[string][]interface{}{
+			"full_name": {schema.ValidationError{Code: schema.CodeReadOnly, Message: "read-only", Field: "full_name"}},
+		}, errs)
+	})
+	t.Run("Compute", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"first": "John",
+			"last":  "Doe",
+			"sub":   map[string]interface{}{"first": "Jane", "last": "Roe"},
+		}
+		doc, err := s.Compute(ctx, payload)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"first":     "John",
+			"last":      "Doe",
+			"full_name": "John Doe",
+			"sub":       map[string]interface{}{"first": "Jane", "last": "Roe", "full_name": "Jane Roe"},
+		}, doc)
+		assert.NotContains(t, payload, "full_name", "payload modified")
+	})
+	t.Run("Serialize", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"first": "John",
+			"last":  "Doe",
+			"sub":   map[string]interface{}{"first": "Jane", "last": "Roe"},
+		}
+		assert.NoError(t, s.Serialize(payload))
+		assert.Equal(t, "John Doe", payload["full_name"])
+		assert.Equal(t, "Jane Roe", payload["sub"].(map[string]interface{})["full_name"])
+	})
+	t.Run("SerializeError", func(t *testing.T) {
+		s := schema.Schema{
+			Fields: schema.Fields{
+				"computed": {
+					Compute: func(ctx context.Context, doc map[string]interface{}) (interface{}, error) {
+						return nil, errors.New("failure")
+					},
+				},
+			},
+		}
+		assert.EqualError(t, s.Serialize(map[string]interface{}{}), "computed: failure")
+	})
+}
+
+func TestSchemaCompileCompute(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"computed": {
+				Required: true,
+				Compute: func(ctx context.Context, doc map[string]interface{}) (interface{}, error) {
+					return nil, nil
+				},
+			},
+		},
+	}
+	assert.EqualError(t, s.Compile(nil), "computed: computed field can't be required, filterable or sortable")
+}
+
+func TestSchemaDescribe(t *testing.T) {
+	s := schema.Schema{
+		Description: "A user",
+		MaxLen:      10,
+		Fields: schema.Fields{
+			"id": schema.IDField,
+			"name": {
+				Description: "The name of the user",
+				Required:    true,
+				Validator:   &schema.String{MaxLen: 150},
+			},
+			"age": {
+				Default:   18,
+				Validator: &schema.Integer{Boundaries: &schema.Boundaries{Min: 0, Max: 150}},
+			},
+			"password": schema.PasswordField,
+			"tags": {
+				Validator: &schema.Array{Values: schema.Field{Validator: &schema.String{}}},
+			},
+			"address": {
+				Schema: &schema.Schema{
+					Fields: schema.Fields{
+						"city": {Validator: &schema.String{}},
+					},
+				},
+			},
+			"untyped": {},
+			"login": {
+				Deprecated:         true,
+				DeprecationMessage: "use name instead",
+				Validator:          &schema.String{},
+			},
+		},
+	}
+	assert.Equal(t, map[string]interface{}{
+		"description": "A user",
+		"maxLen":      10,
+		"fields": map[string]interface{}{
+			"id": map[string]interface{}{
+				"type":        "string",
+				"regexp":      "^[0-9a-v]{20}$",
+				"description": "The item's id",
+				"required":    true,
+				"readOnly":    true,
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"maxLen":      150,
+				"description": "The name of the user",
+				"required":    true,
+			},
+			"age": map[string]interface{}{
+				"type":    "integer",
+				"min":     0.0,
+				"max":     150.0,
+				"default": 18,
+			},
+			"tags": map[string]interface{}{
+				"type":   "array",
+				"values": map[string]interface{}{"type": "string"},
+			},
+			"address": map[string]interface{}{
+				"type": "object",
+				"schema": map[string]interface{}{
+					"fields": map[string]interface{}{
+						"city": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+			"untyped": map[string]interface{}{},
+			"login": map[string]interface{}{
+				"type":               "string",
+				"deprecated":         true,
+				"deprecationMessage": "use name instead",
+			},
+		},
+	}, s.Describe())
+}
+
+func TestSchemaValidateArrayItemErrors(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"tags": {
+				Validator: &schema.Array{
+					Values: schema.Field{Validator: &schema.String{MaxLen: 3}},
+				},
+			},
+			"matrix": {
+				Validator: &schema.Array{
+					Values: schema.Field{Validator: &schema.Array{
+						Values: schema.Field{Validator: &schema.Integer{}},
+					}},
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	_, errs := s.Validate(map[string]interface{}{
+		"tags":   []interface{}{"foo", "foobar", "bar", "barbaz"},
+		"matrix": []interface{}{[]interface{}{1, 2}, []interface{}{3, "x"}},
+	}, map[string]interface{}{})
+
+	assert.Equal(t, map[string][]interface{}{
+		"tags": {
+			schema.ValidationError{
+				Code:    schema.CodeValidator,
+				Message: "invalid value at #2: is longer than 3 characters (got 6), invalid value at #4: is longer than 3 characters (got 6)",
+				Field:   "tags",
+			},
+			map[string][]interface{}{
+				"1": {schema.ValidationError{Code: schema.CodeValidator, Message: "is longer than 3 characters (got 6)", Field: "1"}},
+				"3": {schema.ValidationError{Code: schema.CodeValidator, Message: "is longer than 3 characters (got 6)", Field: "3"}},
+			},
+		},
+		"matrix": {
+			schema.ValidationError{
+				Code:    schema.CodeValidator,
+				Message: "invalid value at #2: invalid value at #2: not an integer",
+				Field:   "matrix",
+			},
+			map[string][]interface{}{
+				"1": {
+					schema.ValidationError{Code: schema.CodeValidator, Message: "invalid value at #2: not an integer", Field: "1"},
+					map[string][]interface{}{
+						"1": {schema.ValidationError{Code: schema.CodeValidator, Message: "not an integer", Field: "1"}},
+					},
+				},
+			},
+		},
+	}, errs)
+	assert.Equal(t, []interface{}{
+		schema.ValidationError{Code: schema.CodeValidator, Message: "is longer than 3 characters (got 6)", Field: "1"},
+	}, schema.FlattenErrors(errs)["tags.1"])
+	assert.Equal(t, []interface{}{
+		schema.ValidationError{Code: schema.CodeValidator, Message: "not an integer", Field: "1"},
+	}, schema.FlattenErrors(errs)["matrix.1.1"])
+}
+
+func TestSchemaValidateArrayItemNormalization(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"tags": {
+				Validator: &schema.Array{
+					Values: schema.Field{Validator: &schema.String{TrimSpace: true, MaxLen: 3}},
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	changes := map[string]interface{}{
+		"tags": []interface{}{" foo ", "foobar", "bar ", 1},
+	}
+	doc, errs := s.Validate(changes, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{
+		"tags":   {schema.ValidationError{Code: schema.CodeValidator, Message: "invalid value at #2: is longer than 3 characters (got 6), invalid value at #4: not a string", Field: "tags"}},
+		"tags.1": {schema.ValidationError{Code: schema.CodeValidator, Message: "is longer than 3 characters (got 6)", Field: "1"}},
+		"tags.3": {schema.ValidationError{Code: schema.CodeValidator, Message: "not a string", Field: "3"}},
+	}, schema.FlattenErrors(errs))
+	// Valid items are normalized even though other items are invalid.
+	assert.Equal(t, []interface{}{"foo", "foobar", "bar", 1}, doc["tags"])
+	// The items of changes are left untouched.
+	assert.Equal(t, []interface{}{" foo ", "foobar", "bar ", 1}, changes["tags"])
+
+	// Same with valid items only.
+	changes = map[string]interface{}{"tags": []interface{}{" foo ", "bar "}}
+	doc, errs = s.Validate(changes, map[string]interface{}{})
+	assert.Len(t, errs, 0)
+	assert.Equal(t, []interface{}{"foo", "bar"}, doc["tags"])
+	assert.Equal(t, []interface{}{" foo ", "bar "}, changes["tags"])
+}
+
+func TestSchemaMaxDepth(t *testing.T) {
+	// A self-referencing schema, e.g. a tree of nodes.
+	node := schema.Schema{
+		MaxDepth: 3,
+		Fields: schema.Fields{
+			"name": {Validator: &schema.String{}},
+		},
+	}
+	node.Fields["child"] = schema.Field{Schema: &node}
+
+	// Nested payload within the limit.
+	payload := map[string]interface{}{
+		"name": "a",
+		"child": map[string]interface{}{
+			"name":  "b",
+			"child": map[string]interface{}{"name": "c"},
+		},
+	}
+	changes, base := node.Prepare(context.Background(), payload, nil, false)
+	doc, errs := node.Validate(changes, base)
+	assert.Len(t, errs, 0)
+	assert.Equal(t, payload, doc)
+
+	// Nested payload exceeding the limit.
+	payload = map[string]interface{}{
+		"name": "a",
+		"child": map[string]interface{}{
+			"name": "b",
+			"child": map[string]interface{}{
+				"name":  "c",
+				"child": map[string]interface{}{"name": "d"},
+			},
+		},
+	}
+	changes, base = node.Prepare(context.Background(), payload, nil, false)
+	_, errs = node.Validate(changes, base)
+	assert.Equal(t, map[string][]interface{}{
+		"child": {map[string][]interface{}{
+			"child": {map[string][]interface{}{
+				"child": {schema.ValidationError{
+					Code:    schema.CodeMaxDepth,
+					Message: "max depth exceeded",
+					Field:   "child",
+				}},
+			}},
+		}},
+	}, errs)
+
+	// The default limit stops the recursion on missing sub-documents.
+	node.MaxDepth = 0
+	changes, base = node.Prepare(context.Background(), map[string]interface{}{"name": "a"}, nil, false)
+	doc, errs = node.Validate(changes, base)
+	assert.Len(t, errs, 0)
+	assert.Equal(t, map[string]interface{}{"name": "a"}, doc)
+}
+
+func TestSchemaPrepareTransform(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"email": {
+				Transform: []schema.Transformer{schema.TrimSpace, schema.ToLower},
+				Validator: &schema.String{Regexp: "^[a-z@.]+$"},
+			},
+			"code": {
+				Transform: []schema.Transformer{schema.CollapseWhitespace, schema.ToUpper},
+				Nullable:  true,
+			},
+			"count": {
+				Transform: []schema.Transformer{
+					schema.TrimSpace,
+					schema.TransformerFunc(func(value interface{}) interface{} {
+						if f, ok := value.(float64); ok {
+							return int(f)
+						}
+						return value
+					}),
+				},
+				Validator: &schema.Integer{},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.Background()
+
+	payload := map[string]interface{}{"email": "  John@Example.COM ", "code": "ab \t cd", "count": 2.0}
+	changes, base := s.Prepare(ctx, payload, nil, false)
+	doc, errs := s.Validate(changes, base)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"email": "john@example.com", "code": "AB CD", "count": 2}, doc)
+	// The payload is not changed.
+	assert.Equal(t, "  John@Example.COM ", payload["email"])
+
+	// Updates equal to the stored value once transformed are no-ops.
+	original := doc
+	changes, _ = s.Prepare(ctx, map[string]interface{}{"email": "JOHN@example.com  ", "code": "ab cd"}, &original, false)
+	assert.Empty(t, changes)
+
+	changes, _ = s.Prepare(ctx, map[string]interface{}{"code": nil}, &original, false)
+	assert.Equal(t, map[string]interface{}{"code": nil}, changes)
+}
+
+func TestSchemaPrepareNumbers(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"int":    {Validator: &schema.Integer{Strict: true}},
+			"float":  {Validator: &schema.Float{}},
+			"bool":   {Validator: &schema.Bool{Coerce: true}},
+			"string": {Validator: &schema.String{}},
+			"any":    {},
+			"list":   {Validator: &schema.Array{Values: schema.Field{Validator: &schema.Integer{Strict: true}}}},
+			"dict":   {Validator: &schema.Dict{}},
+			"sub": {Schema: &schema.Schema{Fields: schema.Fields{
+				"int": {Validator: &schema.Integer{Strict: true}},
+				"any": {},
+			}}},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.Background()
+
+	payload := map[string]interface{}{
+		"int":   json.Number("3"),
+		"float": json.Number("1.5"),
+		"any":   json.Number("4"),
+		"list":  []interface{}{json.Number("1"), json.Number("2")},
+		"dict":  map[string]interface{}{"a": []interface{}{json.Number("1")}},
+		"sub":   map[string]interface{}{"int": json.Number("5"), "any": json.Number("6")},
+	}
+	changes, base := s.Prepare(ctx, payload, nil, false)
+	doc, errs := s.Validate(changes, base)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"int":   3,
+		"float": 1.5,
+		"any":   4.0,
+		"list":  []interface{}{1, 2},
+		"dict":  map[string]interface{}{"a": []interface{}{1.0}},
+		"sub":   map[string]interface{}{"int": 5, "any": 6.0},
+	}, doc)
+	// The payload is not changed.
+	assert.Equal(t, json.Number("4"), payload["any"])
+
+	// Numbers are rejected by strict integers when not written as integers,
+	// and by Bool and String fields, whatever their literal.
+	payload = map[string]interface{}{
+		"int":    json.Number("3.0"),
+		"bool":   json.Number("1"),
+		"string": json.Number("1"),
+		"list":   []interface{}{json.Number("1e0")},
+		"sub":    map[string]interface{}{"int": json.Number("5.0")},
+	}
+	changes, base = s.Prepare(ctx, payload, nil, false)
+	_, errs = s.Validate(changes, base)
+	verr := func(field, msg string) schema.ValidationError {
+		return schema.ValidationError{Code: schema.CodeValidator, Message: msg, Field: field}
+	}
+	assert.Equal(t, map[string][]interface{}{
+		"int":    {verr("int", "not an integer")},
+		"bool":   {verr("bool", "not a Boolean")},
+		"string": {verr("string", "not a string")},
+		"list": {
+			verr("list", "invalid value at #1: not an integer"),
+			map[string][]interface{}{"0": {verr("0", "not an integer")}},
+		},
+		"sub": {map[string][]interface{}{"int": {verr("int", "not an integer")}}},
+	}, errs)
+}
+
+// numberLiteral is a custom validator returning the literal of json.Number
+// values.
+type numberLiteral struct{}
+
+func (numberLiteral) AcceptsJSONNumber() bool { return true }
+
+func (numberLiteral) Validate(value interface{}) (interface{}, error) {
+	n, ok := value.(json.Number)
+	if !ok {
+		return nil, errors.New("not a json.Number")
+	}
+	return n.String(), nil
+}
+
+func TestSchemaPrepareNestedStrictNumbers(t *testing.T) {
+	strict := func() schema.Field { return schema.Field{Validator: &schema.Integer{Strict: true}} }
+	var hooked interface{}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"anyOf": {Validator: &schema.AnyOf{&schema.Integer{Strict: true}}},
+			"allOf": {Validator: &schema.AllOf{&schema.Integer{Strict: true}}},
+			"oneOf": {Validator: &schema.OneOf{&schema.Integer{Strict: true}, &schema.String{}}},
+			"list":  {Validator: &schema.Array{Values: schema.Field{Validator: &schema.AnyOf{&schema.Integer{Strict: true}}}}},
+			"dict":  {Validator: &schema.Dict{Values: strict()}},
+			"objects": {Validator: &schema.Array{Values: schema.Field{Validator: &schema.Object{
+				Schema: &schema.Schema{Fields: schema.Fields{"n": strict()}},
+			}}}},
+			"disc": {Validator: &schema.Discriminated{PropertyName: "type", Mapping: map[string]*schema.Schema{
+				"a": {Fields: schema.Fields{"n": strict()}},
+			}}},
+			"custom": {Validator: &numberLiteral{}},
+			"lenient": {
+				Validator: &schema.Integer{},
+				OnInit: func(ctx context.Context, value interface{}) interface{} {
+					hooked = value
+					return value
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.Background()
+	payload := func(n json.Number) map[string]interface{} {
+		return map[string]interface{}{
+			"anyOf":   n,
+			"allOf":   n,
+			"oneOf":   n,
+			"list":    []interface{}{n},
+			"dict":    map[string]interface{}{"a": n},
+			"objects": []interface{}{map[string]interface{}{"n": n}},
+			"disc":    map[string]interface{}{"type": "a", "n": n},
+			"custom":  n,
+			"lenient": n,
+		}
+	}
+
+	changes, base := s.Prepare(ctx, payload("3"), nil, false)
+	doc, errs := s.Validate(changes, base)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"anyOf":   3,
+		"allOf":   3,
+		"oneOf":   3,
+		"list":    []interface{}{3},
+		"dict":    map[string]interface{}{"a": 3},
+		"objects": []interface{}{map[string]interface{}{"n": 3}},
+		"disc":    map[string]interface{}{"type": "a", "n": 3},
+		"custom":  "3",
+		"lenient": 3,
+	}, doc)
+	// Hooks of fields not accepting json.Number get float64 values.
+	assert.Equal(t, 3.0, hooked)
+
+	changes, base = s.Prepare(ctx, payload("3.0"), nil, false)
+	_, errs = s.Validate(changes, base)
+	for _, field := range []string{"anyOf", "allOf", "oneOf", "list", "dict", "objects", "disc"} {
+		assert.Contains(t, errs, field)
+	}
+	assert.NotContains(t, errs, "custom")
+	assert.NotContains(t, errs, "lenient")
+	assert.Len(t, errs, 7)
+}
+
+func TestSchemaPrepareCreateOnly(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"name": {Validator: &schema.String{}},
+			"slug": {CreateOnly: true, Validator: &schema.String{}},
+			"meta": {CreateOnly: true, Schema: &schema.Schema{Fields: schema.Fields{
+				"source": {},
+			}}},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.Background()
+	immutable := func(field string) schema.ValidationError {
+		return schema.ValidationError{Code: schema.CodeImmutable, Message: "immutable", Field: field}
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		payload := map[string]interface{}{"name": "Foo", "slug": "foo", "meta": map[string]interface{}{"source": "api"}}
+		changes, base := s.Prepare(ctx, payload, nil, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, payload, doc)
+	})
+	original := map[string]interface{}{"name": "Foo", "slug": "foo", "meta": map[string]interface{}{"source": "api"}}
+	t.Run("UpdateUnchanged", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"name": "Bar", "slug": "foo"}, &original, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, "Bar", doc["name"])
+	})
+	t.Run("ReplaceUnchanged", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, original, &original, true)
+		_, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+	})
+	t.Run("Update", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"slug": "bar", "meta": map[string]interface{}{"source": "ui"}}, &original, false)
+		_, errs := s.Validate(changes, base)
+		assert.Equal(t, map[string][]interface{}{
+			"slug": {immutable("slug")},
+			"meta": {immutable("meta")},
+		}, errs)
+	})
+	t.Run("ReplaceOmitted", func(t *testing.T) {
+		// Omitted create-only fields keep their stored value.
+		changes, base := s.Prepare(ctx, map[string]interface{}{"name": "Bar"}, &original, true)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, map[string]interface{}{"name": "Bar", "slug": "foo", "meta": map[string]interface{}{"source": "api"}}, doc)
+	})
+	t.Run("ReplaceChanged", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"name": "Foo", "slug": "bar", "meta": map[string]interface{}{"source": "ui"}}, &original, true)
+		_, errs := s.Validate(changes, base)
+		assert.Equal(t, map[string][]interface{}{
+			"slug": {immutable("slug")},
+			"meta": {immutable("meta")},
+		}, errs)
+	})
+}
+
+func TestSchemaCompileCreateOnly(t *testing.T) {
+	s := schema.Schema{Fields: schema.Fields{
+		"slug": {CreateOnly: true, ReadOnly: true},
+	}}
+	assert.EqualError(t, s.Compile(nil), "slug: create-only field can't be read-only or computed")
+}
+
+func TestSchemaCompileDependencies(t *testing.T) {
+	cases := []struct {
+		name   string
+		fields schema.Fields
+		err    string
+	}{
+		{
+			name: "valid",
+			fields: schema.Fields{
+				"type":       {Filterable: true},
+				"vat_number": {Dependency: query.MustParsePredicate(`{type: "company"}`)},
+			},
+		},
+		{
+			name: "typo",
+			fields: schema.Fields{
+				"type":       {Filterable: true},
+				"vat_number": {Dependency: query.MustParsePredicate(`{$or: [{tpye: "company"}, {kind: "pro"}, {tpye: "person"}]}`)},
+			},
+			err: "vat_number: dependency references unknown fields: kind, tpye",
+		},
+		{
+			name: "parent",
+			fields: schema.Fields{
+				"type": {Filterable: true},
+				"billing": {Schema: &schema.Schema{Fields: schema.Fields{
+					"vat_number": {Dependency: query.MustParsePredicate(`{type: "company"}`)},
+				}}},
+			},
+		},
+		{
+			name: "parent typo",
+			fields: schema.Fields{
+				"type": {Filterable: true},
+				"billing": {Schema: &schema.Schema{Fields: schema.Fields{
+					"vat_number": {Dependency: query.MustParsePredicate(`{"billing.tpye": "company"}`)},
+				}}},
+			},
+			err: "billing.vat_number: dependency references unknown fields: billing.tpye",
+		},
+		{
+			name: "dotted",
+			fields: schema.Fields{
+				"billing": {Schema: &schema.Schema{Fields: schema.Fields{
+					"type": {Filterable: true},
+				}}},
+				"vat_number": {Dependency: query.MustParsePredicate(`{"billing.type": "company"}`)},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := schema.Schema{Fields: tc.fields}
+			err := s.Compile(nil)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+// signatureValidator rejects any value but "valid" with a FatalError.
+type signatureValidator struct{}
+
+func (signatureValidator) Validate(value interface{}) (interface{}, error) {
+	if value != "valid" {
+		return nil, schema.FatalError{Err: errors.New("signature mismatch")}
+	}
+	return value, nil
+}
+
+func TestSchemaValidateFatalError(t *testing.T) {
+	signature := &signatureValidator{}
+	for _, parallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("parallel=%v", parallel), func(t *testing.T) {
+			s := schema.Schema{
+				ParallelValidation: parallel,
+				Fields: schema.Fields{
+					"id":        {Required: true},
+					"name":      {Validator: &schema.String{}},
+					"count":     {Validator: &schema.Integer{}},
+					"signature": {Validator: signature},
+					"meta": {Schema: &schema.Schema{Fields: schema.Fields{
+						"signature": {Validator: signature},
+					}}},
+				},
+			}
+			assert.NoError(t, s.Compile(nil))
+
+			_, errs := s.Validate(map[string]interface{}{"name": 1, "count": "a", "signature": "invalid", "unknown": true}, map[string]interface{}{})
+			assert.Equal(t, map[string][]interface{}{
+				"signature": {schema.ValidationError{Code: schema.CodeFatal, Message: "signature mismatch", Field: "signature"}},
+			}, errs)
+
+			_, errs = s.Validate(map[string]interface{}{"name": 1, "signature": "valid", "meta": map[string]interface{}{"signature": "invalid"}}, map[string]interface{}{})
+			assert.Equal(t, map[string][]interface{}{
+				"meta": {map[string][]interface{}{
+					"signature": {schema.ValidationError{Code: schema.CodeFatal, Message: "signature mismatch", Field: "signature"}},
+				}},
+			}, errs)
+
+			// Other errors are collected as usual without fatal error.
+			_, errs = s.Validate(map[string]interface{}{"name": 1, "signature": "valid"}, map[string]interface{}{})
+			assert.Len(t, errs, 2)
+		})
+	}
+}
+
+func TestSchemaConditions(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"type":       {Filterable: true, Validator: &schema.String{Allowed: []string{"company", "person"}}},
+			"vat_number": {Validator: &schema.String{}},
+			"birthdate":  {Validator: &schema.String{}},
+			"name":       {Validator: &schema.String{}},
+		},
+		Conditions: []schema.Condition{
+			{
+				If:         query.MustParsePredicate(`{type: "company"}`),
+				Required:   []string{"vat_number"},
+				Forbidden:  []string{"birthdate"},
+				Validators: map[string]schema.FieldValidator{"name": &schema.String{MinLen: 3}},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	cases := []struct {
+		Name   string
+		Change map[string]interface{}
+		Errs   map[string][]interface{}
+	}{
+		{
+			Name:   "person",
+			Change: map[string]interface{}{"type": "person", "birthdate": "2000-01-01", "name": "Al"},
+			Errs:   map[string][]interface{}{},
+		},
+		{
+			Name:   "valid company",
+			Change: map[string]interface{}{"type": "company", "vat_number": "FR123", "name": "ACME"},
+			Errs:   map[string][]interface{}{},
+		},
+		{
+			Name:   "invalid company",
+			Change: map[string]interface{}{"type": "company", "birthdate": "2000-01-01", "name": "Al"},
+			Errs: map[string][]interface{}{
+				"vat_number": {schema.ValidationError{Code: schema.CodeRequired, Message: "required", Field: "vat_number"}},
+				"birthdate":  {schema.ValidationError{Code: schema.CodeDependency, Message: `not allowed when {type: "company"}`, Field: "birthdate"}},
+				"name":       {schema.ValidationError{Code: schema.CodeValidator, Message: "is shorter than 3 characters (got 2)", Field: "name"}},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, errs := s.Validate(tc.Change, map[string]interface{}{})
+			assert.Equal(t, tc.Errs, errs)
+		})
+	}
+}
+
+func TestSchemaCompileConditions(t *testing.T) {
+	fields := schema.Fields{
+		"type":       {Filterable: true},
+		"vat_number": {},
+	}
+	cases := []struct {
+		Name      string
+		Condition schema.Condition
+		Error     string
+	}{
+		{
+			Name:      "no predicate",
+			Condition: schema.Condition{Required: []string{"vat_number"}},
+			Error:     "conditions[0]: no predicate defined",
+		},
+		{
+			Name:      "unknown predicate field",
+			Condition: schema.Condition{If: query.MustParsePredicate(`{tpye: "company"}`)},
+			Error:     "conditions[0]: predicate references unknown fields: tpye",
+		},
+		{
+			Name:      "not filterable",
+			Condition: schema.Condition{If: query.MustParsePredicate(`{vat_number: "a"}`)},
+			Error:     "conditions[0]: vat_number: field is not filterable",
+		},
+		{
+			Name:      "unknown required field",
+			Condition: schema.Condition{If: query.MustParsePredicate(`{type: "company"}`), Required: []string{"vat"}},
+			Error:     "conditions[0]: field vat not found",
+		},
+		{
+			Name:      "unknown forbidden field",
+			Condition: schema.Condition{If: query.MustParsePredicate(`{type: "company"}`), Forbidden: []string{"birthdate"}},
+			Error:     "conditions[0]: field birthdate not found",
+		},
+		{
+			Name: "invalid validator",
+			Condition: schema.Condition{
+				If:         query.MustParsePredicate(`{type: "company"}`),
+				Validators: map[string]schema.FieldValidator{"vat_number": &schema.String{Regexp: "["}},
+			},
+			Error: "conditions[0]: vat_number: invalid regexp: error parsing regexp: missing closing ]: `[`",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			s := schema.Schema{Fields: fields, Conditions: []schema.Condition{tc.Condition}}
+			assert.EqualError(t, s.Compile(nil), tc.Error)
+		})
+	}
+}
+
+func TestSchemaPostValidate(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"start_date": {Validator: &schema.Time{}},
+			"end_date":   {Validator: &schema.Time{}},
+		},
+		PostValidate: func(ctx context.Context, doc map[string]interface{}, errs map[string][]interface{}) map[string][]interface{} {
+			start, ok1 := doc["start_date"].(time.Time)
+			end, ok2 := doc["end_date"].(time.Time)
+			if ok1 && ok2 && !end.After(start) {
+				errs["end_date"] = append(errs["end_date"], schema.ValidationError{Code: schema.CodeValidator, Message: "must be after start_date", Field: "end_date"})
+			}
+			return errs
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	_, errs := s.Validate(map[string]interface{}{"start_date": "2020-01-02T00:00:00Z", "end_date": "2020-01-03T00:00:00Z"}, map[string]interface{}{})
+	assert.Empty(t, errs)
+
+	_, errs = s.Validate(map[string]interface{}{"start_date": "2020-01-02T00:00:00Z", "end_date": "2020-01-01T00:00:00Z"}, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{
+		"end_date": {schema.ValidationError{Code: schema.CodeValidator, Message: "must be after start_date", Field: "end_date"}},
+	}, errs)
+
+	// Errors can be removed.
+	s.PostValidate = func(ctx context.Context, doc map[string]interface{}, errs map[string][]interface{}) map[string][]interface{} {
+		return nil
+	}
+	doc, errs := s.Validate(map[string]interface{}{"start_date": "invalid"}, map[string]interface{}{})
+	assert.Empty(t, errs)
+	assert.NotNil(t, doc)
+}
+
+func TestSchemaPreValidate(t *testing.T) {
+	var calls int
+	contact := func(ctx context.Context, doc map[string]interface{}) error {
+		calls++
+		if doc["email"] == nil && doc["phone"] == nil {
+			return errors.New("at least one contact method is required")
+		}
+		return nil
+	}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"email": {Validator: &schema.String{}},
+			"phone": {Validator: &schema.String{}},
+			"name":  {Validator: &schema.String{}},
+			"address": {Schema: &schema.Schema{
+				PreValidate: contact,
+				Fields:      schema.Fields{"city": {}},
+			}},
+		},
+		PreValidate: contact,
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	_, errs := s.Validate(map[string]interface{}{"name": 1, "address": map[string]interface{}{"city": "Paris"}}, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{
+		"": {schema.ValidationError{Code: schema.CodeValidator, Message: "at least one contact method is required"}},
+	}, errs)
+
+	_, errs = s.Validate(map[string]interface{}{"email": "john@example.com", "address": map[string]interface{}{"city": "Paris"}}, map[string]interface{}{})
+	assert.Empty(t, errs)
+	// Only called on the root document.
+	assert.Equal(t, 2, calls)
+
+	s.PreValidate = func(ctx context.Context, doc map[string]interface{}) error {
+		return schema.ErrorMap{"email": {"required without phone"}}
+	}
+	_, errs = s.Validate(map[string]interface{}{}, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{"email": {"required without phone"}}, errs)
+}
+
+func TestSchemaOnCompute(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"first_name": {Validator: &schema.String{}},
+			"last_name":  {Validator: &schema.String{}},
+			"full_name": {
+				OnCompute: func(ctx context.Context, doc map[string]interface{}) (interface{}, error) {
+					if doc["last_name"] == "error" {
+						return nil, errors.New("cannot compute")
+					}
+					return fmt.Sprintf("%v %v", doc["first_name"], doc["last_name"]), nil
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.Background()
+
+	t.Run("Create", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"first_name": "John", "last_name": "Doe"}, nil, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Empty(t, errs)
+		assert.Equal(t, "John Doe", doc["full_name"])
+	})
+	t.Run("Update", func(t *testing.T) {
+		original := map[string]interface{}{"first_name": "John", "last_name": "Doe", "full_name": "John Doe"}
+		changes, base := s.Prepare(ctx, map[string]interface{}{"last_name": "Smith"}, &original, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Empty(t, errs)
+		assert.Equal(t, "John Smith", doc["full_name"])
+	})
+	t.Run("Replace", func(t *testing.T) {
+		original := map[string]interface{}{"first_name": "John", "last_name": "Doe", "full_name": "John Doe"}
+		changes, base := s.Prepare(ctx, map[string]interface{}{"first_name": "Jane", "last_name": "Doe"}, &original, true)
+		doc, errs := s.Validate(changes, base)
+		assert.Empty(t, errs)
+		assert.Equal(t, "Jane Doe", doc["full_name"])
+	})
+	t.Run("ReadOnly", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"first_name": "John", "full_name": "Foo"}, nil, false)
+		_, errs := s.Validate(changes, base)
+		assert.Equal(t, map[string][]interface{}{
+			"full_name": {schema.ValidationError{Code: schema.CodeReadOnly, Message: "read-only", Field: "full_name"}},
+		}, errs)
+	})
+	t.Run("Error", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"last_name": "error"}, nil, false)
+		_, errs := s.Validate(changes, base)
+		assert.Equal(t, map[string][]interface{}{
+			"full_name": {schema.ValidationError{Code: schema.CodeHook, Message: "cannot compute", Field: "full_name"}},
+		}, errs)
+	})
+	t.Run("Compile", func(t *testing.T) {
+		s := schema.Schema{Fields: schema.Fields{
+			"full_name": {Required: true, OnCompute: s.Fields["full_name"].OnCompute},
+		}}
+		assert.EqualError(t, s.Compile(nil), "full_name: on-compute field can't be computed or required")
+	})
+}
+
+func TestSchemaAliases(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"handle": {
+				Aliases:    []string{"username", "login"},
+				Validator:  &schema.String{},
+				Filterable: true,
+				Sortable:   true,
+			},
+			"address": {Schema: &schema.Schema{
+				Fields: schema.Fields{
+					"zip": {Aliases: []string{"postcode"}, Validator: &schema.String{}},
+				},
+			}},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.Background()
+
+	payload := map[string]interface{}{
+		"username": "john",
+		"address":  map[string]interface{}{"postcode": "75001"},
+	}
+	changes, base := s.Prepare(ctx, payload, nil, false)
+	doc, errs := s.Validate(changes, base)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"handle":  "john",
+		"address": map[string]interface{}{"zip": "75001"},
+	}, doc)
+	// The payload is not changed.
+	assert.Contains(t, payload, "username")
+
+	changes, base = s.Prepare(ctx, map[string]interface{}{"handle": "john", "login": "jdoe"}, nil, false)
+	_, errs = s.Validate(changes, base)
+	assert.Equal(t, map[string][]interface{}{
+		"login": {schema.ValidationError{Code: schema.CodeConflict, Message: "alias of handle, which is also set", Field: "login"}},
+	}, errs)
+
+	assert.Equal(t, s.GetField("handle"), s.GetField("username"))
+	assert.Equal(t, s.GetField("address.zip"), s.GetField("address.postcode"))
+	assert.True(t, s.IsFilterable("login"))
+	assert.True(t, s.IsSortable("login"))
+}
+
+func TestSchemaCompileAliases(t *testing.T) {
+	s := schema.Schema{Fields: schema.Fields{
+		"handle": {Aliases: []string{"name"}},
+		"name":   {},
+	}}
+	assert.EqualError(t, s.Compile(nil), "handle: alias name is a field name")
+	s = schema.Schema{Fields: schema.Fields{
+		"handle": {Aliases: []string{"login"}},
+		"email":  {Aliases: []string{"login"}},
+	}}
+	assert.Error(t, s.Compile(nil))
+}
+
+func TestSchemaValidateDelete(t *testing.T) {
+	var deleted []string
+	onDelete := func(name string, fail bool) func(ctx context.Context, value interface{}) error {
+		return func(ctx context.Context, value interface{}) error {
+			if fail {
+				return errors.New(name + " in use")
+			}
+			deleted = append(deleted, name)
+			return nil
+		}
+	}
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"token": {OnDelete: onDelete("token", false)},
+			"file": {Schema: &schema.Schema{
+				Fields: schema.Fields{"path": {OnDelete: onDelete("path", false)}},
+			}},
+			"name": {},
+		},
+		OnDelete: func(ctx context.Context, doc map[string]interface{}) error {
+			if doc["name"] == "locked" {
+				return schema.ErrorMap{"name": {"locked"}}
+			}
+			deleted = append(deleted, "doc")
+			return nil
+		},
+	}
+	assert.True(t, s.HasDeleteHooks())
+	assert.False(t, schema.Schema{Fields: schema.Fields{"name": {}}}.HasDeleteHooks())
+	ctx := context.Background()
+
+	errs := s.ValidateDelete(ctx, map[string]interface{}{"token": "t", "file": map[string]interface{}{"path": "/tmp/f"}})
+	assert.Empty(t, errs)
+	sort.Strings(deleted)
+	assert.Equal(t, []string{"doc", "path", "token"}, deleted)
+
+	deleted = nil
+	errs = s.ValidateDelete(ctx, map[string]interface{}{"name": "locked"})
+	assert.Equal(t, map[string][]interface{}{"name": {"locked"}}, errs)
+	assert.Empty(t, deleted)
+
+	// The document hook is not called when a field hook fails.
+	s.Fields["file"].Schema.Fields["path"] = schema.Field{OnDelete: onDelete("path", true)}
+	errs = s.ValidateDelete(ctx, map[string]interface{}{"file": map[string]interface{}{"path": "/tmp/f"}})
+	assert.Equal(t, map[string][]interface{}{
+		"file": {map[string][]interface{}{
+			"path": {schema.ValidationError{Code: schema.CodeDelete, Message: "path in use", Field: "path"}},
+		}},
+	}, errs)
+	assert.Empty(t, deleted)
+}
+
+func TestSchemaCompileErrorPath(t *testing.T) {
+	address := func(zip schema.Field) *schema.Schema {
+		return &schema.Schema{Fields: schema.Fields{
+			"country": {},
+			"geo": {Schema: &schema.Schema{Fields: schema.Fields{
+				"zip": zip,
+			}}},
+		}}
+	}
+	cases := []struct {
+		name  string
+		field schema.Field
+		err   string
+	}{
+		{
+			name:  "Validator",
+			field: schema.Field{Validator: &schema.String{Regexp: "["}},
+			err:   "address.geo.zip: invalid regexp: error parsing regexp: missing closing ]: `[`",
+		},
+		{
+			name:  "Field",
+			field: schema.Field{Compute: func(ctx context.Context, doc map[string]interface{}) (interface{}, error) { return nil, nil }, Required: true},
+			err:   "address.geo.zip: computed field can't be required, filterable or sortable",
+		},
+		{
+			name:  "Excludes",
+			field: schema.Field{Excludes: []string{"city"}},
+			err:   "address.geo.zip: excluded field city not found",
+		},
+		{
+			name:  "Dependency",
+			field: schema.Field{Dependency: query.MustParsePredicate(`{"address.country": "FR"}`)},
+			err:   "address.geo.zip: invalid dependency: address.country: field is not filterable",
+		},
+		{
+			name:  "UnknownDependency",
+			field: schema.Field{Dependency: query.MustParsePredicate(`{"address.city": "Paris"}`)},
+			err:   "address.geo.zip: dependency references unknown fields: address.city",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := schema.Schema{Fields: schema.Fields{"address": {Schema: address(tc.field)}}}
+			assert.EqualError(t, s.Compile(nil), tc.err)
+		})
+	}
+	t.Run("Object", func(t *testing.T) {
+		s := schema.Schema{Fields: schema.Fields{
+			"address": {Validator: &schema.Object{Schema: address(schema.Field{Validator: schema.String{}})}},
+		}}
+		assert.EqualError(t, s.Compile(nil), "address.geo.zip: not a schema.Validator pointer")
+	})
+}
+
+type upperValidator struct{}
+
+func (upperValidator) Validate(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, errors.New("not a string")
+	}
+	return strings.ToUpper(str), nil
+}
+
+func TestSchemaValidateChanges(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"status": {Validator: &upperValidator{}},
+			"name":   {Validator: &schema.String{}},
+			"note":   {},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.Background()
+	base := map[string]interface{}{"status": "ACTIVE", "name": "john", "note": "n"}
+
+	doc, errs, changed := s.ValidateChanges(ctx, map[string]interface{}{"status": "ACTIVE"}, base)
+	assert.Empty(t, errs)
+	assert.Equal(t, "ACTIVE", doc["status"])
+	assert.Empty(t, changed)
+
+	// The normalized value is identical to the stored one.
+	_, errs, changed = s.ValidateChanges(ctx, map[string]interface{}{"status": "active"}, base)
+	assert.Empty(t, errs)
+	assert.Empty(t, changed)
+
+	_, errs, changed = s.ValidateChanges(ctx, map[string]interface{}{"status": "inactive", "note": schema.Tombstone}, base)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]bool{"status": true, "note": true}, changed)
+
+	_, errs, changed = s.ValidateChanges(ctx, map[string]interface{}{"name": 1}, base)
+	assert.NotEmpty(t, errs)
+	assert.Nil(t, changed)
+}
+
+func TestSchemaPartialValidate(t *testing.T) {
+	s := schema.Schema{
+		MinLen: 3,
+		Fields: schema.Fields{
+			"name":  {Required: true, Validator: &schema.String{}},
+			"email": {Required: true, Validator: &schema.String{MinLen: 3}},
+			"vat":   {Dependency: fakePredicate(false), Validator: &schema.String{}},
+			"address": {Schema: &schema.Schema{
+				Fields: schema.Fields{
+					"city": {Required: true},
+					"zip":  {Validator: &schema.Integer{}},
+				},
+			}},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+
+	doc, errs := s.PartialValidate(map[string]interface{}{"email": "john@example.com"})
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"email": "john@example.com"}, doc)
+
+	doc, errs = s.PartialValidate(map[string]interface{}{"address": map[string]interface{}{"zip": 75000}, "name": schema.Tombstone})
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"address": map[string]interface{}{"zip": 75000}}, doc)
+
+	_, errs = s.PartialValidate(map[string]interface{}{
+		"name":  nil,
+		"email": "j",
+		"vat":   "FR123",
+		"foo":   "bar",
+	})
+	assert.Equal(t, map[string][]interface{}{
+		"name":  {schema.ValidationError{Code: schema.CodeRequired, Message: "required", Field: "name"}},
+		"email": {schema.ValidationError{Code: schema.CodeValidator, Message: "is shorter than 3 characters (got 1)", Field: "email"}},
+		"vat":   {schema.ValidationError{Code: schema.CodeDependency, Message: "does not match dependency: false", Field: "vat"}},
+		"foo":   {schema.ValidationError{Code: schema.CodeInvalidField, Message: "invalid field", Field: "foo"}},
+	}, errs)
+
+	s.UnknownFields = schema.StripUnknownFields
+	doc, errs = s.PartialValidate(map[string]interface{}{"name": "john", "foo": "bar"})
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"name": "john"}, doc)
+}
+
+func TestSchemaFieldPaths(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"name":     {},
+			"password": {Hidden: true},
+			"user": {Schema: &schema.Schema{
+				Fields: schema.Fields{
+					"name": {},
+					"address": {Validator: &schema.Object{Schema: &schema.Schema{
+						Fields: schema.Fields{
+							"zip":    {},
+							"secret": {Hidden: true, Schema: &schema.Schema{Fields: schema.Fields{"key": {}}}},
+						},
+					}}},
+				},
+			}},
+			"tags": {Validator: &schema.Array{Values: schema.Field{Validator: &schema.String{}}}},
+			"contacts": {Validator: &schema.Array{Values: schema.Field{Validator: &schema.Object{Schema: &schema.Schema{
+				Fields: schema.Fields{"email": {}},
+			}}}}},
+			"meta": {Validator: &schema.Dict{Values: schema.Field{Validator: &schema.Integer{}}}},
+		},
+	}
+	expected := []string{
+		"contacts",
+		"contacts.*",
+		"contacts.*.email",
+		"meta",
+		"meta.*",
+		"name",
+		"password",
+		"tags",
+		"tags.*",
+		"user",
+		"user.address",
+		"user.address.secret",
+		"user.address.secret.key",
+		"user.address.zip",
+		"user.name",
+	}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, expected, s.FieldPaths())
+	}
+	assert.Equal(t, []string{
+		"contacts",
+		"contacts.*",
+		"contacts.*.email",
+		"meta",
+		"meta.*",
+		"name",
+		"tags",
+		"tags.*",
+		"user",
+		"user.address",
+		"user.address.zip",
+		"user.name",
+	}, s.VisibleFieldPaths())
+	for _, path := range s.FieldPaths() {
+		assert.NotNil(t, s.GetField(strings.Replace(path, "*", "0", -1)), path)
+	}
+	assert.Empty(t, schema.Schema{}.FieldPaths())
+}
+
+func TestSchemaUnknownFields(t *testing.T) {
+	newSchema := func(root, sub schema.UnknownFieldsPolicy) schema.Schema {
+		return schema.Schema{
+			UnknownFields: root,
+			Fields: schema.Fields{
+				"name": {},
+				"sub": {Schema: &schema.Schema{
+					UnknownFields: sub,
+					Fields: schema.Fields{
+						"name": {},
+						"deep": {Schema: &schema.Schema{Fields: schema.Fields{"name": {}}}},
+					},
+				}},
+			},
+		}
+	}
+	payload := map[string]interface{}{
+		"name":  "a",
+		"extra": 1,
+		"sub": map[string]interface{}{
+			"name":  "b",
+			"extra": 2,
+			"deep":  map[string]interface{}{"name": "c", "extra": 3},
+		},
+	}
+	invalid := func(field string) []interface{} {
+		return []interface{}{schema.ValidationError{Code: schema.CodeInvalidField, Message: "invalid field", Field: field}}
+	}
+	cases := []struct {
+		name      string
+		root, sub schema.UnknownFieldsPolicy
+		doc       map[string]interface{}
+		errs      map[string][]interface{}
+	}{
+		{
+			name: "Reject",
+			errs: map[string][]interface{}{
+				"extra": invalid("extra"),
+				"sub": {map[string][]interface{}{
+					"extra": invalid("extra"),
+					"deep":  {map[string][]interface{}{"extra": invalid("extra")}},
+				}},
+			},
+		},
+		{
+			name: "Strip",
+			root: schema.StripUnknownFields,
+			doc: map[string]interface{}{
+				"name": "a",
+				"sub": map[string]interface{}{
+					"name": "b",
+					"deep": map[string]interface{}{"name": "c"},
+				},
+			},
+		},
+		{
+			name: "Allow",
+			root: schema.AllowUnknownFields,
+			doc:  payload,
+		},
+		{
+			name: "Override",
+			root: schema.StripUnknownFields,
+			sub:  schema.AllowUnknownFields,
+			doc: map[string]interface{}{
+				"name": "a",
+				"sub":  payload["sub"],
+			},
+		},
+		{
+			name: "OverrideReject",
+			root: schema.AllowUnknownFields,
+			sub:  schema.RejectUnknownFields,
+			errs: map[string][]interface{}{
+				"sub": {map[string][]interface{}{
+					"extra": invalid("extra"),
+					"deep":  {map[string][]interface{}{"extra": invalid("extra")}},
+				}},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newSchema(tc.root, tc.sub)
+			assert.NoError(t, s.Compile(nil))
+			changes, base := s.Prepare(context.Background(), payload, nil, false)
+			doc, errs := s.Validate(changes, base)
+			if tc.errs != nil {
+				assert.Equal(t, tc.errs, errs)
+				return
+			}
+			assert.Empty(t, errs)
+			assert.Equal(t, tc.doc, doc)
+		})
+	}
+}
+
+func TestSchemaErrorMessages(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"name": {
+				Required:  true,
+				Validator: &schema.String{MinLen: 2, MaxLen: 5, Regexp: "^[a-z]+$"},
+				ErrorMessages: map[schema.ErrorCode]string{
+					schema.CodeRequired: "Please enter your name",
+					schema.CodeMinLen:   "At least {min} characters, got {len}",
+					schema.CodePattern:  "Only lowercase letters",
+				},
+			},
+			"age": {
+				Validator: &schema.Integer{Boundaries: &schema.Boundaries{Min: 18, Max: math.Inf(1)}},
+				ErrorMessages: map[schema.ErrorCode]string{
+					schema.CodeMin:       "You must be at least {min}",
+					schema.CodeValidator: "Invalid age",
+				},
+			},
+			"id": {
+				ReadOnly:      true,
+				ErrorMessages: map[schema.ErrorCode]string{schema.CodeReadOnly: "The id can't be changed"},
+			},
+			"email": {Validator: &schema.String{MaxLen: 3}},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	verr := func(field, msg string) []interface{} {
+		return []interface{}{schema.ValidationError{Code: schema.CodeValidator, Message: msg, Field: field}}
+	}
+
+	_, errs := s.Validate(map[string]interface{}{"age": 12, "id": 1, "email": "john@example.com"}, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{
+		"name":  {schema.ValidationError{Code: schema.CodeRequired, Message: "Please enter your name", Field: "name"}},
+		"age":   verr("age", "You must be at least 18"),
+		"id":    {schema.ValidationError{Code: schema.CodeReadOnly, Message: "The id can't be changed", Field: "id"}},
+		"email": verr("email", "is longer than 3 characters (got 16)"),
+	}, errs)
+
+	_, errs = s.Validate(map[string]interface{}{"name": "j", "age": "x"}, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{
+		"name": verr("name", "At least 2 characters, got 1"),
+		"age":  verr("age", "Invalid age"),
+	}, errs)
+
+	// Codes without custom message keep the default message.
+	_, errs = s.Validate(map[string]interface{}{"name": "johnny"}, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{
+		"name": verr("name", "is longer than 5 characters (got 6)"),
+	}, errs)
+	_, errs = s.Validate(map[string]interface{}{"name": "JO"}, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{
+		"name": verr("name", "Only lowercase letters"),
+	}, errs)
+}
+
+func TestSchemaIsFilterableSortable(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"name":   {Filterable: true, Sortable: true},
+			"secret": {},
+			"address": {
+				Schema: &schema.Schema{Fields: schema.Fields{
+					"city": {Filterable: true},
+					"zip":  {Sortable: true},
+				}},
+			},
+			"tags": {
+				Validator: &schema.Dict{Values: schema.Field{Filterable: true}},
+			},
+		},
+	}
+	cases := []struct {
+		path       string
+		filterable bool
+		sortable   bool
+	}{
+		{"name", true, true},
+		{"secret", false, false},
+		{"address", false, false},
+		{"address.city", true, false},
+		{"address.zip", false, true},
+		{"address.unknown", false, false},
+		{"tags.foo", true, false},
+		{"unknown", false, false},
+		{"name.sub", false, false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.filterable, s.IsFilterable(tc.path), "IsFilterable(%q)", tc.path)
+		assert.Equal(t, tc.sortable, s.IsSortable(tc.path), "IsSortable(%q)", tc.path)
+	}
+}
+
+func TestSchemaNullable(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"nickname": {Nullable: true, Default: "anonymous", Validator: &schema.String{}},
+			"name":     {Validator: &schema.String{}},
+			"email":    {Required: true, Validator: &schema.String{}},
+			"website":  {Validator: &schema.AnyOf{&schema.String{}, &schema.Null{}}},
+			"address": {Nullable: true, Schema: &schema.Schema{Fields: schema.Fields{
+				"city": {Validator: &schema.String{}},
+			}}},
+			"location": {Schema: &schema.Schema{Fields: schema.Fields{
+				"lat": {Validator: &schema.Float{}},
+			}}},
+			"tags": {Validator: &schema.Array{
+				Values: schema.Field{Nullable: true, Validator: &schema.String{}},
+			}},
+			"scores": {Validator: &schema.Array{
+				Values: schema.Field{Validator: &schema.Integer{}},
+			}},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	ctx := context.Background()
+	null := func(field string) schema.ValidationError {
+		return schema.ValidationError{Code: schema.CodeNull, Message: "cannot be null", Field: field}
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"nickname": nil,
+			"email":    "john@example.com",
+			"address":  nil,
+			"tags":     []interface{}{"a", nil},
+		}
+		changes, base := s.Prepare(ctx, payload, nil, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, payload, doc)
+	})
+	t.Run("CreateAbsent", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{"email": "john@example.com"}, nil, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, map[string]interface{}{"nickname": "anonymous", "email": "john@example.com"}, doc)
+	})
+	original := map[string]interface{}{
+		"nickname": "johnny",
+		"name":     "John",
+		"email":    "john@example.com",
+		"address":  map[string]interface{}{"city": "Paris"},
+		"location": map[string]interface{}{"lat": 48.8},
+	}
+	t.Run("UpdateNull", func(t *testing.T) {
+		// Null is also accepted by validators accepting it.
+		changes, base := s.Prepare(ctx, map[string]interface{}{"nickname": nil, "address": nil, "website": nil}, &original, false)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.Nil(t, doc["nickname"])
+		assert.Contains(t, doc, "nickname")
+		assert.Nil(t, doc["address"])
+		assert.Contains(t, doc, "address")
+		assert.Contains(t, doc, "website")
+	})
+	t.Run("UpdateNotNullable", func(t *testing.T) {
+		changes, base := s.Prepare(ctx, map[string]interface{}{
+			"name":     nil,
+			"email":    nil,
+			"location": nil,
+			"scores":   []interface{}{1, nil},
+		}, &original, false)
+		_, errs := s.Validate(changes, base)
+		assert.Equal(t, map[string][]interface{}{
+			"name":     {null("name")},
+			"email":    {schema.ValidationError{Code: schema.CodeRequired, Message: "required", Field: "email"}},
+			"location": {null("location")},
+			"scores": {
+				schema.ValidationError{Code: schema.CodeValidator, Message: "invalid value at #2: cannot be null", Field: "scores"},
+				map[string][]interface{}{"1": {schema.ValidationError{Code: schema.CodeValidator, Message: "cannot be null", Field: "1"}}},
+			},
+		}, errs)
+	})
+	t.Run("Replace", func(t *testing.T) {
+		// Omitted fields are removed while null is stored.
+		changes, base := s.Prepare(ctx, map[string]interface{}{"name": "John", "email": "john@example.com", "nickname": nil}, &original, true)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, map[string]interface{}{"nickname": nil, "name": "John", "email": "john@example.com"}, doc)
+	})
+	t.Run("MergePatch", func(t *testing.T) {
+		// With merge patch, null removes the field.
+		changes, base := s.PrepareMergePatch(ctx, map[string]interface{}{"nickname": nil}, &original)
+		doc, errs := s.Validate(changes, base)
+		assert.Len(t, errs, 0)
+		assert.NotContains(t, doc, "nickname")
+	})
+}
+
+func TestSchemaPrepareE(t *testing.T) {
+	s := schema.Schema{Fields: schema.Fields{"name": {}}}
+	ctx := context.Background()
+
+	assert.NotPanics(t, func() {
+		changes, base, err := s.PrepareE(ctx, map[string]interface{}{"name": "John"}, nil, true)
+		assert.Equal(t, schema.ErrReplaceWithoutOriginal, err)
+		assert.Nil(t, changes)
+		assert.Nil(t, base)
+	})
+
+	original := map[string]interface{}{"name": "Jane"}
+	changes, base, err := s.PrepareE(ctx, map[string]interface{}{"name": "John"}, &original, true)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "John"}, changes)
+	assert.Equal(t, map[string]interface{}{"name": "Jane"}, base)
+}
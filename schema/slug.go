@@ -0,0 +1,129 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// slugRegexp matches valid slugs: lowercase alphanumeric words separated by
+// single dashes.
+var slugRegexp = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// slugTranslit maps common latin characters to their ASCII transliteration.
+var slugTranslit = map[rune]string{}
+
+func init() {
+	for ascii, runes := range map[string]string{
+		"a":  "àáâãäåāăą",
+		"c":  "çćĉċč",
+		"d":  "ďđð",
+		"e":  "èéêëēĕėęě",
+		"g":  "ĝğġģ",
+		"h":  "ĥħ",
+		"i":  "ìíîïĩīĭįı",
+		"j":  "ĵ",
+		"k":  "ķ",
+		"l":  "ĺļľŀł",
+		"n":  "ñńņňŉ",
+		"o":  "òóôõöøōŏő",
+		"r":  "ŕŗř",
+		"s":  "śŝşšș",
+		"t":  "ţťŧț",
+		"u":  "ùúûüũūŭůűų",
+		"w":  "ŵ",
+		"y":  "ýÿŷ",
+		"z":  "źżž",
+		"ae": "æ",
+		"oe": "œ",
+		"ss": "ß",
+		"th": "þ",
+	} {
+		for _, r := range runes {
+			slugTranslit[r] = ascii
+		}
+	}
+}
+
+// Slug validates URL friendly identifiers made of lowercase alphanumeric
+// characters separated by dashes (i.e.: "my-first-post").
+type Slug struct {
+	// MinLen defines the minimum slug length (default 1).
+	MinLen int
+	// MaxLen defines the maximum slug length (default no limit).
+	MaxLen int
+	// Strict rejects non conforming values instead of normalizing them.
+	Strict bool
+	// Truncate shortens normalized slugs longer than MaxLen at a word
+	// boundary instead of rejecting them. It is ignored when Strict is set.
+	Truncate bool
+}
+
+// Validate validates and normalizes slug values. Unless Strict is set, the
+// value is lowercased, common latin characters are transliterated to ASCII,
+// and any sequence of other characters is replaced by a single dash.
+func (v Slug) Validate(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("not a string")
+	}
+	if !v.Strict {
+		s = Slugify(s)
+		if v.Truncate && v.MaxLen > 0 {
+			s = truncateSlug(s, v.MaxLen)
+		}
+	}
+	if !slugRegexp.MatchString(s) {
+		return nil, errors.New("not a valid slug")
+	}
+	l := len(s)
+	if l < v.MinLen {
+		return nil, fmt.Errorf("is shorter than %d", v.MinLen)
+	}
+	if v.MaxLen > 0 && l > v.MaxLen {
+		return nil, fmt.Errorf("is longer than %d", v.MaxLen)
+	}
+	return s, nil
+}
+
+// Slugify converts s to a slug as normalized by Slug: s is lowercased, common
+// latin characters are transliterated to ASCII, and any sequence of other
+// characters is replaced by a single dash (i.e.: "Crème Brûlée!" becomes
+// "creme-brulee"). It can be used to derive a slug from another field.
+func Slugify(s string) string {
+	b := make([]byte, 0, len(s))
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		var part string
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			part = string(r)
+		} else if part = slugTranslit[r]; part == "" {
+			// Any other character is a word separator.
+			dash = len(b) > 0
+			continue
+		}
+		if dash {
+			b = append(b, '-')
+			dash = false
+		}
+		b = append(b, part...)
+	}
+	return string(b)
+}
+
+// truncateSlug shortens slug to at most max bytes, cutting at the last word
+// boundary when possible.
+func truncateSlug(slug string, max int) string {
+	if len(slug) <= max {
+		return slug
+	}
+	if slug[max] == '-' {
+		return slug[:max]
+	}
+	cut := slug[:max]
+	if i := strings.LastIndexByte(cut, '-'); i > 0 {
+		return cut[:i]
+	}
+	return cut
+}
@@ -0,0 +1,144 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestSlugValidate(t *testing.T) {
+	cases := []fieldValidatorTestCase{
+		{
+			Name:      `Validate("my-post")`,
+			Validator: &schema.Slug{},
+			Input:     "my-post",
+			Expect:    "my-post",
+		},
+		{
+			Name:      `Validate("  My First   Post!! ")`,
+			Validator: &schema.Slug{},
+			Input:     "  My First   Post!! ",
+			Expect:    "my-first-post",
+		},
+		{
+			Name:      `Validate("Crème Brûlée, à la française")`,
+			Validator: &schema.Slug{},
+			Input:     "Crème Brûlée, à la française",
+			Expect:    "creme-brulee-a-la-francaise",
+		},
+		{
+			Name:      `Validate("Straße_Œuvre--2018")`,
+			Validator: &schema.Slug{},
+			Input:     "Straße_Œuvre--2018",
+			Expect:    "strasse-oeuvre-2018",
+		},
+		{
+			Name:      `Validate("!!!")`,
+			Validator: &schema.Slug{},
+			Input:     "!!!",
+			Error:     "not a valid slug",
+		},
+		{
+			Name:      `Validate(1)`,
+			Validator: &schema.Slug{},
+			Input:     1,
+			Error:     "not a string",
+		},
+		{
+			Name:      `{MinLen:4}.Validate("a b")`,
+			Validator: &schema.Slug{MinLen: 4},
+			Input:     "a b",
+			Error:     "is shorter than 4",
+		},
+		{
+			Name:      `{MaxLen:5}.Validate("Hello World")`,
+			Validator: &schema.Slug{MaxLen: 5},
+			Input:     "Hello World",
+			Error:     "is longer than 5",
+		},
+		{
+			Name:      `{MaxLen:12,Truncate:true}.Validate("Hello World Again")`,
+			Validator: &schema.Slug{MaxLen: 12, Truncate: true},
+			Input:     "Hello World Again",
+			Expect:    "hello-world",
+		},
+		{
+			Name:      `{MaxLen:11,Truncate:true}.Validate("Hello World Again")`,
+			Validator: &schema.Slug{MaxLen: 11, Truncate: true},
+			Input:     "Hello World Again",
+			Expect:    "hello-world",
+		},
+		{
+			Name:      `{MaxLen:4,Truncate:true}.Validate("Hello World")`,
+			Validator: &schema.Slug{MaxLen: 4, Truncate: true},
+			Input:     "Hello World",
+			Expect:    "hell",
+		},
+		{
+			Name:      `{MaxLen:8,Truncate:true}.Validate("Crème Brûlée")`,
+			Validator: &schema.Slug{MaxLen: 8, Truncate: true},
+			Input:     "Crème Brûlée",
+			Expect:    "creme",
+		},
+		{
+			Name:      `{MaxLen:5,Truncate:true,Strict:true}.Validate("hello-world")`,
+			Validator: &schema.Slug{MaxLen: 5, Truncate: true, Strict: true},
+			Input:     "hello-world",
+			Error:     "is longer than 5",
+		},
+		{
+			Name:      `{Strict:true}.Validate("my-post-2")`,
+			Validator: &schema.Slug{Strict: true},
+			Input:     "my-post-2",
+			Expect:    "my-post-2",
+		},
+		{
+			Name:      `{Strict:true}.Validate("My Post")`,
+			Validator: &schema.Slug{Strict: true},
+			Input:     "My Post",
+			Error:     "not a valid slug",
+		},
+		{
+			Name:      `{Strict:true}.Validate("my--post")`,
+			Validator: &schema.Slug{Strict: true},
+			Input:     "my--post",
+			Error:     "not a valid slug",
+		},
+		{
+			Name:      `{Strict:true}.Validate("-my-post")`,
+			Validator: &schema.Slug{Strict: true},
+			Input:     "-my-post",
+			Error:     "not a valid slug",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestSlugSchemaValidate(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"slug": {Validator: &schema.Slug{}},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	doc, errs := s.Validate(map[string]interface{}{"slug": "Hello, World"}, map[string]interface{}{})
+	assert.Len(t, errs, 0)
+	assert.Equal(t, map[string]interface{}{"slug": "hello-world"}, doc)
+}
+
+func TestSlugify(t *testing.T) {
+	for input, expect := range map[string]string{
+		"My First Post":        "my-first-post",
+		"Ça déçoit à Łódź":     "ca-decoit-a-lodz",
+		"Æsir & Þór":           "aesir-thor",
+		"日本語 title":            "title",
+		"  --Hello--World--  ": "hello-world",
+		"":                     "",
+	} {
+		assert.Equal(t, expect, schema.Slugify(input), input)
+	}
+}
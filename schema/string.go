@@ -4,20 +4,124 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
-// String validates string based values
+// LenMeasure defines how the length of a string is measured.
+type LenMeasure int
+
+const (
+	// Runes measures the length of strings in Unicode code points, so a
+	// multi-byte character counts as one. Note that a character made of a
+	// base and a combining character counts as two.
+	Runes LenMeasure = iota
+	// Bytes measures the length of strings in bytes of their UTF-8 encoding.
+	Bytes
+)
+
+func (m LenMeasure) unit() string {
+	if m == Bytes {
+		return "bytes"
+	}
+	return "characters"
+}
+
+func (m LenMeasure) len(s string) int {
+	if m == Bytes {
+		return len(s)
+	}
+	return utf8.RuneCountInString(s)
+}
+
+// String validates string based values. Values of other types, numbers
+// included, are rejected rather than converted.
 type String struct {
-	re      *regexp.Regexp
-	Regexp  string
-	Allowed []string
-	MaxLen  int
-	MinLen  int
+	re     *regexp.Regexp
+	Regexp string
+	// RegexpMessage overrides the error message returned when the value does
+	// not match Regexp (default "does not match <regexp>").
+	RegexpMessage string
+	Allowed       []string
+	// AllowedDescriptions defines allowed values with their description, for
+	// documentation purpose. Its keys are accepted in addition to Allowed.
+	AllowedDescriptions map[string]string
+	// AllowedCaseInsensitive matches allowed values ignoring case. The value
+	// is normalized to the casing of the matching allowed value. Compile
+	// rejects allowed values only differing by case.
+	AllowedCaseInsensitive bool
+	MaxLen                 int
+	MinLen                 int
+	// LenMeasure defines how MinLen and MaxLen are measured (default Runes).
+	LenMeasure LenMeasure
+	// TrimSpace removes leading and trailing white space from the value.
+	TrimSpace bool
+	// CollapseWhitespace replaces each sequence of white space characters in
+	// the value with a single space, after TrimSpace is applied. The cleaned
+	// value is validated and stored.
+	CollapseWhitespace bool
+}
+
+// AllowedValues returns the list of allowed values, from both Allowed and
+// AllowedDescriptions (sorted).
+func (v String) AllowedValues() []string {
+	if len(v.AllowedDescriptions) == 0 {
+		return v.Allowed
+	}
+	values := make([]string, 0, len(v.Allowed)+len(v.AllowedDescriptions))
+	values = append(values, v.Allowed...)
+	extra := make([]string, 0, len(v.AllowedDescriptions))
+	for value := range v.AllowedDescriptions {
+		found := false
+		for _, allowed := range v.Allowed {
+			if value == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			extra = append(extra, value)
+		}
+	}
+	sort.Strings(extra)
+	return append(values, extra...)
+}
+
+// Describe implements the Describer interface.
+func (v String) Describe() map[string]interface{} {
+	m := map[string]interface{}{"type": "string"}
+	if v.Regexp != "" {
+		m["regexp"] = v.Regexp
+	}
+	if allowed := v.AllowedValues(); len(allowed) > 0 {
+		m["allowed"] = allowed
+	}
+	if v.MinLen > 0 {
+		m["minLen"] = v.MinLen
+	}
+	if v.MaxLen > 0 {
+		m["maxLen"] = v.MaxLen
+	}
+	if v.LenMeasure == Bytes && (v.MinLen > 0 || v.MaxLen > 0) {
+		m["lenMeasure"] = "bytes"
+	}
+	return m
 }
 
 // Compile compiles and validate regexp if any.
 func (v *String) Compile(rc ReferenceChecker) (err error) {
+	if v.AllowedCaseInsensitive {
+		allowed := v.AllowedValues()
+		for i, a := range allowed {
+			for _, b := range allowed[:i] {
+				if a != b && strings.EqualFold(a, b) {
+					return fmt.Errorf("allowed values %s and %s only differ by case", b, a)
+				}
+			}
+		}
+	}
 	if v.Regexp != "" {
 		// Compile and cache regexp, report any compilation error.
 		if v.re, err = regexp.Compile(v.Regexp); err != nil {
@@ -47,29 +151,66 @@ func (v String) Validate(value interface{}) (interface{}, error) {
 	if !ok {
 		return nil, errors.New("not a string")
 	}
-	l := len(s)
-	if l < v.MinLen {
-		return nil, fmt.Errorf("is shorter than %d", v.MinLen)
+	if v.TrimSpace {
+		s = strings.TrimSpace(s)
 	}
-	if v.MaxLen > 0 && l > v.MaxLen {
-		return nil, fmt.Errorf("is longer than %d", v.MaxLen)
+	if v.CollapseWhitespace {
+		s = collapseWhitespace(s)
 	}
-	if len(v.Allowed) > 0 {
+	if v.MinLen > 0 || v.MaxLen > 0 {
+		l := v.LenMeasure.len(s)
+		if l < v.MinLen {
+			msg := fmt.Sprintf("is shorter than %d %s (got %d)", v.MinLen, v.LenMeasure.unit(), l)
+			return nil, FieldError{CodeMinLen, msg, map[string]interface{}{"min": v.MinLen, "len": l}}
+		}
+		if v.MaxLen > 0 && l > v.MaxLen {
+			msg := fmt.Sprintf("is longer than %d %s (got %d)", v.MaxLen, v.LenMeasure.unit(), l)
+			return nil, FieldError{CodeMaxLen, msg, map[string]interface{}{"max": v.MaxLen, "len": l}}
+		}
+	}
+	if allowedValues := v.AllowedValues(); len(allowedValues) > 0 {
 		found := false
-		for _, allowed := range v.Allowed {
-			if s == allowed {
+		for _, allowed := range allowedValues {
+			if s == allowed || (v.AllowedCaseInsensitive && strings.EqualFold(s, allowed)) {
+				// Normalize to the canonical casing.
+				s = allowed
 				found = true
 				break
 			}
 		}
 		if !found {
-			return nil, fmt.Errorf("not one of [%s]", strings.Join(v.Allowed, ", "))
+			msg := fmt.Sprintf("not one of [%s]", strings.Join(allowedValues, ", "))
+			return nil, FieldError{CodeAllowed, msg, map[string]interface{}{"allowed": strings.Join(allowedValues, ", ")}}
 		}
 	}
 	if v.Regexp != "" {
 		if !v.re.MatchString(s) {
-			return nil, fmt.Errorf("does not match %s", v.Regexp)
+			msg := v.RegexpMessage
+			if msg == "" {
+				msg = fmt.Sprintf("does not match %s", v.Regexp)
+			}
+			return nil, FieldError{CodePattern, msg, map[string]interface{}{"pattern": v.Regexp}}
 		}
 	}
 	return s, nil
 }
+
+// collapseWhitespace replaces each sequence of white space characters in s
+// with a single space.
+func collapseWhitespace(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	space := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
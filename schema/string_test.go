@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"encoding/json"
 	"errors"
 	"reflect"
 	"testing"
@@ -19,6 +20,7 @@ func TestStringQueryValidator(t *testing.T) {
 		{`String.ValidateQuery(string)-ouf range`, String{MaxLen: 2}, "foo", "foo", nil},
 		{`String.ValidateQuery(string)-not allowed`, String{Allowed: []string{"bar", "baz"}}, "foo", "foo", nil},
 		{"String.ValidateQuery(int)", String{}, 1, nil, errors.New("not a string")},
+		{"String.ValidateQuery(json.Number)", String{}, json.Number("1"), nil, errors.New("not a string")},
 	}
 	for i := range cases {
 		tt := cases[i]
@@ -40,13 +42,13 @@ func TestStringValidator(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "foo", s)
 	s, err = String{MaxLen: 2}.Validate("foo")
-	assert.EqualError(t, err, "is longer than 2")
+	assert.EqualError(t, err, "is longer than 2 characters (got 3)")
 	assert.Nil(t, s)
 	s, err = String{MaxLen: 4}.Validate("foo")
 	assert.NoError(t, err)
 	assert.Equal(t, "foo", s)
 	s, err = String{MinLen: 4}.Validate("foo")
-	assert.EqualError(t, err, "is shorter than 4")
+	assert.EqualError(t, err, "is shorter than 4 characters (got 3)")
 	assert.Nil(t, s)
 	s, err = String{MinLen: 2}.Validate("foo")
 	assert.NoError(t, err)
@@ -57,6 +59,25 @@ func TestStringValidator(t *testing.T) {
 	s, err = String{Allowed: []string{"bar", "baz"}}.Validate("foo")
 	assert.EqualError(t, err, "not one of [bar, baz]")
 	assert.Nil(t, s)
+	s, err = String{Allowed: []string{"active", "inactive"}}.Validate("Active")
+	assert.EqualError(t, err, "not one of [active, inactive]")
+	assert.Nil(t, s)
+	s, err = String{Allowed: []string{"Active", "inactive"}, AllowedCaseInsensitive: true}.Validate("aCTIVE")
+	assert.NoError(t, err)
+	assert.Equal(t, "Active", s)
+	s, err = String{Allowed: []string{"active", "inactive"}, AllowedCaseInsensitive: true}.Validate("deleted")
+	assert.EqualError(t, err, "not one of [active, inactive]")
+	assert.Nil(t, s)
+	desc := map[string]string{"pending": "Waiting for approval", "active": "In use"}
+	s, err = String{AllowedDescriptions: desc}.Validate("pending")
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", s)
+	s, err = String{Allowed: []string{"inactive"}, AllowedDescriptions: desc, AllowedCaseInsensitive: true}.Validate("PENDING")
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", s)
+	s, err = String{Allowed: []string{"inactive", "active"}, AllowedDescriptions: desc}.Validate("deleted")
+	assert.EqualError(t, err, "not one of [inactive, active, pending]")
+	assert.Nil(t, s)
 	v := String{Regexp: "^f.o$"}
 	assert.NoError(t, v.Compile(nil))
 	s, err = v.Validate("foo")
@@ -67,9 +88,167 @@ func TestStringValidator(t *testing.T) {
 	s, err = v.Validate("foo")
 	assert.EqualError(t, err, "does not match ^bar$")
 	assert.Nil(t, s)
+	v = String{Regexp: "^bar$", RegexpMessage: "must be bar"}
+	assert.NoError(t, v.Compile(nil))
+	s, err = v.Validate("foo")
+	assert.EqualError(t, err, "must be bar")
+	assert.Nil(t, s)
+	// Length constraints are checked before the regexp.
+	v = String{Regexp: "^[a-z]+$", RegexpMessage: "must be lowercase", MinLen: 4}
+	assert.NoError(t, v.Compile(nil))
+	s, err = v.Validate("FOO")
+	assert.EqualError(t, err, "is shorter than 4 characters (got 3)")
+	assert.Nil(t, s)
+	s, err = v.Validate("FOOBAR")
+	assert.EqualError(t, err, "must be lowercase")
+	assert.Nil(t, s)
+	v = String{Regexp: "^[a-z]+$", MaxLen: 4}
+	assert.NoError(t, v.Compile(nil))
+	s, err = v.Validate("foobar")
+	assert.EqualError(t, err, "is longer than 4 characters (got 6)")
+	assert.Nil(t, s)
+	// Allowed values must match the regexp too.
+	v = String{Regexp: "^b", Allowed: []string{"foo", "bar"}}
+	assert.NoError(t, v.Compile(nil))
+	s, err = v.Validate("bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", s)
+	s, err = v.Validate("foo")
+	assert.EqualError(t, err, "does not match ^b")
+	assert.Nil(t, s)
+	s, err = v.Validate("baz")
+	assert.EqualError(t, err, "not one of [foo, bar]")
+	assert.Nil(t, s)
 	v = String{Regexp: "^bar["}
 	assert.EqualError(t, v.Compile(nil), "invalid regexp: error parsing regexp: missing closing ]: `[`")
 	s, err = String{}.ValidateQuery(1)
 	assert.EqualError(t, err, "not a string")
 	assert.Nil(t, s)
 }
+
+func TestStringDescribe(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{"type": "string"}, String{}.Describe())
+	assert.Equal(t, map[string]interface{}{
+		"type":    "string",
+		"regexp":  "^a",
+		"allowed": []string{"a", "ab", "ac"},
+		"minLen":  1,
+		"maxLen":  2,
+	}, String{
+		Regexp:              "^a",
+		Allowed:             []string{"a", "ab"},
+		AllowedDescriptions: map[string]string{"ac": "The ac value"},
+		MinLen:              1,
+		MaxLen:              2,
+	}.Describe())
+	assert.Equal(t, map[string]interface{}{
+		"type":       "string",
+		"maxLen":     10,
+		"lenMeasure": "bytes",
+	}, String{MaxLen: 10, LenMeasure: Bytes}.Describe())
+}
+
+func TestStringLenMeasure(t *testing.T) {
+	cases := []struct {
+		name   string
+		v      String
+		input  string
+		expect string
+	}{
+		{"Runes/ascii", String{MaxLen: 5}, "hello", ""},
+		{"Runes/precomposed", String{MaxLen: 5}, "h\u00e9llo", ""},
+		{"Runes/combining", String{MaxLen: 5}, "he\u0301llo", "is longer than 5 characters (got 6)"},
+		{"Runes/emoji", String{MinLen: 10, MaxLen: 10}, "😀😀😀😀😀😀😀😀😀😀", ""},
+		{"Runes/emoji/short", String{MinLen: 3}, "😀😀", "is shorter than 3 characters (got 2)"},
+		{"Bytes/ascii", String{MaxLen: 5, LenMeasure: Bytes}, "hello", ""},
+		{"Bytes/precomposed", String{MaxLen: 5, LenMeasure: Bytes}, "h\u00e9llo", "is longer than 5 bytes (got 6)"},
+		{"Bytes/combining", String{MaxLen: 6, LenMeasure: Bytes}, "he\u0301llo", "is longer than 6 bytes (got 7)"},
+		{"Bytes/emoji", String{MinLen: 8, LenMeasure: Bytes}, "😀😀", ""},
+		{"Bytes/emoji/short", String{MinLen: 10, LenMeasure: Bytes}, "😀", "is shorter than 10 bytes (got 4)"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := tc.v.Validate(tc.input)
+			if tc.expect != "" {
+				assert.EqualError(t, err, tc.expect)
+				assert.Nil(t, s)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.input, s)
+		})
+	}
+}
+
+func TestStringAllowedCaseInsensitive(t *testing.T) {
+	v := String{Allowed: []string{"Active", "Inactive"}, AllowedCaseInsensitive: true}
+	assert.NoError(t, v.Compile(nil))
+	for _, input := range []string{"active", "ACTIVE", "Active"} {
+		s, err := v.Validate(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, "Active", s, input)
+	}
+	s, err := v.Validate("deleted")
+	assert.EqualError(t, err, "not one of [Active, Inactive]")
+	assert.Nil(t, s)
+
+	v = String{Allowed: []string{"Active", "Inactive"}}
+	assert.NoError(t, v.Compile(nil))
+	s, err = v.Validate("active")
+	assert.EqualError(t, err, "not one of [Active, Inactive]")
+	assert.Nil(t, s)
+
+	v = String{Allowed: []string{"Active", "inactive", "ACTIVE"}, AllowedCaseInsensitive: true}
+	assert.EqualError(t, v.Compile(nil), "allowed values Active and ACTIVE only differ by case")
+	v = String{
+		Allowed:                []string{"active"},
+		AllowedDescriptions:    map[string]string{"Active": "In use"},
+		AllowedCaseInsensitive: true,
+	}
+	assert.EqualError(t, v.Compile(nil), "allowed values active and Active only differ by case")
+	// Values only differing by case are allowed when matching is case
+	// sensitive.
+	v = String{Allowed: []string{"Active", "ACTIVE"}}
+	assert.NoError(t, v.Compile(nil))
+}
+
+func TestStringWhitespace(t *testing.T) {
+	cases := []struct {
+		name   string
+		v      String
+		input  string
+		expect interface{}
+		err    string
+	}{
+		{"None", String{}, "  a  b ", "  a  b ", ""},
+		{"Trim", String{TrimSpace: true}, " \ta  b\n", "a  b", ""},
+		{"Collapse", String{CollapseWhitespace: true}, " a \t\n b  ", " a b ", ""},
+		{"TrimCollapse", String{TrimSpace: true, CollapseWhitespace: true}, "  John   \t Doe ", "John Doe", ""},
+		{"Regexp", String{TrimSpace: true, CollapseWhitespace: true, Regexp: "^[a-z]+ [a-z]+$"}, " foo   bar ", "foo bar", ""},
+		{"MaxLen", String{TrimSpace: true, MaxLen: 3}, "  abc  ", "abc", ""},
+		{"Empty", String{TrimSpace: true}, " \t\n ", "", ""},
+		{"Empty/MinLen", String{TrimSpace: true, CollapseWhitespace: true, MinLen: 1}, "   ", nil, "is shorter than 1 characters (got 0)"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.NoError(t, tc.v.Compile(nil))
+			s, err := tc.v.Validate(tc.input)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expect, s)
+		})
+	}
+}
+
+func TestStringFieldError(t *testing.T) {
+	_, err := String{MinLen: 3}.Validate("ab")
+	assert.Equal(t, FieldError{CodeMinLen, "is shorter than 3 characters (got 2)", map[string]interface{}{"min": 3, "len": 2}}, err)
+	v := String{Regexp: "^a", RegexpMessage: "must start with a"}
+	assert.NoError(t, v.Compile(nil))
+	_, err = v.Validate("b")
+	assert.Equal(t, FieldError{CodePattern, "must start with a", map[string]interface{}{"pattern": "^a"}}, err)
+	assert.Equal(t, "must start with a", err.(FieldError).String())
+}
@@ -0,0 +1,225 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Struct validates objects against the fields of a Go struct and returns a
+// populated copy of the struct instead of a map[string]interface{}.
+//
+// Object keys are mapped to the struct fields following the encoding/json
+// rules: json tags are respected and the fields of embedded structs are
+// promoted. Keys with no matching field are rejected, including in nested
+// structs.
+type Struct struct {
+	// Prototype is a value (or a pointer to a value) of the struct type to
+	// bind objects to. The returned value has the same type as Prototype.
+	Prototype interface{}
+
+	typ    reflect.Type
+	fields map[string][]int
+}
+
+// Compile implements the Compiler interface.
+func (v *Struct) Compile(rc ReferenceChecker) error {
+	t, err := structType(v.Prototype)
+	if err != nil {
+		return err
+	}
+	v.typ = t
+	v.fields = structFields(t)
+	return nil
+}
+
+// Validate implements FieldValidator interface.
+func (v Struct) Validate(value interface{}) (interface{}, error) {
+	if v.typ == nil {
+		if err := v.Compile(nil); err != nil {
+			return nil, err
+		}
+	}
+	if value != nil && reflect.TypeOf(value) == reflect.TypeOf(v.Prototype) {
+		// Already bound value, i.e.: stored value validated on update.
+		return value, nil
+	}
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("not an object")
+	}
+	ptr := reflect.New(v.typ)
+	errs := ErrorMap{}
+	for key, val := range obj {
+		index, found := v.fields[key]
+		if !found {
+			errs[key] = []interface{}{ValidationError{CodeInvalidField, "invalid field", key, nil}}
+			continue
+		}
+		f := fieldByIndexAlloc(ptr.Elem(), index)
+		if err := assignJSONValue(f, val); err != nil {
+			errs[key] = []interface{}{ValidationError{CodeValidator, err.Error(), key, nil}}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if reflect.TypeOf(v.Prototype).Kind() == reflect.Ptr {
+		return ptr.Interface(), nil
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+// structType returns the struct type of prototype.
+func structType(prototype interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(prototype)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.New("Prototype must be a struct or a pointer to a struct")
+	}
+	return t, nil
+}
+
+// structFields returns the index of the fields of t by JSON name, including
+// the promoted fields of embedded structs. As with encoding/json, a field
+// shadows the fields with the same name at a deeper level, and among several
+// fields with the same name at the same level, the only one with a json tag
+// wins. Names left ambiguous are dropped.
+func structFields(t reflect.Type) map[string][]int {
+	type embeddedStruct struct {
+		typ   reflect.Type
+		index []int
+	}
+	candidates := map[string][]structField{}
+	visited := map[reflect.Type]bool{}
+	next := []embeddedStruct{{t, nil}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		for _, es := range current {
+			if visited[es.typ] {
+				// Already walked at a shallower level.
+				continue
+			}
+			for i := 0; i < es.typ.NumField(); i++ {
+				sf := es.typ.Field(i)
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name := strings.Split(tag, ",")[0]
+				idx := append(append([]int{}, es.index...), i)
+				ft := sf.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+					if sf.PkgPath != "" && sf.Type.Kind() == reflect.Ptr {
+						// Pointers to unexported structs can't be allocated.
+						continue
+					}
+					// Promote embedded struct fields at the next level.
+					next = append(next, embeddedStruct{ft, idx})
+					continue
+				}
+				if sf.PkgPath != "" {
+					// Unexported field.
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = sf.Name
+				}
+				candidates[name] = append(candidates[name], structField{idx, tagged})
+			}
+		}
+		for _, es := range current {
+			visited[es.typ] = true
+		}
+	}
+	fields := make(map[string][]int, len(candidates))
+	for name, cs := range candidates {
+		if idx, ok := dominantField(cs); ok {
+			fields[name] = idx
+		}
+	}
+	return fields
+}
+
+// structField is a struct field candidate for a JSON name.
+type structField struct {
+	index []int
+	// tagged is true if the name comes from a json tag.
+	tagged bool
+}
+
+// dominantField returns the index of the field of fields, sorted by depth,
+// which wins for their common JSON name, or false if the name is ambiguous.
+func dominantField(fields []structField) ([]int, bool) {
+	var dominant []int
+	n, tagged := 0, 0
+	for _, f := range fields {
+		if len(f.index) > len(fields[0].index) {
+			break
+		}
+		n++
+		if f.tagged {
+			tagged++
+			dominant = f.index
+		}
+	}
+	switch {
+	case n == 1:
+		return fields[0].index, true
+	case tagged == 1:
+		return dominant, true
+	}
+	return nil, false
+}
+
+// fieldByIndexAlloc is like reflect.Value.FieldByIndex but allocates the nil
+// embedded struct pointers on the way.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// assignJSONValue sets f to val, a value decoded from JSON, converting it to
+// the type of f.
+func assignJSONValue(f reflect.Value, val interface{}) error {
+	if val != nil {
+		if rv := reflect.ValueOf(val); rv.Type().AssignableTo(f.Type()) {
+			f.Set(rv)
+			return nil
+		}
+	}
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	ptr := reflect.New(f.Type())
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(ptr.Interface()); err != nil {
+		if msg := err.Error(); strings.HasPrefix(msg, "json: unknown field ") {
+			// Keys with no matching field are rejected in nested structs too.
+			return fmt.Errorf("invalid value, %s", strings.TrimPrefix(msg, "json: "))
+		}
+		return fmt.Errorf("invalid value, expected %s", f.Type())
+	}
+	f.Set(ptr.Elem())
+	return nil
+}
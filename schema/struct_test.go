@@ -0,0 +1,169 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+type testStructBase struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
+
+type StructTestMeta struct {
+	Tags []string `json:"tags"`
+}
+
+type testStruct struct {
+	testStructBase
+	*StructTestMeta
+	Name     string            `json:"name"`
+	Nickname *string           `json:"nickname,omitempty"`
+	Age      int               `json:"age"`
+	Address  *testStructAddr   `json:"address"`
+	Labels   map[string]string `json:"labels"`
+	Secret   string            `json:"-"`
+	Raw      string
+	internal string
+}
+
+type testStructAddr struct {
+	City string `json:"city"`
+}
+
+type testStructNameA struct {
+	Name  string
+	Label string
+	A     string `json:"a"`
+}
+
+type testStructNameB struct {
+	Name  string
+	Other string `json:"Label"`
+	B     string `json:"b"`
+}
+
+// testStructShadow embeds two structs with untagged Name fields, which are
+// ambiguous, and "Label" fields of which only one is tagged.
+type testStructShadow struct {
+	testStructNameA
+	testStructNameB
+}
+
+func TestStructCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{Name: "{Prototype:struct}", Compiler: &schema.Struct{Prototype: testStruct{}}},
+		{Name: "{Prototype:*struct}", Compiler: &schema.Struct{Prototype: &testStruct{}}},
+		{
+			Name:     "{Prototype:nil}",
+			Compiler: &schema.Struct{},
+			Error:    "Prototype must be a struct or a pointer to a struct",
+		},
+		{
+			Name:     "{Prototype:string}",
+			Compiler: &schema.Struct{Prototype: ""},
+			Error:    "Prototype must be a struct or a pointer to a struct",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestStructValidate(t *testing.T) {
+	nick := "Johnny"
+	cases := []fieldValidatorTestCase{
+		{
+			Name:      "Validate(map)",
+			Validator: &schema.Struct{Prototype: testStruct{}},
+			Input: map[string]interface{}{
+				"name":    "John",
+				"age":     float64(42),
+				"Raw":     "raw",
+				"labels":  map[string]interface{}{"a": "b"},
+				"address": map[string]interface{}{"city": "Paris"},
+			},
+			Expect: testStruct{
+				Name:    "John",
+				Age:     42,
+				Raw:     "raw",
+				Labels:  map[string]string{"a": "b"},
+				Address: &testStructAddr{City: "Paris"},
+			},
+		},
+		{
+			Name:      "Validate(embedded)",
+			Validator: &schema.Struct{Prototype: testStruct{}},
+			Input: map[string]interface{}{
+				"id":      "abc",
+				"version": 2,
+				"tags":    []interface{}{"x", "y"},
+			},
+			Expect: testStruct{
+				testStructBase: testStructBase{ID: "abc", Version: 2},
+				StructTestMeta: &StructTestMeta{Tags: []string{"x", "y"}},
+			},
+		},
+		{
+			Name:      "Validate(pointer)",
+			Validator: &schema.Struct{Prototype: testStruct{}},
+			Input:     map[string]interface{}{"nickname": "Johnny", "address": nil},
+			Expect:    testStruct{Nickname: &nick},
+		},
+		{
+			Name:      "{Prototype:*struct}.Validate(map)",
+			Validator: &schema.Struct{Prototype: &testStruct{}},
+			Input:     map[string]interface{}{"name": "John"},
+			Expect:    &testStruct{Name: "John"},
+		},
+		{
+			Name:      "Validate(struct)",
+			Validator: &schema.Struct{Prototype: testStruct{}},
+			Input:     testStruct{Name: "John"},
+			Expect:    testStruct{Name: "John"},
+		},
+		{
+			Name:      "Validate(string)",
+			Validator: &schema.Struct{Prototype: testStruct{}},
+			Input:     "John",
+			Error:     "not an object",
+		},
+		{
+			Name:      "Validate(unknown field)",
+			Validator: &schema.Struct{Prototype: testStruct{}},
+			Input:     map[string]interface{}{"name": "John", "Secret": "x", "internal": "x"},
+			Error:     "Secret is [invalid field], internal is [invalid field]",
+		},
+		{
+			Name:      "Validate(nested unknown field)",
+			Validator: &schema.Struct{Prototype: testStruct{}},
+			Input:     map[string]interface{}{"address": map[string]interface{}{"city": "Paris", "zip": "75001"}},
+			Error:     `address is [invalid value, unknown field "zip"]`,
+		},
+		{
+			Name:      "{Prototype:shadow}.Validate(map)",
+			Validator: &schema.Struct{Prototype: testStructShadow{}},
+			Input:     map[string]interface{}{"a": "a", "b": "b", "Label": "label"},
+			Expect: testStructShadow{
+				testStructNameA: testStructNameA{A: "a"},
+				testStructNameB: testStructNameB{B: "b", Other: "label"},
+			},
+		},
+		{
+			Name:      "{Prototype:shadow}.Validate(ambiguous)",
+			Validator: &schema.Struct{Prototype: testStructShadow{}},
+			Input:     map[string]interface{}{"Name": "John"},
+			Error:     "Name is [invalid field]",
+		},
+		{
+			Name:      "Validate(invalid type)",
+			Validator: &schema.Struct{Prototype: testStruct{}},
+			Input:     map[string]interface{}{"age": "42", "address": "Paris"},
+			Error:     "address is [invalid value, expected *schema_test.testStructAddr], age is [invalid value, expected int]",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
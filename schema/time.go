@@ -2,7 +2,9 @@ package schema
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"math"
 	"time"
 )
 
@@ -53,35 +55,101 @@ var (
 type Time struct {
 	TimeLayouts []string // TimeLayouts is set of time layouts we want to validate.
 	layouts     []string
+	// InLocation, when set, normalizes parsed times to the given location.
+	// String values with no timezone information are interpreted in this
+	// location instead of UTC. Use time.UTC to store all times in UTC.
+	InLocation *time.Location
+	// Truncate, when set, rounds parsed times down to a multiple of the given
+	// duration (i.e.: time.Second to strip sub-second precision).
+	Truncate time.Duration
+	// OutputLayout defines the layout used by Serialize (default
+	// time.RFC3339Nano).
+	OutputLayout string
+	// Epoch, when set to time.Second or time.Millisecond, accepts integer
+	// values as Unix timestamps in this unit. The resulting times are in UTC.
+	Epoch time.Duration
 }
 
 // Compile the time formats.
 func (v *Time) Compile(rc ReferenceChecker) error {
-	if len(v.TimeLayouts) == 0 {
+	if v.Epoch != 0 && v.Epoch != time.Second && v.Epoch != time.Millisecond {
+		return errors.New("Epoch must be time.Second or time.Millisecond")
+	}
+	if v.TimeLayouts == nil {
 		// default layouts to all formats.
 		v.layouts = formats
 		return nil
 	}
+	if len(v.TimeLayouts) == 0 {
+		return errors.New("TimeLayouts must not be empty")
+	}
 	// User specified list of time layouts.
+	v.layouts = make([]string, 0, len(v.TimeLayouts))
 	for _, layout := range v.TimeLayouts {
-		v.layouts = append(v.layouts, string(layout))
+		if layout == "" {
+			return errors.New("empty time layout")
+		}
+		v.layouts = append(v.layouts, layout)
 	}
 	return nil
 }
 
 func (v Time) parse(value interface{}) (interface{}, error) {
 	if s, ok := value.(string); ok {
+		loc := time.UTC
+		if v.InLocation != nil {
+			loc = v.InLocation
+		}
 		for _, layout := range v.layouts {
-			if t, err := time.Parse(layout, s); err == nil {
+			if t, err := time.ParseInLocation(layout, s, loc); err == nil {
 				value = t
 				break
 			}
 		}
+	} else if v.Epoch != 0 {
+		if n, ok := epochValue(value); ok {
+			value = v.fromEpoch(n)
+		}
 	}
-	if _, ok := value.(time.Time); !ok {
+	t, ok := value.(time.Time)
+	if !ok {
 		return nil, errors.New("not a time")
 	}
-	return value, nil
+	if v.Truncate > 0 {
+		t = t.Truncate(v.Truncate)
+	}
+	if v.InLocation != nil {
+		t = t.In(v.InLocation)
+	}
+	return t, nil
+}
+
+// fromEpoch returns the time of the Unix timestamp n expressed in v.Epoch
+// units.
+func (v Time) fromEpoch(n int64) time.Time {
+	if v.Epoch == time.Millisecond {
+		return time.Unix(n/1e3, n%1e3*1e6).UTC()
+	}
+	return time.Unix(n, 0).UTC()
+}
+
+// epochValue returns value as an int64 if it is an integer number.
+func epochValue(value interface{}) (int64, bool) {
+	switch n := value.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	}
+	return 0, false
 }
 
 // ValidateQuery implements schema.FieldQueryValidator interface
@@ -94,6 +162,19 @@ func (v Time) Validate(value interface{}) (interface{}, error) {
 	return v.parse(value)
 }
 
+// Serialize implements FieldSerializer.
+func (v Time) Serialize(value interface{}) (interface{}, error) {
+	t, err := v.get(value)
+	if err != nil {
+		return nil, err
+	}
+	layout := v.OutputLayout
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+	return t.Format(layout), nil
+}
+
 func (v Time) get(value interface{}) (time.Time, error) {
 	t, ok := value.(time.Time)
 	if !ok {
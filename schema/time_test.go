@@ -1,6 +1,7 @@
 package schema_test
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -97,3 +98,203 @@ func TestTimeLess(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{Name: "{}", Compiler: &schema.Time{}},
+		{
+			Name:     "{TimeLayouts:[]}",
+			Compiler: &schema.Time{TimeLayouts: []string{}},
+			Error:    "TimeLayouts must not be empty",
+		},
+		{
+			Name:     `{TimeLayouts:[""]}`,
+			Compiler: &schema.Time{TimeLayouts: []string{""}},
+			Error:    "empty time layout",
+		},
+		{Name: "{Epoch:Second}", Compiler: &schema.Time{Epoch: time.Second}},
+		{Name: "{Epoch:Millisecond}", Compiler: &schema.Time{Epoch: time.Millisecond}},
+		{
+			Name:     "{Epoch:Minute}",
+			Compiler: &schema.Time{Epoch: time.Minute},
+			Error:    "Epoch must be time.Second or time.Millisecond",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestTimeLayoutOrder(t *testing.T) {
+	// "03/04/2020" is valid in both layouts; the first matching one wins.
+	us := schema.Time{TimeLayouts: []string{"01/02/2006", "02/01/2006"}}
+	eu := schema.Time{TimeLayouts: []string{"02/01/2006", "01/02/2006"}}
+	assert.NoError(t, us.Compile(nil))
+	assert.NoError(t, eu.Compile(nil))
+
+	v, err := us.Validate("03/04/2020")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, time.March, 4, 0, 0, 0, 0, time.UTC), v)
+	v, err = eu.Validate("03/04/2020")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, time.April, 3, 0, 0, 0, 0, time.UTC), v)
+
+	// Only the second layout accepts a month of 13.
+	v, err = us.Validate("13/04/2020")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, time.April, 13, 0, 0, 0, 0, time.UTC), v)
+}
+
+func TestTimeInLocation(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone database not available: %v", err)
+	}
+	timeT := schema.Time{
+		TimeLayouts: []string{time.RFC3339, "2006-01-02 15:04"},
+		InLocation:  ny,
+	}
+	assert.NoError(t, timeT.Compile(nil))
+
+	cases := []struct {
+		input  string
+		expect string
+	}{
+		// Times with an offset are converted to the target location, on both
+		// sides of the DST boundary (2021-03-14 07:00 UTC).
+		{"2021-03-14T06:59:00Z", "2021-03-14T01:59:00-05:00"},
+		{"2021-03-14T07:00:00Z", "2021-03-14T03:00:00-04:00"},
+		{"2021-11-07T06:00:00Z", "2021-11-07T01:00:00-05:00"},
+		// Times without an offset are interpreted in the target location.
+		{"2021-03-13 12:00", "2021-03-13T12:00:00-05:00"},
+		{"2021-03-15 12:00", "2021-03-15T12:00:00-04:00"},
+	}
+	for _, tc := range cases {
+		v, err := timeT.Validate(tc.input)
+		if !assert.NoError(t, err, tc.input) {
+			continue
+		}
+		tm := v.(time.Time)
+		assert.Equal(t, ny, tm.Location(), tc.input)
+		assert.Equal(t, tc.expect, tm.Format(time.RFC3339), tc.input)
+	}
+
+	// Wall clock times in the spring forward gap do not exist; they are
+	// resolved to one of the offsets surrounding the gap.
+	v, err := timeT.Validate("2021-03-14 02:30")
+	assert.NoError(t, err)
+	assert.Contains(t, []string{
+		"2021-03-14T01:30:00-05:00",
+		"2021-03-14T03:30:00-04:00",
+	}, v.(time.Time).Format(time.RFC3339))
+}
+
+func TestTimeTruncate(t *testing.T) {
+	timeT := schema.Time{Truncate: time.Second}
+	assert.NoError(t, timeT.Compile(nil))
+
+	v, err := timeT.Validate("2021-03-14T06:59:00.999999999Z")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, time.March, 14, 6, 59, 0, 0, time.UTC), v)
+
+	in := time.Date(2021, time.March, 14, 6, 59, 30, 500, time.UTC)
+	v, err = timeT.Validate(in)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, time.March, 14, 6, 59, 30, 0, time.UTC), v)
+
+	timeT = schema.Time{Truncate: time.Hour}
+	assert.NoError(t, timeT.Compile(nil))
+	v, err = timeT.Validate("2021-03-14T06:59:59Z")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, time.March, 14, 6, 0, 0, 0, time.UTC), v)
+}
+
+func TestTimeSerialize(t *testing.T) {
+	tm := time.Date(2021, time.March, 14, 6, 59, 0, 5, time.UTC)
+	cases := []fieldSerializerTestCase{
+		{
+			Name:       "Serialize(time.Time)",
+			Serializer: &schema.Time{},
+			Input:      tm,
+			Expect:     "2021-03-14T06:59:00.000000005Z",
+		},
+		{
+			Name:       "{OutputLayout:RFC1123}.Serialize(time.Time)",
+			Serializer: &schema.Time{OutputLayout: time.RFC1123},
+			Input:      tm,
+			Expect:     "Sun, 14 Mar 2021 06:59:00 UTC",
+		},
+		{
+			Name:       "Serialize(string)",
+			Serializer: &schema.Time{},
+			Input:      "2021-03-14",
+			Error:      "not a time",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestTimeEpoch(t *testing.T) {
+	tm := time.Date(2021, time.March, 14, 6, 59, 0, 0, time.UTC)
+	cases := []fieldValidatorTestCase{
+		{
+			Name:      "{}.Validate(float64)",
+			Validator: &schema.Time{},
+			Input:     float64(1615705140),
+			Error:     "not a time",
+		},
+		{
+			Name:      "{Epoch:Second}.Validate(float64)",
+			Validator: &schema.Time{Epoch: time.Second},
+			Input:     float64(1615705140),
+			Expect:    tm,
+		},
+		{
+			Name:      "{Epoch:Second}.Validate(int)",
+			Validator: &schema.Time{Epoch: time.Second},
+			Input:     1615705140,
+			Expect:    tm,
+		},
+		{
+			Name:      "{Epoch:Second}.Validate(json.Number)",
+			Validator: &schema.Time{Epoch: time.Second},
+			Input:     json.Number("1615705140"),
+			Expect:    tm,
+		},
+		{
+			Name:      "{Epoch:Second}.Validate(fractional float64)",
+			Validator: &schema.Time{Epoch: time.Second},
+			Input:     1615705140.5,
+			Error:     "not a time",
+		},
+		{
+			Name:      "{Epoch:Second}.Validate(string)",
+			Validator: &schema.Time{Epoch: time.Second},
+			Input:     "2021-03-14T06:59:00Z",
+			Expect:    tm,
+		},
+		{
+			Name:      "{Epoch:Millisecond}.Validate(int64)",
+			Validator: &schema.Time{Epoch: time.Millisecond},
+			Input:     int64(1615705140123),
+			Expect:    tm.Add(123 * time.Millisecond),
+		},
+		{
+			Name:      "{Epoch:Millisecond}.Validate(negative int64)",
+			Validator: &schema.Time{Epoch: time.Millisecond},
+			Input:     int64(-1500),
+			Expect:    time.Date(1969, time.December, 31, 23, 59, 58, 500000000, time.UTC),
+		},
+		{
+			Name:      "{Epoch:Millisecond,Truncate:Second}.Validate(int64)",
+			Validator: &schema.Time{Epoch: time.Millisecond, Truncate: time.Second},
+			Input:     int64(1615705140123),
+			Expect:    tm,
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Timezone validates IANA time zone names such as "Europe/Paris". Valid names
+// are normalized to their canonical form: case is fixed (i.e.: "europe/paris"
+// is stored as "Europe/Paris") and deprecated aliases are replaced by the name
+// they link to (i.e.: "US/Eastern" is stored as "America/New_York").
+type Timezone struct {
+	// Strict rejects deprecated aliases as well as the "UTC" and "Local"
+	// names.
+	Strict bool
+	// AllowFallback lets Compile succeed when the system has no tz database.
+	// Names are then validated against the list embedded in this package
+	// only. Use UsesFallback to know if the fallback has been selected.
+	AllowFallback bool
+
+	fallback bool
+}
+
+var (
+	timezoneIndex      map[string]string
+	timezoneAliasIndex map[string]string
+)
+
+func init() {
+	timezoneIndex = make(map[string]string, len(timezones))
+	for _, name := range timezones {
+		timezoneIndex[strings.ToLower(name)] = name
+	}
+	timezoneAliasIndex = make(map[string]string, len(timezoneAliases))
+	for alias := range timezoneAliases {
+		timezoneAliasIndex[strings.ToLower(alias)] = alias
+	}
+}
+
+// Compile implements the Compiler interface.
+func (v *Timezone) Compile(rc ReferenceChecker) error {
+	v.fallback = false
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		if !v.AllowFallback {
+			return fmt.Errorf("timezone database not available: %v", err)
+		}
+		v.fallback = true
+	}
+	return nil
+}
+
+// UsesFallback returns true if Compile found no tz database on the system and
+// selected the embedded list of names instead.
+func (v Timezone) UsesFallback() bool {
+	return v.fallback
+}
+
+// Validate validates and normalizes time zone names.
+func (v Timezone) Validate(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("not a string")
+	}
+	if s == "UTC" || s == "Local" {
+		if v.Strict {
+			return nil, fmt.Errorf("%s is not allowed, use a location name", s)
+		}
+		return s, nil
+	}
+	name, found := timezoneIndex[strings.ToLower(s)]
+	if !found {
+		if alias, isAlias := timezoneAliasIndex[strings.ToLower(s)]; isAlias {
+			name = timezoneAliases[alias]
+			if v.Strict {
+				return nil, fmt.Errorf("deprecated timezone, use %s", name)
+			}
+		} else if !v.fallback && s != "" {
+			// The zone may be more recent than the embedded list.
+			name = s
+		} else {
+			return nil, errors.New("unknown timezone")
+		}
+	}
+	if !v.fallback {
+		if _, err := time.LoadLocation(name); err != nil {
+			return nil, errors.New("unknown timezone")
+		}
+	}
+	return name, nil
+}
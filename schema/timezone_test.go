@@ -0,0 +1,67 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestTimezoneCompile(t *testing.T) {
+	v := &schema.Timezone{}
+	if err := v.Compile(nil); err != nil {
+		t.Skipf("timezone database not available: %v", err)
+	}
+	if v.UsesFallback() {
+		t.Error("expected the system tz database to be used")
+	}
+	v = &schema.Timezone{AllowFallback: true}
+	if err := v.Compile(nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTimezoneValidate(t *testing.T) {
+	cases := []fieldValidatorTestCase{
+		{Name: `Validate("Europe/Paris")`, Validator: &schema.Timezone{}, Input: "Europe/Paris", Expect: "Europe/Paris"},
+		{Name: `Validate("america/new_york")`, Validator: &schema.Timezone{}, Input: "america/new_york", Expect: "America/New_York"},
+		{Name: `Validate("Etc/GMT+5")`, Validator: &schema.Timezone{}, Input: "Etc/GMT+5", Expect: "Etc/GMT+5"},
+		{Name: `Validate("US/Eastern")`, Validator: &schema.Timezone{}, Input: "US/Eastern", Expect: "America/New_York"},
+		{Name: `Validate("Asia/Calcutta")`, Validator: &schema.Timezone{}, Input: "Asia/Calcutta", Expect: "Asia/Kolkata"},
+		{Name: `Validate("UTC")`, Validator: &schema.Timezone{}, Input: "UTC", Expect: "UTC"},
+		{Name: `Validate("Local")`, Validator: &schema.Timezone{}, Input: "Local", Expect: "Local"},
+		{Name: `Validate(1)`, Validator: &schema.Timezone{}, Input: 1, Error: "not a string"},
+		{Name: `Validate("")`, Validator: &schema.Timezone{}, Input: "", Error: "unknown timezone"},
+		{Name: `Validate("Mars/Olympus_Mons")`, Validator: &schema.Timezone{}, Input: "Mars/Olympus_Mons", Error: "unknown timezone"},
+		{Name: `Validate("../etc/passwd")`, Validator: &schema.Timezone{}, Input: "../etc/passwd", Error: "unknown timezone"},
+		{
+			Name:      `{Strict:true}.Validate("Europe/Paris")`,
+			Validator: &schema.Timezone{Strict: true},
+			Input:     "Europe/Paris",
+			Expect:    "Europe/Paris",
+		},
+		{
+			Name:      `{Strict:true}.Validate("US/Eastern")`,
+			Validator: &schema.Timezone{Strict: true},
+			Input:     "US/Eastern",
+			Error:     "deprecated timezone, use America/New_York",
+		},
+		{
+			Name:      `{Strict:true}.Validate("UTC")`,
+			Validator: &schema.Timezone{Strict: true},
+			Input:     "UTC",
+			Error:     "UTC is not allowed, use a location name",
+		},
+		{
+			Name:      `{Strict:true}.Validate("Local")`,
+			Validator: &schema.Timezone{Strict: true},
+			Input:     "Local",
+			Error:     "Local is not allowed, use a location name",
+		},
+	}
+	if err := (&schema.Timezone{}).Compile(nil); err != nil {
+		t.Skipf("timezone database not available: %v", err)
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
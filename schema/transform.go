@@ -0,0 +1,43 @@
+package schema
+
+import "strings"
+
+// Transformer transforms the value of a field received from the client before
+// it is validated (see Field.Transform).
+type Transformer interface {
+	Transform(value interface{}) interface{}
+}
+
+// TransformerFunc adapts a function to the Transformer interface.
+type TransformerFunc func(value interface{}) interface{}
+
+// Transform implements the Transformer interface.
+func (f TransformerFunc) Transform(value interface{}) interface{} {
+	return f(value)
+}
+
+// StringTransformer adapts a string function to the Transformer interface.
+// Values which are not strings are returned as is. For instance, Unicode NFC
+// normalization can be applied with StringTransformer(norm.NFC.String) using
+// the golang.org/x/text/unicode/norm package.
+type StringTransformer func(s string) string
+
+// Transform implements the Transformer interface.
+func (f StringTransformer) Transform(value interface{}) interface{} {
+	if s, ok := value.(string); ok {
+		return f(s)
+	}
+	return value
+}
+
+var (
+	// TrimSpace removes leading and trailing white space from string values.
+	TrimSpace Transformer = StringTransformer(strings.TrimSpace)
+	// ToLower lowercases string values.
+	ToLower Transformer = StringTransformer(strings.ToLower)
+	// ToUpper uppercases string values.
+	ToUpper Transformer = StringTransformer(strings.ToUpper)
+	// CollapseWhitespace replaces each sequence of white space characters in
+	// string values with a single space.
+	CollapseWhitespace Transformer = StringTransformer(collapseWhitespace)
+)
@@ -3,18 +3,32 @@ package schema
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"strings"
 )
 
 // URL validates URLs values.
 type URL struct {
-	AllowRelative  bool
-	AllowLocale    bool
-	AllowNonHTTP   bool
+	// AllowRelative allows relative URLs.
+	AllowRelative bool
+	// AllowLocale allows local hosts, i.e.: domains without a dot (like
+	// localhost) or under localhost, as well as loopback, private and
+	// link-local IP addresses.
+	// Leave it disabled for fields used to issue requests from the server.
+	AllowLocale bool
+	// AllowNonHTTP allows schemes other than http and https.
+	AllowNonHTTP bool
+	// AllowedSchemes restricts the accepted schemes to the given list. When
+	// set, AllowNonHTTP is ignored.
 	AllowedSchemes []string
 }
 
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
 // Validate validates URL values.
 func (v URL) Validate(value interface{}) (interface{}, error) {
 	str, ok := value.(string)
@@ -28,8 +42,16 @@ func (v URL) Validate(value interface{}) (interface{}, error) {
 	if !v.AllowRelative && !u.IsAbs() {
 		return nil, errors.New("is relative URL")
 	}
-	if !v.AllowLocale && strings.IndexByte(u.Host, '.') == -1 {
-		return nil, errors.New("invalid domain")
+	if !v.AllowLocale {
+		// The trailing dot of fully qualified names is ignored.
+		host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+		if ip := net.ParseIP(host); ip != nil {
+			if isLocalIP(ip) {
+				return nil, errors.New("local address not allowed")
+			}
+		} else if strings.IndexByte(host, '.') == -1 || strings.HasSuffix(host, ".localhost") {
+			return nil, errors.New("invalid domain")
+		}
 	}
 	if len(v.AllowedSchemes) > 0 {
 		found := false
@@ -45,5 +67,17 @@ func (v URL) Validate(value interface{}) (interface{}, error) {
 	} else if !v.AllowNonHTTP && u.Scheme != "http" && u.Scheme != "https" {
 		return nil, errors.New("invalid scheme")
 	}
+	// Normalize host case and strip the scheme's default port.
+	u.Host = strings.ToLower(u.Host)
+	if port := u.Port(); port != "" && port == defaultPorts[u.Scheme] {
+		u.Host = strings.TrimSuffix(u.Host, ":"+port)
+	}
 	return u.String(), nil
 }
+
+// isLocalIP returns true if ip is a loopback, private, link-local or
+// unspecified address.
+func isLocalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()
+}
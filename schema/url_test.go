@@ -26,7 +26,7 @@ func TestURLValidator(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "http://foo.com/bar", u)
 	u, err = URL{}.Validate(":foo")
-	assert.EqualError(t, err, "invalid URL: parse :foo: missing protocol scheme")
+	assert.EqualError(t, err, `invalid URL: parse ":foo": missing protocol scheme`)
 	assert.Nil(t, u)
 	u, err = URL{}.Validate(1)
 	assert.EqualError(t, err, "invalid type")
@@ -37,4 +37,65 @@ func TestURLValidator(t *testing.T) {
 	u, err = URL{AllowedSchemes: []string{"foo"}}.Validate("http://foo.com/bar")
 	assert.EqualError(t, err, "invalid scheme")
 	assert.Nil(t, u)
+	u, err = URL{}.Validate("HTTP://Foo.COM:80/Bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://foo.com/Bar", u)
+	u, err = URL{}.Validate("https://foo.com:443/bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://foo.com/bar", u)
+	u, err = URL{}.Validate("https://foo.com:8443/bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://foo.com:8443/bar", u)
+	for _, local := range []string{"http://127.0.0.1/", "http://10.1.2.3/", "http://192.168.0.1:8080/", "http://169.254.169.254/", "http://0.0.0.0/"} {
+		u, err = URL{}.Validate(local)
+		assert.EqualError(t, err, "local address not allowed", local)
+		assert.Nil(t, u)
+		u, err = URL{AllowLocale: true}.Validate(local)
+		assert.NoError(t, err, local)
+		assert.Equal(t, local, u)
+	}
+	for _, local := range []string{"http://localhost./x", "http://LOCALHOST.:80/", "http://foo.localhost/", "http://foo.localhost./", "http://intranet./"} {
+		u, err = URL{}.Validate(local)
+		assert.EqualError(t, err, "invalid domain", local)
+		assert.Nil(t, u)
+	}
+	u, err = URL{AllowLocale: true}.Validate("http://LOCALHOST.:80/")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost./", u)
+	u, err = URL{}.Validate("http://foo.com./bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://foo.com./bar", u)
+	u, err = URL{}.Validate("http://8.8.8.8/")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://8.8.8.8/", u)
+}
+
+func TestURLValidatorSubSchema(t *testing.T) {
+	s := Schema{
+		Fields: Fields{
+			"link": {
+				Schema: &Schema{
+					Fields: Fields{
+						"href": {Required: true, Validator: &URL{}},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	f := s.GetField("link.href")
+	if assert.NotNil(t, f) {
+		assert.IsType(t, &URL{}, f.Validator)
+	}
+	doc, errs := s.Validate(map[string]interface{}{
+		"link": map[string]interface{}{"href": "HTTP://Foo.com:80/"},
+	}, map[string]interface{}{})
+	assert.Len(t, errs, 0)
+	assert.Equal(t, map[string]interface{}{"link": map[string]interface{}{"href": "http://foo.com/"}}, doc)
+	_, errs = s.Validate(map[string]interface{}{
+		"link": map[string]interface{}{},
+	}, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{
+		"link": {map[string][]interface{}{"href": {ValidationError{CodeRequired, "required", "href", nil}}}},
+	}, errs)
 }
@@ -14,3 +14,36 @@ func splitFieldPath(name string) (string, string, bool) {
 	}
 	return name, "", false
 }
+
+// isNumber takes an interface as input, and returns a float64 if the type is
+// compatible (int* or float*).
+func isNumber(n interface{}) (float64, bool) {
+	switch n := n.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
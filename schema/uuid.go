@@ -0,0 +1,143 @@
+package schema
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// UUID validates RFC 4122 UUID values and normalizes them to their lowercase
+// hyphenated canonical form. The hyphenated form, the 32 hexadecimal digits
+// form and their variants enclosed in braces are accepted.
+type UUID struct {
+	// Versions restricts the accepted UUID versions (1 to 8). When empty, any
+	// well-formed UUID is accepted.
+	Versions []int
+	// StoreBinary activates storage of the UUID as its 16 bytes binary
+	// representation to save space.
+	StoreBinary bool
+}
+
+// Compile implements the Compiler interface.
+func (v *UUID) Compile(rc ReferenceChecker) error {
+	for _, version := range v.Versions {
+		if version < 1 || version > 8 {
+			return fmt.Errorf("unsupported UUID version: %d", version)
+		}
+	}
+	return nil
+}
+
+// Validate implements FieldValidator.
+func (v UUID) Validate(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("invalid type")
+	}
+	u, err := parseUUID(s)
+	if err != nil || !v.allowed(u) {
+		return nil, v.error()
+	}
+	return v.value(u), nil
+}
+
+// GenerateFunc returns a new random (version 4) UUID in the format stored by
+// v. It can be used as the DefaultFunc of the field:
+//
+//     "id": {
+//         DefaultFunc: schema.UUID{}.GenerateFunc,
+//         Validator:   &schema.UUID{},
+//     },
+func (v UUID) GenerateFunc(ctx context.Context) interface{} {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		panic(fmt.Sprintf("cannot generate UUID: %v", err))
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return v.value(u)
+}
+
+// value returns u in the format stored by v.
+func (v UUID) value(u [16]byte) interface{} {
+	if v.StoreBinary {
+		return u[:]
+	}
+	return formatUUID(u)
+}
+
+// Serialize implements FieldSerializer.
+func (v UUID) Serialize(value interface{}) (interface{}, error) {
+	switch t := value.(type) {
+	case string:
+		return t, nil
+	case [16]byte:
+		return formatUUID(t), nil
+	case []byte:
+		if len(t) != 16 {
+			return nil, errors.New("invalid size")
+		}
+		var u [16]byte
+		copy(u[:], t)
+		return formatUUID(u), nil
+	}
+	return nil, errors.New("invalid type")
+}
+
+func (v UUID) allowed(u [16]byte) bool {
+	if len(v.Versions) == 0 {
+		return true
+	}
+	// Versions are only defined for the RFC 4122 variant.
+	if u[8]&0xc0 != 0x80 {
+		return false
+	}
+	version := int(u[6] >> 4)
+	for _, allowed := range v.Versions {
+		if version == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (v UUID) error() error {
+	if len(v.Versions) == 0 {
+		return errors.New("not a valid UUID")
+	}
+	versions := make([]string, 0, len(v.Versions))
+	for _, version := range v.Versions {
+		versions = append(versions, fmt.Sprintf("v%d", version))
+	}
+	return fmt.Errorf("not a valid UUID (%s)", strings.Join(versions, ", "))
+}
+
+// parseUUID parses the hyphenated or 32 hexadecimal digits form of an UUID,
+// optionally enclosed in braces.
+func parseUUID(s string) (u [16]byte, err error) {
+	if len(s) > 2 && s[0] == '{' && s[len(s)-1] == '}' {
+		s = s[1 : len(s)-1]
+	}
+	var h string
+	switch {
+	case len(s) == 32:
+		h = s
+	case len(s) == 36 && s[8] == '-' && s[13] == '-' && s[18] == '-' && s[23] == '-':
+		h = s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	default:
+		return u, errors.New("invalid UUID format")
+	}
+	if _, err = hex.Decode(u[:], []byte(h)); err != nil {
+		return u, errors.New("invalid UUID format")
+	}
+	return u, nil
+}
+
+// formatUUID returns the lowercase canonical form of u.
+func formatUUID(u [16]byte) string {
+	h := hex.EncodeToString(u[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
@@ -0,0 +1,199 @@
+package schema_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rs/rest-layer/schema"
+)
+
+func TestUUIDCompile(t *testing.T) {
+	cases := []referenceCompilerTestCase{
+		{
+			Name:     "{Versions:[1,4,7]}",
+			Compiler: &schema.UUID{Versions: []int{1, 4, 7}},
+		},
+		{
+			Name:     "{Versions:[0]}",
+			Compiler: &schema.UUID{Versions: []int{0}},
+			Error:    "unsupported UUID version: 0",
+		},
+		{
+			Name:     "{Versions:[4,9]}",
+			Compiler: &schema.UUID{Versions: []int{4, 9}},
+			Error:    "unsupported UUID version: 9",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestUUIDValidate(t *testing.T) {
+	cases := []fieldValidatorTestCase{
+		{
+			Name:      `{}.Validate(v4)`,
+			Validator: &schema.UUID{},
+			Input:     "6BA7B810-9DAD-41D1-80B4-00C04FD430C8",
+			Expect:    "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+		},
+		{
+			Name:      `{Versions:[1]}.Validate(v1)`,
+			Validator: &schema.UUID{Versions: []int{1}},
+			Input:     "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			Expect:    "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		},
+		{
+			Name:      `{}.Validate(braces)`,
+			Validator: &schema.UUID{},
+			Input:     "{6BA7B810-9DAD-41D1-80B4-00C04FD430C8}",
+			Expect:    "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+		},
+		{
+			Name:      `{}.Validate(no hyphens)`,
+			Validator: &schema.UUID{},
+			Input:     "6ba7b8109dad41d180b400c04fd430c8",
+			Expect:    "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+		},
+		{
+			Name:      `{}.Validate(braces, no hyphens)`,
+			Validator: &schema.UUID{},
+			Input:     "{6ba7b8109dad41d180b400c04fd430c8}",
+			Expect:    "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+		},
+		{
+			Name:      `{}.Validate(uppercase)`,
+			Validator: &schema.UUID{},
+			Input:     "6BA7B810-9DAD-41D1-80B4-00C04FD430C8",
+			Expect:    "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+		},
+		{
+			Name:      `{Versions:[4]}.Validate(v4)`,
+			Validator: &schema.UUID{Versions: []int{4}},
+			Input:     "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+			Expect:    "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+		},
+		{
+			Name:      `{Versions:[4]}.Validate(v1)`,
+			Validator: &schema.UUID{Versions: []int{4}},
+			Input:     "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			Error:     "not a valid UUID (v4)",
+		},
+		{
+			Name:      `{Versions:[1,5]}.Validate(v4)`,
+			Validator: &schema.UUID{Versions: []int{1, 5}},
+			Input:     "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+			Error:     "not a valid UUID (v1, v5)",
+		},
+		{
+			Name:      `{Versions:[4]}.Validate(invalid variant)`,
+			Validator: &schema.UUID{Versions: []int{4}},
+			Input:     "6ba7b810-9dad-41d1-c0b4-00c04fd430c8",
+			Error:     "not a valid UUID (v4)",
+		},
+		{
+			Name:      `{}.Validate(malformed)`,
+			Validator: &schema.UUID{},
+			Input:     "6ba7b810-9dad-41d1-80b4-00c04fd430cz",
+			Error:     "not a valid UUID",
+		},
+		{
+			Name:      `{}.Validate(misplaced hyphens)`,
+			Validator: &schema.UUID{},
+			Input:     "6ba7b8109-dad-41d1-80b4-00c04fd430c8",
+			Error:     "not a valid UUID",
+		},
+		{
+			Name:      `{}.Validate(unbalanced braces)`,
+			Validator: &schema.UUID{},
+			Input:     "{6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+			Error:     "not a valid UUID",
+		},
+		{
+			Name:      `{}.Validate(empty)`,
+			Validator: &schema.UUID{},
+			Input:     "",
+			Error:     "not a valid UUID",
+		},
+		{
+			Name:      `{}.Validate(int)`,
+			Validator: &schema.UUID{},
+			Input:     1,
+			Error:     "invalid type",
+		},
+		{
+			Name:      `{StoreBinary:true}.Validate(v4)`,
+			Validator: &schema.UUID{StoreBinary: true},
+			Input:     "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+			Expect:    []byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x41, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8},
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestUUIDSerialize(t *testing.T) {
+	cases := []fieldSerializerTestCase{
+		{
+			Name:       `Serialize(string)`,
+			Serializer: &schema.UUID{},
+			Input:      "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+			Expect:     "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+		},
+		{
+			Name:       `Serialize([]byte)`,
+			Serializer: &schema.UUID{StoreBinary: true},
+			Input:      []byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x41, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8},
+			Expect:     "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+		},
+		{
+			Name:       `Serialize([16]byte)`,
+			Serializer: &schema.UUID{StoreBinary: true},
+			Input:      [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x41, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8},
+			Expect:     "6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+		},
+		{
+			Name:       `Serialize(short []byte)`,
+			Serializer: &schema.UUID{StoreBinary: true},
+			Input:      []byte{0x6b, 0xa7},
+			Error:      "invalid size",
+		},
+		{
+			Name:       `Serialize(int)`,
+			Serializer: &schema.UUID{},
+			Input:      1,
+			Error:      "invalid type",
+		},
+	}
+	for i := range cases {
+		cases[i].Run(t)
+	}
+}
+
+func TestUUIDSchemaValidate(t *testing.T) {
+	s := schema.Schema{
+		Fields: schema.Fields{
+			"id": {Validator: &schema.UUID{Versions: []int{4}}},
+		},
+	}
+	assert.NoError(t, s.Compile(nil))
+	_, errs := s.Validate(map[string]interface{}{"id": "foo"}, map[string]interface{}{})
+	assert.Equal(t, map[string][]interface{}{
+		"id": {schema.ValidationError{Code: schema.CodeValidator, Message: "not a valid UUID (v4)", Field: "id"}},
+	}, errs)
+}
+
+func TestUUIDGenerateFunc(t *testing.T) {
+	v := schema.UUID{Versions: []int{4}}
+	id := v.GenerateFunc(context.Background())
+	got, err := v.Validate(id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, got)
+	assert.NotEqual(t, id, v.GenerateFunc(context.Background()))
+
+	b := schema.UUID{StoreBinary: true}.GenerateFunc(context.Background())
+	assert.Len(t, b, 16)
+}